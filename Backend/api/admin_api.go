@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSchedulerStatus reports the background scheduler state.
+// GET /admin/scheduler
+func GetSchedulerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"scheduler": service.GetSchedulerStatus()})
+}
+
+// StartScheduler resumes the background scheduler with its current settings.
+// POST /admin/scheduler/start
+func StartScheduler(c *gin.Context) {
+	if err := service.ResumeScheduler(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scheduler": service.GetSchedulerStatus()})
+}
+
+// StopScheduler pauses the background scheduler.
+// POST /admin/scheduler/stop
+func StopScheduler(c *gin.Context) {
+	if err := service.StopScheduler(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scheduler": service.GetSchedulerStatus()})
+}
+
+// RunScheduler runs one scheduler pass immediately.
+// POST /admin/scheduler/run
+func RunScheduler(c *gin.Context) {
+	result, err := service.RunSchedulerOnce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
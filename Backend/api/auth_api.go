@@ -42,43 +42,162 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, roleID, err := service.LoginUserWithRole(input)
+	tokens, roleID, err := service.LoginUserWithRole(input)
 	if err != nil {
+		if err.Error() == "email not verified" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"role_id": roleID,
+		"message":       "Login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"role_id":       roleID,
 	})
 }
 
-// GetProfile handles GET /auth/profile by manually verifying the JWT
-func GetProfile(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+// RefreshToken exchanges a refresh token for a new access token and refresh token.
+// The presented refresh token stops working. POST /auth/refresh
+func RefreshToken(c *gin.Context) {
+	var input model.RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide a refresh_token"})
+		return
+	}
+
+	tokens, err := service.RefreshSession(input.RefreshToken)
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout ends the session of a refresh token; all_devices ends every session of the user.
+// Access tokens already issued expire on their own. POST /auth/logout
+func Logout(c *gin.Context) {
+	var input model.LogoutInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide a refresh_token"})
 		return
 	}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Malformed token. Please use 'Bearer <token>' format"})
+	if err := service.Logout(input.RefreshToken, input.AllDevices); err != nil {
+		writeSessionError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
 
-	userID, err := service.ExtractUserIDFromToken(tokenString)
+// ForgotPassword mails a password reset link. It answers the same whether or not the email has an account.
+// POST /auth/password/forgot
+func ForgotPassword(c *gin.Context) {
+	var input model.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide a valid email"})
+		return
+	}
+
+	if err := service.RequestPasswordReset(input.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for this email, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password with a mailed reset token and signs the user out everywhere.
+// POST /auth/password/reset
+func ResetPassword(c *gin.Context) {
+	var input model.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.ResetPassword(input.Token, input.NewPassword); err != nil {
+		writeAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// ChangePassword changes the caller's password after checking the current one. Other sessions end;
+// the response carries new tokens for this device. PUT /auth/password
+func ChangePassword(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	var input model.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := service.ChangePassword(userID, input.CurrentPassword, input.NewPassword)
 	if err != nil {
-		errorMessage := err.Error()
-		if strings.Contains(errorMessage, "expired") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired. Please log in again"})
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid security token. Authentication failed"})
-		}
+		writeAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully", "tokens": tokens})
+}
+
+// VerifyEmail confirms the email address of a mailed verification token. POST /auth/email/verify
+func VerifyEmail(c *gin.Context) {
+	var input model.VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.VerifyEmail(input.Token); err != nil {
+		writeAccountError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ResendEmailVerification mails the caller a new verification link. POST /auth/email/verification
+func ResendEmailVerification(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	if err := service.SendEmailVerification(userID); err != nil {
+		writeAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+func writeAccountError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid or expired token", "new password must be different":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "current password is incorrect":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "email already verified":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func writeSessionError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "invalid refresh token", "refresh token has expired":
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetProfile handles GET /auth/profile for the authenticated user.
+func GetProfile(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
 
 	profile, err := service.GetUserProfile(userID)
 	if err != nil {
@@ -94,19 +213,7 @@ func GetProfile(c *gin.Context) {
 }
 
 func UpdateProfile(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-		return
-	}
-
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-	userID, err := service.ExtractUserIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-		return
-	}
+	userID := currentPrincipal(c).UserID
 
 	// Use RawMessage map to distinguish:
 	// - missing key (undefined)
@@ -137,6 +244,7 @@ func UpdateProfile(c *gin.Context) {
 	}
 
 	var patch model.UserProfilePatch
+	var err error
 
 	if patch.Name, err = parsePatchString("name"); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field: name"})
@@ -162,6 +270,10 @@ func UpdateProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field: date_of_birth"})
 		return
 	}
+	if patch.Notes, err = parsePatchString("notes"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field: notes"})
+		return
+	}
 
 	if err := service.UpdateUserProfilePatch(userID, patch); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database update failed: " + err.Error()})
@@ -191,4 +303,11 @@ func DeleteUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User and related data deleted successfully"})
-}
\ No newline at end of file
+}
+
+// GetJWKS publishes the public keys access tokens are signed with. HS256 keys are never published.
+// GET /.well-known/jwks.json
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, service.TokenJWKS())
+}
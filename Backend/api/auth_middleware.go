@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey is the context key Authenticate stores the caller's model.Principal under.
+const principalKey = "principal"
+
+// kioskKey is the context key set when a request was authenticated with the kiosk key instead of a token.
+const kioskKey = "kiosk"
+
+// Authenticate requires a valid bearer token and stores the caller's principal in the context.
+func Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := authenticate(c); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRoles requires a valid bearer token of a caller holding one of roles.
+func RequireRoles(roles ...uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authenticate(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if !principal.HasRole(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient role permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireKioskOrRoles accepts the shared kiosk key in the X-Kiosk-Key header, or else a bearer token
+// of a caller holding one of roles.
+func RequireKioskOrRoles(roles ...uint) gin.HandlerFunc {
+	requireRoles := RequireRoles(roles...)
+	return func(c *gin.Context) {
+		key := c.GetHeader(KioskKeyHeader)
+		if key == "" {
+			requireRoles(c)
+			return
+		}
+		if !service.ValidKioskKey(key) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid kiosk key"})
+			return
+		}
+		c.Set(kioskKey, true)
+		c.Next()
+	}
+}
+
+// authenticate returns the request's principal, parsing the bearer token the first time it is needed.
+func authenticate(c *gin.Context) (model.Principal, error) {
+	if principal, ok := c.Get(principalKey); ok {
+		return principal.(model.Principal), nil
+	}
+
+	tokenString, err := bearerToken(c)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	principal, err := service.ParseAccessToken(tokenString)
+	if err != nil {
+		return model.Principal{}, errors.New("invalid or expired token")
+	}
+	c.Set(principalKey, *principal)
+	return *principal, nil
+}
+
+func bearerToken(c *gin.Context) (string, error) {
+	auth := strings.TrimSpace(c.GetHeader("Authorization"))
+	if auth == "" {
+		return "", errors.New("missing authorization header")
+	}
+	const bearer = "Bearer "
+	if !strings.HasPrefix(auth, bearer) {
+		return "", errors.New("invalid authorization header")
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(auth, bearer))
+	if token == "" {
+		return "", errors.New("invalid authorization header")
+	}
+	return token, nil
+}
+
+// currentPrincipal returns the caller stored by the route's auth middleware. It panics on routes
+// registered without one, so a handler cannot silently run unauthenticated.
+func currentPrincipal(c *gin.Context) model.Principal {
+	return c.MustGet(principalKey).(model.Principal)
+}
+
+// isKioskRequest reports whether RequireKioskOrRoles admitted the request with the kiosk key.
+func isKioskRequest(c *gin.Context) bool {
+	return c.GetBool(kioskKey)
+}
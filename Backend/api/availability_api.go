@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyAvailability returns the signed-in instructor's weekly availability windows.
+// GET /instructor/availability
+func GetMyAvailability(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	windows, err := service.GetInstructorAvailability(instructorID)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"availability": windows})
+}
+
+// SetMyAvailability replaces the signed-in instructor's weekly availability windows.
+// PUT /instructor/availability
+func SetMyAvailability(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	var input model.InstructorAvailabilityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	windows, err := service.SetInstructorAvailability(instructorID, input)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"availability": windows})
+}
+
+// ManagerSetInstructorAvailability replaces an instructor's weekly availability windows.
+// PUT /manager/instructors/:id/availability (manager only)
+func ManagerSetInstructorAvailability(c *gin.Context) {
+	instructorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instructor ID"})
+		return
+	}
+
+	var input model.InstructorAvailabilityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	windows, err := service.ManagerSetInstructorAvailability(uint(instructorID), input)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"availability": windows})
+}
+
+// ManagerGetInstructorSchedule returns an instructor's availability, courses, upcoming sessions and conflicts.
+// GET /manager/instructors/:id/schedule (manager only)
+func ManagerGetInstructorSchedule(c *gin.Context) {
+	instructorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instructor ID"})
+		return
+	}
+
+	schedule, err := service.GetInstructorSchedule(uint(instructorID))
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedule": schedule})
+}
+
+// writeScheduleConflict answers 409 with the conflict details when err is a schedule conflict.
+func writeScheduleConflict(c *gin.Context, err error) bool {
+	var conflict *service.ScheduleConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": conflict.Conflicts})
+	return true
+}
+
+func writeAvailabilityError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "instructor not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid weekday", "end_time must be after start_time",
+		"invalid start_time, expected HH:MM or HH:MM:SS", "invalid end_time, expected HH:MM or HH:MM:SS":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
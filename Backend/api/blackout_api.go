@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListBlackoutDates returns upcoming blackout dates. Public endpoint.
+// GET /blackouts?from=YYYY-MM-DD
+func ListBlackoutDates(c *gin.Context) {
+	blackouts, err := service.ListBlackoutDates(c.Query("from"))
+	if err != nil {
+		writeBlackoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"blackouts": blackouts})
+}
+
+// ManagerCreateBlackoutDate adds a blackout date and cancels the sessions scheduled on it.
+// POST /blackouts (manager only)
+func ManagerCreateBlackoutDate(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	var input model.BlackoutDateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	blackout, canceled, err := service.ManagerCreateBlackoutDate(managerID, input)
+	if err != nil {
+		writeBlackoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"blackout": blackout, "canceled_sessions": canceled})
+}
+
+// ManagerUpdateBlackoutDate changes a blackout date and cancels the sessions scheduled on the new date.
+// PUT /blackouts/:id (manager only)
+func ManagerUpdateBlackoutDate(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	blackoutID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blackout ID"})
+		return
+	}
+
+	var input model.BlackoutDateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	blackout, canceled, err := service.ManagerUpdateBlackoutDate(managerID, uint(blackoutID), input)
+	if err != nil {
+		writeBlackoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"blackout": blackout, "canceled_sessions": canceled})
+}
+
+// ManagerDeleteBlackoutDate removes a blackout date.
+// DELETE /blackouts/:id (manager only)
+func ManagerDeleteBlackoutDate(c *gin.Context) {
+	blackoutID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blackout ID"})
+		return
+	}
+
+	if err := service.ManagerDeleteBlackoutDate(uint(blackoutID)); err != nil {
+		writeBlackoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Blackout date deleted successfully"})
+}
+
+func writeBlackoutError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "blackout date not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid date, expected YYYY-MM-DD", "blackout date is in the past":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "blackout date already exists":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListBookingPolicies returns all booking policies. Public endpoint.
+// GET /booking-policies
+func ListBookingPolicies(c *gin.Context) {
+	policies, err := service.ListBookingPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"booking_policies": policies, "default": service.DefaultBookingPolicy})
+}
+
+// GetBookingPolicy returns one booking policy. Public endpoint.
+// GET /booking-policies/:id
+func GetBookingPolicy(c *gin.Context) {
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking policy ID"})
+		return
+	}
+
+	policy, err := service.GetBookingPolicy(uint(policyID))
+	if err != nil {
+		writeBookingPolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"booking_policy": policy})
+}
+
+// ManagerCreateBookingPolicy creates a booking policy.
+// POST /booking-policies (manager only)
+func ManagerCreateBookingPolicy(c *gin.Context) {
+	var input model.BookingPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := service.ManagerCreateBookingPolicy(input)
+	if err != nil {
+		writeBookingPolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"booking_policy": policy})
+}
+
+// ManagerUpdateBookingPolicy updates a booking policy.
+// PUT /booking-policies/:id (manager only)
+func ManagerUpdateBookingPolicy(c *gin.Context) {
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking policy ID"})
+		return
+	}
+
+	var input model.BookingPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := service.ManagerUpdateBookingPolicy(uint(policyID), input)
+	if err != nil {
+		writeBookingPolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"booking_policy": policy})
+}
+
+// ManagerDeleteBookingPolicy deletes a booking policy.
+// DELETE /booking-policies/:id (manager only)
+func ManagerDeleteBookingPolicy(c *gin.Context) {
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking policy ID"})
+		return
+	}
+
+	if err := service.ManagerDeleteBookingPolicy(uint(policyID)); err != nil {
+		writeBookingPolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Booking policy deleted successfully"})
+}
+
+// writeBookingPolicyRefusal answers with the error code when err is a booking or cancellation
+// refused by a booking policy, a strike suspension or a missing membership. Window errors are 400,
+// missing memberships 402, suspensions 403, cutoffs and limits 409.
+func writeBookingPolicyRefusal(c *gin.Context, err error) bool {
+	var refusal *service.BookingPolicyError
+	if !errors.As(err, &refusal) {
+		return false
+	}
+
+	status := http.StatusConflict
+	switch refusal.Code {
+	case service.BookingCodeNotOpen, service.BookingCodeClosed:
+		status = http.StatusBadRequest
+	case service.BookingCodeSuspended:
+		status = http.StatusForbidden
+	case service.BookingCodeMembershipRequired:
+		status = http.StatusPaymentRequired
+	}
+	c.JSON(status, gin.H{"error": refusal.Message, "code": refusal.Code})
+	return true
+}
+
+func writeBookingPolicyError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "booking policy not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "booking policy name is required", "booking must open before it closes":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "booking policy name already exists", "category already has a booking policy":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KioskKeyHeader carries the shared key of a check-in kiosk.
+const KioskKeyHeader = "X-Kiosk-Key"
+
+// GetMyCheckInCode returns a short-lived code for the caller's seat in a session, to show as a QR code.
+// GET /classes/sessions/:session_id/check-in-code
+func GetMyCheckInCode(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	code, err := service.IssueCheckInCode(userID, uint(sessionID))
+	if err != nil {
+		writeCheckInError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"check_in": code})
+}
+
+// CheckIn validates a scanned check-in code and records the member's arrival.
+// Kiosks authenticate with the X-Kiosk-Key header, staff with an instructor or manager token.
+// POST /check-in
+func CheckIn(c *gin.Context) {
+	var staffID *uint
+	instructor := false
+	if !isKioskRequest(c) {
+		principal := currentPrincipal(c)
+		staffID = &principal.UserID
+		instructor = principal.RoleID == model.RoleInstructor
+	}
+
+	var input model.CheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	enrollment, err := service.CheckIn(input.Token, staffID, instructor)
+	if err != nil {
+		writeCheckInError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enrollment": enrollment})
+}
+
+func writeCheckInError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "enrollment not found", "class not found", "instructor not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "invalid check-in code", "check-in code has expired":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "forbidden":
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: you do not teach this session"})
+	case "already checked in", "enrollment is not seated", "session is not open for check-in",
+		"check-in is not open yet", "check-in has closed":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
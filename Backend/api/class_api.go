@@ -1,7 +1,6 @@
 package api
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -20,20 +19,18 @@ func RegisterClass(c *gin.Context) {
 		return
 	}
 
-	userID, err := requireRegisterPermission(c)
-	if err != nil {
-		return
-	}
+	userID := currentPrincipal(c).UserID
 
 	if err := service.RegisterClass(userID, input.CourseID); err != nil {
+		if writeBookingPolicyRefusal(c, err) {
+			return
+		}
 		switch err.Error() {
 		case "user not found", "class not found", "no upcoming session found for this class":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		case "enrollment already exists", "class is full", "class schedule overlaps with an existing enrolled class":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		case "invalid class schedule",
-			"registration closed: class has already started",
-			"enrollment opens 25 hours before class start.":
+		case "invalid class schedule":
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -52,12 +49,71 @@ func DropClass(c *gin.Context) {
 		return
 	}
 
-	userID, err := requireRegisterPermission(c)
+	userID := currentPrincipal(c).UserID
+
+	if err := service.DropClass(userID, input.CourseID); err != nil {
+		if writeBookingPolicyRefusal(c, err) {
+			return
+		}
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Class unenrolled successfully"})
+}
+
+// RegisterSession enrolls the authenticated user in a specific class session.
+// POST /classes/sessions/:session_id/register
+func RegisterSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
 		return
 	}
 
-	if err := service.DropClass(userID, input.CourseID); err != nil {
+	userID := currentPrincipal(c).UserID
+
+	if err := service.RegisterSession(userID, uint(sessionID)); err != nil {
+		if writeBookingPolicyRefusal(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "user not found", "session not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "enrollment already exists", "class is full",
+			"class schedule overlaps with an existing enrolled class",
+			"session is not open for registration":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "invalid class schedule":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Class enrolled successfully"})
+}
+
+// DropSession removes the authenticated user's enrollment from a specific class session.
+// POST /classes/sessions/:session_id/drop
+func DropSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	userID := currentPrincipal(c).UserID
+
+	if err := service.DropSession(userID, uint(sessionID)); err != nil {
+		if writeBookingPolicyRefusal(c, err) {
+			return
+		}
 		if err.Error() == "enrollment not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -69,15 +125,105 @@ func DropClass(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Class unenrolled successfully"})
 }
 
-// ListClasses returns all courses. Public endpoint.
+// ListClassSessions returns upcoming sessions of a course with spot counts. Public endpoint.
+// GET /classes/:id/sessions
+func ListClassSessions(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	sessions, err := service.ListClassSessions(uint(classID))
+	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// ListClasses returns the class catalog. Public endpoint.
+// GET /classes?q=&category=&weekday=&instructor_id=&room_id=&start_after=&end_before=&open_only=&sort=&page=&limit=
 func ListClasses(c *gin.Context) {
-	classes, err := service.ListClasses()
+	filter, ok := bindClassFilter(c)
+	if !ok {
+		return
+	}
+
+	classes, total, page, limit, totalPages, err := service.ListClasses(filter)
 	if err != nil {
+		if err.Error() == "invalid sort" || err.Error() == "invalid weekday" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"classes": classes})
+	c.JSON(http.StatusOK, gin.H{
+		"classes":     classes,
+		"page":        page,
+		"limit":       limit,
+		"total":       total,
+		"total_pages": totalPages,
+	})
+}
+
+// bindClassFilter reads the catalog query parameters, answering 400 on malformed values.
+func bindClassFilter(c *gin.Context) (model.ClassFilter, bool) {
+	filter := model.ClassFilter{
+		Category: c.Query("category"),
+		Weekday:  c.Query("weekday"),
+		Search:   c.Query("q"),
+		Sort:     c.Query("sort"),
+	}
+
+	ids := map[string]**uint{"room_id": &filter.RoomID, "instructor_id": &filter.InstructorID}
+	for param, target := range ids {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+			return filter, false
+		}
+		parsed := uint(id)
+		*target = &parsed
+	}
+
+	times := map[string]**model.TimeOnly{"start_after": &filter.StartAfter, "end_before": &filter.EndBefore}
+	for param, target := range times {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := model.ParseTimeOnly(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + ", expected HH:MM or HH:MM:SS"})
+			return filter, false
+		}
+		*target = &parsed
+	}
+
+	if value := c.Query("open_only"); value != "" {
+		openOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid open_only"})
+			return filter, false
+		}
+		filter.OnlyOpen = openOnly
+	}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "0"))
+	return filter, true
 }
 
 // ListCategories returns all distinct course categories. Public endpoint.
@@ -115,10 +261,6 @@ func GetClass(c *gin.Context) {
 
 // ListClassEnrollments returns all enrollments for a class.
 func ListClassEnrollments(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	classIDStr := c.Param("id")
 	classID, err := strconv.ParseUint(classIDStr, 10, 32)
 	if err != nil {
@@ -148,11 +290,7 @@ func GetUserEnrolledClasses(c *gin.Context) {
 		return
 	}
 
-	authenticatedUserID, err := getUserIDFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
+	authenticatedUserID := currentPrincipal(c).UserID
 
 	if authenticatedUserID != uint(userID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: you can only view your own enrollments"})
@@ -181,11 +319,7 @@ func GetUserAnalytics(c *gin.Context) {
 		return
 	}
 
-	authUserID, err := getUserIDFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
+	authUserID := currentPrincipal(c).UserID
 
 	if uint(userID) != authUserID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
@@ -206,64 +340,3 @@ func GetUserAnalytics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"analytics": analytics})
 }
-
-func getUserIDFromAuthHeader(c *gin.Context) (uint, error) {
-	authorization := strings.TrimSpace(c.GetHeader("Authorization"))
-	if authorization == "" {
-		return 0, errors.New("missing authorization header")
-	}
-
-	const bearerPrefix = "Bearer "
-	if !strings.HasPrefix(authorization, bearerPrefix) {
-		return 0, errors.New("invalid authorization header")
-	}
-
-	tokenString := strings.TrimSpace(strings.TrimPrefix(authorization, bearerPrefix))
-	if tokenString == "" {
-		return 0, errors.New("invalid authorization header")
-	}
-
-	return service.ExtractUserIDFromToken(tokenString)
-}
-
-func getRoleIDFromAuthHeader(c *gin.Context) (uint, error) {
-	authorization := strings.TrimSpace(c.GetHeader("Authorization"))
-	if authorization == "" {
-		return 0, errors.New("missing authorization header")
-	}
-
-	const bearerPrefix = "Bearer "
-	if !strings.HasPrefix(authorization, bearerPrefix) {
-		return 0, errors.New("invalid authorization header")
-	}
-
-	tokenString := strings.TrimSpace(strings.TrimPrefix(authorization, bearerPrefix))
-	if tokenString == "" {
-		return 0, errors.New("invalid authorization header")
-	}
-
-	return service.GetRoleIDFromToken(tokenString)
-}
-
-// register/drop permission check
-func requireRegisterPermission(c *gin.Context) (uint, error) {
-	userID, err := getUserIDFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return 0, err
-	}
-
-	roleID, err := getRoleIDFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return 0, err
-	}
-
-	// role_id 1(User)/2(SuperManager)/3(Manager) are allowed
-	if roleID != 1 && roleID != 2 && roleID != 3 {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient role permissions"})
-		return 0, errors.New("forbidden")
-	}
-
-	return userID, nil
-}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCourseInstructors returns the lead and assistant instructors of a class.
+// GET /classes/:id/instructors
+func ListCourseInstructors(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	instructors, err := service.ListCourseInstructors(uint(courseID))
+	if err != nil {
+		writeCourseInstructorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructors": instructors})
+}
+
+// ManagerSetCourseInstructor assigns an instructor to a class as lead or assistant.
+// PUT /classes/:id/instructors (manager only)
+func ManagerSetCourseInstructor(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	var input model.CourseInstructorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := service.ManagerSetCourseInstructor(uint(courseID), input)
+	if err != nil {
+		if writeScheduleConflict(c, err) {
+			return
+		}
+		writeCourseInstructorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructor": assignment})
+}
+
+// ManagerRemoveCourseInstructor unassigns an instructor from a class.
+// DELETE /classes/:id/instructors/:instructor_id (manager only)
+func ManagerRemoveCourseInstructor(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	instructorID, err := strconv.ParseUint(c.Param("instructor_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instructor ID"})
+		return
+	}
+
+	if err := service.ManagerRemoveCourseInstructor(uint(courseID), uint(instructorID)); err != nil {
+		writeCourseInstructorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Instructor removed from class"})
+}
+
+// ManagerSetSessionSubstitute assigns a substitute instructor to one session.
+// PUT /classes/sessions/:session_id/substitute (manager only)
+func ManagerSetSessionSubstitute(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var input model.SessionSubstituteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := service.ManagerSetSessionSubstitute(uint(sessionID), input)
+	if err != nil {
+		writeCourseInstructorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+// ManagerClearSessionSubstitute removes the substitute instructor of one session.
+// DELETE /classes/sessions/:session_id/substitute (manager only)
+func ManagerClearSessionSubstitute(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := service.ManagerClearSessionSubstitute(uint(sessionID)); err != nil {
+		writeCourseInstructorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Substitute removed"})
+}
+
+func writeCourseInstructorError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "class not found", "session not found", "instructor is not assigned to this class", "session has no substitute":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "instructor not found":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "session is not scheduled", "instructor already teaches this class":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,62 +1,29 @@
 package api
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
+	"my-course-backend/model"
 	"my-course-backend/service"
 
 	"github.com/gin-gonic/gin"
 )
 
-func requireInstructorRole(c *gin.Context) (uint, error) {
-	tokenString, err := getTokenStringFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return 0, err
-	}
-
-	roleID, err := service.GetRoleIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-		return 0, err
-	}
-
-	if roleID != 4 {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: instructor role required"})
-		return 0, errors.New("forbidden")
-	}
-
-	userID, err := service.ExtractUserIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-		return 0, err
-	}
-
-	return userID, nil
-}
-
 func InstructorListCourses(c *gin.Context) {
-	instructorID, err := requireInstructorRole(c)
-	if err != nil {
-		return
-	}
+	instructorID := currentPrincipal(c).UserID
 
-	courses, err := service.ListInstructorCourses(instructorID)
+	courses, sessions, err := service.ListInstructorCourses(instructorID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"courses": courses})
+	c.JSON(http.StatusOK, gin.H{"courses": courses, "substitute_sessions": sessions})
 }
 
 func InstructorListCourseEnrollments(c *gin.Context) {
-	instructorID, err := requireInstructorRole(c)
-	if err != nil {
-		return
-	}
+	instructorID := currentPrincipal(c).UserID
 
 	courseIDStr := c.Param("id")
 	courseID64, err := strconv.ParseUint(courseIDStr, 10, 32)
@@ -84,14 +51,13 @@ func InstructorListCourseEnrollments(c *gin.Context) {
 
 type InstructorAddEnrollmentInput struct {
 	UserID uint `json:"user_id" binding:"required"`
+	// SessionID books a specific session instead of the course's next scheduled one.
+	SessionID *uint `json:"session_id"`
 }
 
 // InstructorAddEnrollment enrolls a user into the instructor's course.
 func InstructorAddEnrollment(c *gin.Context) {
-	instructorID, err := requireInstructorRole(c)
-	if err != nil {
-		return
-	}
+	instructorID := currentPrincipal(c).UserID
 
 	courseIDStr := c.Param("id")
 	courseID64, err := strconv.ParseUint(courseIDStr, 10, 32)
@@ -106,13 +72,18 @@ func InstructorAddEnrollment(c *gin.Context) {
 		return
 	}
 
-	if err := service.InstructorAddEnrollment(instructorID, input.UserID, uint(courseID64)); err != nil {
+	if input.SessionID != nil {
+		err = service.InstructorAddSessionEnrollment(instructorID, input.UserID, uint(courseID64), *input.SessionID)
+	} else {
+		err = service.InstructorAddEnrollment(instructorID, input.UserID, uint(courseID64))
+	}
+	if err != nil {
 		switch err.Error() {
 		case "forbidden":
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-		case "user not found", "class not found", "no upcoming session found for this class":
+		case "user not found", "class not found", "session not found", "no upcoming session found for this class":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "enrollment already exists", "class is full":
+		case "enrollment already exists", "class is full", "session is not open for registration":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -124,15 +95,13 @@ func InstructorAddEnrollment(c *gin.Context) {
 }
 
 type InstructorUpdateStatusInput struct {
-	UserID uint   `json:"user_id" binding:"required"`
-	Status string `json:"status" binding:"required"`
+	UserID    uint   `json:"user_id" binding:"required"`
+	Status    string `json:"status" binding:"required"`
+	SessionID *uint  `json:"session_id"`
 }
 
 func InstructorUpdateEnrollmentStatus(c *gin.Context) {
-	instructorID, err := requireInstructorRole(c)
-	if err != nil {
-		return
-	}
+	instructorID := currentPrincipal(c).UserID
 
 	courseIDStr := c.Param("id")
 	courseID64, err := strconv.ParseUint(courseIDStr, 10, 32)
@@ -147,7 +116,7 @@ func InstructorUpdateEnrollmentStatus(c *gin.Context) {
 		return
 	}
 
-	if err := service.UpdateEnrollmentStatusByInstructor(instructorID, uint(courseID64), input.UserID, input.Status); err != nil {
+	if err := service.UpdateEnrollmentStatusByInstructor(instructorID, uint(courseID64), input.UserID, input.Status, input.SessionID); err != nil {
 		if err.Error() == "forbidden" {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
@@ -161,4 +130,39 @@ func InstructorUpdateEnrollmentStatus(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "status updated"})
-}
\ No newline at end of file
+}
+
+// InstructorMarkSessionAttendance records attendance for several members of one session and reports each mark's outcome.
+// PUT /instructor/sessions/:session_id/attendance
+func InstructorMarkSessionAttendance(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var input model.SessionAttendanceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := service.InstructorMarkSessionAttendance(instructorID, uint(sessionID), input.Marks)
+	if err != nil {
+		switch err.Error() {
+		case "forbidden":
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case "session not found", "class not found", "instructor not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "session is canceled", "enrollment changed during update":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyInstructorProfile returns the signed-in instructor's profile.
+// GET /instructor/profile
+func GetMyInstructorProfile(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	profile, err := service.GetMyInstructorProfile(instructorID)
+	if err != nil {
+		if err.Error() == "instructor not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructor": profile})
+}
+
+// UpdateMyInstructorProfile edits the signed-in instructor's bio, photo, specialties and certifications.
+// PATCH /instructor/profile
+func UpdateMyInstructorProfile(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	var input model.InstructorProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := service.UpdateMyInstructorProfile(instructorID, input)
+	if err != nil {
+		switch err.Error() {
+		case "instructor not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "too many specialties", "too many certifications":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructor": profile})
+}
+
+// ListInstructors returns the public instructor directory.
+// GET /instructors
+func ListInstructors(c *gin.Context) {
+	instructors, err := service.ListInstructorDirectory()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructors": instructors})
+}
+
+// GetInstructor returns one instructor's public profile with upcoming courses and sessions.
+// GET /instructors/:id
+func GetInstructor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instructor ID"})
+		return
+	}
+
+	instructor, err := service.GetInstructorDirectoryEntry(uint(id))
+	if err != nil {
+		if err.Error() == "instructor not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"instructor": instructor})
+}
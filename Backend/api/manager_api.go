@@ -1,7 +1,6 @@
 package api
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 	"my-course-backend/model"
@@ -10,33 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// manager role required: role_id == 2 or 3
-func requireManagerRole(c *gin.Context) error {
-	tokenString, err := getTokenStringFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return err
-	}
-
-	roleID, err := service.GetRoleIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-		return err
-	}
-
-	if roleID != 2 && roleID != 3 {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: manager role required"})
-		return errors.New("forbidden")
-	}
-	return nil
-}
-
 // POST /classes (manager only)
 func ManagerCreateClass(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	var input service.CourseUpsertInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -45,6 +19,21 @@ func ManagerCreateClass(c *gin.Context) {
 
 	class, err := service.ManagerCreateCourse(input)
 	if err != nil {
+		if writeScheduleConflict(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "instructor not found", "room not found", "booking policy not found", "class capacity exceeds room capacity",
+			"start_time and end_time are required", "invalid weekday", "each weekday may only have one slot",
+			"end_time must be after start_time", "invalid start_time, expected HH:MM or HH:MM:SS",
+			"invalid end_time, expected HH:MM or HH:MM:SS", "invalid starts_on, expected YYYY-MM-DD",
+			"invalid ends_on, expected YYYY-MM-DD", "ends_on must not be before starts_on":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "room is already booked at that time":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -53,10 +42,6 @@ func ManagerCreateClass(c *gin.Context) {
 
 // PUT /classes/:id (manager only)
 func ManagerUpdateClass(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	idStr := c.Param("id")
 	id64, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -72,10 +57,25 @@ func ManagerUpdateClass(c *gin.Context) {
 
 	class, err := service.ManagerUpdateCourse(uint(id64), input)
 	if err != nil {
+		if writeScheduleConflict(c, err) {
+			return
+		}
 		if err.Error() == "class not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		switch err.Error() {
+		case "instructor not found", "room not found", "booking policy not found", "class capacity exceeds room capacity",
+			"start_time and end_time are required", "invalid weekday", "each weekday may only have one slot",
+			"end_time must be after start_time", "invalid start_time, expected HH:MM or HH:MM:SS",
+			"invalid end_time, expected HH:MM or HH:MM:SS", "invalid starts_on, expected YYYY-MM-DD",
+			"invalid ends_on, expected YYYY-MM-DD", "ends_on must not be before starts_on":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "room is already booked at that time":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -85,10 +85,6 @@ func ManagerUpdateClass(c *gin.Context) {
 
 // DELETE /classes/:id (manager only)
 func ManagerDeleteClass(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	idStr := c.Param("id")
 	id64, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -136,10 +132,6 @@ func ManagerRegister(c *gin.Context) {
 
 // ✅ GET /manager/users?page=1&limit=20
 func ManagerListUsers(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -160,10 +152,6 @@ func ManagerListUsers(c *gin.Context) {
 
 // ✅ GET /manager/users/:id/enrollments
 func ManagerListUserEnrollments(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	idStr := c.Param("id")
 	id64, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -185,15 +173,13 @@ func ManagerListUserEnrollments(c *gin.Context) {
 }
 
 type ManagerAddEnrollmentInput struct {
-	CourseID uint `json:"course_id" binding:"required"`
+	CourseID uint `json:"course_id" binding:"required_without=SessionID"`
+	// SessionID books a specific session instead of the course's next scheduled one.
+	SessionID *uint `json:"session_id"`
 }
 
 // ✅ POST /manager/users/:id/enrollments
 func ManagerAddUserEnrollment(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
-
 	idStr := c.Param("id")
 	id64, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -207,11 +193,16 @@ func ManagerAddUserEnrollment(c *gin.Context) {
 		return
 	}
 
-	if err := service.ManagerAddUserEnrollment(uint(id64), input.CourseID); err != nil {
+	if input.SessionID != nil {
+		err = service.ManagerAddUserSessionEnrollment(uint(id64), *input.SessionID)
+	} else {
+		err = service.ManagerAddUserEnrollment(uint(id64), input.CourseID)
+	}
+	if err != nil {
 		switch err.Error() {
-		case "user not found", "class not found", "no upcoming session found for this class":
+		case "user not found", "class not found", "session not found", "no upcoming session found for this class":
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "enrollment already exists", "class is full":
+		case "enrollment already exists", "class is full", "session is not open for registration":
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -224,9 +215,7 @@ func ManagerAddUserEnrollment(c *gin.Context) {
 
 // ✅ DELETE /manager/users/:id/enrollments/:course_id
 func ManagerDeleteUserEnrollment(c *gin.Context) {
-	if err := requireManagerRole(c); err != nil {
-		return
-	}
+	managerID := currentPrincipal(c).UserID
 
 	userIDStr := c.Param("id")
 	userID64, err := strconv.ParseUint(userIDStr, 10, 32)
@@ -242,7 +231,7 @@ func ManagerDeleteUserEnrollment(c *gin.Context) {
 		return
 	}
 
-	if err := service.ManagerDeleteUserEnrollment(uint(userID64), uint(courseID64)); err != nil {
+	if err := service.ManagerDeleteUserEnrollment(managerID, uint(userID64), uint(courseID64)); err != nil {
 		if err.Error() == "enrollment not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
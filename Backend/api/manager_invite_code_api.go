@@ -11,29 +11,7 @@ import (
 
 // CreateManagerInviteCode handles POST /auth/manager/invite-codes (SuperManager only)
 func CreateManagerInviteCode(c *gin.Context) {
-	tokenString, err := getTokenStringFromAuthHeader(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
-
-	userID, err := service.ExtractUserIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-		return
-	}
-
-	roleID, err := service.GetRoleIDFromToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-		return
-	}
-
-	// Only role_id=2 (SuperManager) can create invite codes
-	if roleID != 2 {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: SuperManager role required"})
-		return
-	}
+	userID := currentPrincipal(c).UserID
 
 	var input model.CreateManagerInviteInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListMembershipPlans returns the plans members can be granted. Public endpoint;
+// ?all=true also lists retired plans.
+// GET /memberships/plans
+func ListMembershipPlans(c *gin.Context) {
+	plans, err := service.ListMembershipPlans(c.Query("all") != "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
+}
+
+// ManagerCreateMembershipPlan creates a membership plan.
+// POST /memberships/plans (manager only)
+func ManagerCreateMembershipPlan(c *gin.Context) {
+	var input model.MembershipPlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := service.ManagerCreateMembershipPlan(input)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"plan": plan})
+}
+
+// ManagerUpdateMembershipPlan updates a membership plan.
+// PUT /memberships/plans/:id (manager only)
+func ManagerUpdateMembershipPlan(c *gin.Context) {
+	planID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan ID"})
+		return
+	}
+
+	var input model.MembershipPlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := service.ManagerUpdateMembershipPlan(uint(planID), input)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plan": plan})
+}
+
+// GetMyMembership returns the caller's credits, subscriptions and ledger.
+// GET /memberships/me
+func GetMyMembership(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	balance, err := service.GetMemberBalance(userID)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"balance": balance})
+}
+
+// ManagerGetMemberBalance returns a member's credits, subscriptions and ledger.
+// GET /manager/users/:id/balance (manager only)
+func ManagerGetMemberBalance(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	balance, err := service.GetMemberBalance(uint(userID))
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"balance": balance})
+}
+
+// ManagerGrantSubscription grants a plan to a member.
+// POST /manager/users/:id/subscriptions (manager only)
+func ManagerGrantSubscription(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var input model.SubscriptionGrantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := service.ManagerGrantSubscription(managerID, uint(userID), input)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"subscription": subscription})
+}
+
+// ManagerCancelSubscription ends a member's subscription today.
+// DELETE /manager/users/:id/subscriptions/:subscription_id (manager only)
+func ManagerCancelSubscription(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	subscriptionID, err := strconv.ParseUint(c.Param("subscription_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription ID"})
+		return
+	}
+
+	subscription, err := service.ManagerCancelSubscription(uint(userID), uint(subscriptionID))
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": subscription})
+}
+
+// ManagerAdjustCredits adds or removes a member's credits with an explanatory note.
+// POST /manager/users/:id/credits (manager only)
+func ManagerAdjustCredits(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var input model.CreditAdjustmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := service.ManagerAdjustCredits(managerID, uint(userID), input)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"entry": entry})
+}
+
+func writeMembershipError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found", "membership plan not found", "subscription not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "membership plan name is required", "unlimited plans do not grant credits",
+		"class packs and drop-ins must grant credits", "invalid plan kind", "invalid starts_on",
+		"delta must not be zero", "note is required", "membership plan is inactive":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "membership plan name already exists", "subscription already canceled", "insufficient credits":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/payments"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCheckout starts the caller's purchase of a membership plan.
+// POST /payments/checkout
+func CreateCheckout(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	var input model.CheckoutInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := service.CreateCheckout(userID, input)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"order": order})
+}
+
+// ListMyPaymentOrders returns the caller's orders.
+// GET /payments/orders
+func ListMyPaymentOrders(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	orders, err := service.ListMyPaymentOrders(userID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+// GetMyPaymentOrder returns one of the caller's orders.
+// GET /payments/orders/:id
+func GetMyPaymentOrder(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := service.GetMyPaymentOrder(userID, uint(orderID))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// ManagerRefundPaymentOrder refunds a paid order and withdraws what it granted.
+// POST /payments/orders/:id/refund (manager only)
+func ManagerRefundPaymentOrder(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := service.ManagerRefundPaymentOrder(managerID, uint(orderID))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// PaymentWebhook ingests a signed webhook from the payment provider. Unauthenticated; the signature is checked.
+// POST /payments/webhooks/:provider
+func PaymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook payload"})
+		return
+	}
+
+	if err := service.HandlePaymentWebhook(c.Param("provider"), payload, c.Request.Header); err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// SimulateFakePayment completes one of the caller's checkouts on the fake provider, as the member paying would.
+// Only routed while the fake provider is enabled for development.
+// POST /payments/fake/intents/:intent_id/pay?outcome=decline
+func SimulateFakePayment(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	if err := service.SimulateFakePayment(userID, c.Param("intent_id"), c.Query("outcome") == "decline"); err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Payment simulated"})
+}
+
+func writePaymentError(c *gin.Context, err error) {
+	var providerErr *service.PaymentProviderError
+	if errors.As(err, &providerErr) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch err.Error() {
+	case "payments are not enabled":
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case "user not found", "membership plan not found", "payment order not found", "payment provider not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "membership plan is inactive", "membership plan is not for sale", "invalid webhook payload":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "only paid orders can be refunded", "payment intent cannot be completed":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRooms returns all rooms. Public endpoint.
+// GET /rooms
+func ListRooms(c *gin.Context) {
+	rooms, err := service.ListRooms()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rooms": rooms})
+}
+
+// GetRoom returns one room. Public endpoint.
+// GET /rooms/:id
+func GetRoom(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	room, err := service.GetRoom(uint(roomID))
+	if err != nil {
+		writeRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// ManagerCreateRoom creates a room.
+// POST /rooms (manager only)
+func ManagerCreateRoom(c *gin.Context) {
+	var input model.RoomInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := service.ManagerCreateRoom(input)
+	if err != nil {
+		writeRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"room": room})
+}
+
+// ManagerUpdateRoom updates a room.
+// PUT /rooms/:id (manager only)
+func ManagerUpdateRoom(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var input model.RoomInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := service.ManagerUpdateRoom(uint(roomID), input)
+	if err != nil {
+		writeRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room": room})
+}
+
+// ManagerDeleteRoom deletes an unused room.
+// DELETE /rooms/:id (manager only)
+func ManagerDeleteRoom(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	if err := service.ManagerDeleteRoom(uint(roomID)); err != nil {
+		writeRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Room deleted successfully"})
+}
+
+// ManagerSetSessionRoom moves one session to another room.
+// PUT /classes/sessions/:session_id/room (manager only)
+func ManagerSetSessionRoom(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var input model.SessionRoomInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := service.ManagerSetSessionRoom(uint(sessionID), input)
+	if err != nil {
+		writeRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+func writeRoomError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "room not found", "session not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "room name is required", "too many equipment items", "room capacity is below a class held in it":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case "room name already exists", "room is in use", "room is already booked at that time",
+		"room is too small for the session's bookings", "session is not scheduled":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
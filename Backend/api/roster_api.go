@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InstructorListCourseSessions lists a course's sessions with their headcounts.
+// GET /instructor/courses/:id/sessions
+func InstructorListCourseSessions(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	sessions, err := service.ListInstructorCourseSessions(instructorID, uint(courseID))
+	if err != nil {
+		writeRosterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// InstructorGetSessionRoster returns a session's roster; ?format=csv downloads it as a spreadsheet.
+// GET /instructor/sessions/:session_id/roster
+func InstructorGetSessionRoster(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	roster, err := service.GetInstructorSessionRoster(instructorID, uint(sessionID))
+	if err != nil {
+		writeRosterError(c, err)
+		return
+	}
+	if format == "csv" {
+		writeRosterCSV(c, roster)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roster": roster})
+}
+
+var rosterCSVHeader = []string{
+	"name", "email", "phone_number", "status", "waitlist_position", "checked_in_at",
+	"attended", "missed", "last_attended_at", "notes",
+}
+
+func writeRosterCSV(c *gin.Context, roster *model.SessionRoster) {
+	filename := fmt.Sprintf("roster-session-%d-%s.csv", roster.Session.ID, roster.Session.SessionDate)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(rosterCSVHeader)
+	for _, entry := range roster.Entries {
+		position := ""
+		if entry.WaitlistPosition != nil {
+			position = strconv.Itoa(*entry.WaitlistPosition)
+		}
+		_ = w.Write([]string{
+			csvCell(entry.Name),
+			csvCell(entry.Email),
+			csvCell(entry.PhoneNumber),
+			entry.Status,
+			position,
+			formatRosterTime(entry.CheckedInAt),
+			strconv.FormatInt(entry.History.Attended, 10),
+			strconv.FormatInt(entry.History.Missed, 10),
+			formatRosterTime(entry.History.LastAttendedAt),
+			csvCell(entry.Notes),
+		})
+	}
+	w.Flush()
+}
+
+// csvCell keeps member-entered text from being read as a formula when the export is opened in a spreadsheet.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatRosterTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(model.FacilityLocation()).Format(time.RFC3339)
+}
+
+func writeRosterError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "forbidden":
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case "class not found", "session not found", "instructor not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
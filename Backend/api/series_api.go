@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBookingSeries books the authenticated user into every session of a course in a date range.
+// POST /classes/series
+func CreateBookingSeries(c *gin.Context) {
+	var input model.BookingSeriesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentPrincipal(c).UserID
+
+	series, results, err := service.CreateBookingSeries(userID, input)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "class not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "booking series already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "invalid start_date, expected YYYY-MM-DD",
+			"invalid end_date, expected YYYY-MM-DD",
+			"end_date must not be before start_date":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"series":  series,
+		"results": results,
+	})
+}
+
+// ListMyBookingSeries returns the authenticated user's booking series with per-session outcomes.
+// GET /classes/series
+func ListMyBookingSeries(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	series, err := service.ListUserBookingSeries(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+// CancelBookingSeries drops the remaining sessions of one of the user's series.
+// POST /classes/series/:series_id/cancel
+func CancelBookingSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("series_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	userID := currentPrincipal(c).UserID
+
+	dropped, err := service.CancelBookingSeries(userID, uint(seriesID))
+	if err != nil {
+		switch err.Error() {
+		case "booking series not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "forbidden":
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case "booking series already canceled":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Booking series canceled",
+		"dropped_sessions": dropped,
+	})
+}
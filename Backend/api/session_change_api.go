@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ManagerCancelSession cancels a single class session and its enrollments.
+// POST /classes/sessions/:session_id/cancel (manager only)
+func ManagerCancelSession(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	sessionID, input, ok := bindSessionCancel(c)
+	if !ok {
+		return
+	}
+
+	change, err := service.ManagerCancelSession(managerID, sessionID, input)
+	if err != nil {
+		writeSessionChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session canceled", "change": change})
+}
+
+// ManagerRescheduleSession moves a single class session to a new time.
+// POST /classes/sessions/:session_id/reschedule (manager only)
+func ManagerRescheduleSession(c *gin.Context) {
+	managerID := currentPrincipal(c).UserID
+
+	sessionID, input, ok := bindSessionReschedule(c)
+	if !ok {
+		return
+	}
+
+	change, err := service.ManagerRescheduleSession(managerID, sessionID, input)
+	if err != nil {
+		writeSessionChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session rescheduled", "change": change})
+}
+
+// InstructorCancelSession cancels a session of one of the instructor's courses.
+// POST /instructor/sessions/:session_id/cancel
+func InstructorCancelSession(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	sessionID, input, ok := bindSessionCancel(c)
+	if !ok {
+		return
+	}
+
+	change, err := service.InstructorCancelSession(instructorID, sessionID, input)
+	if err != nil {
+		writeSessionChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session canceled", "change": change})
+}
+
+// InstructorRescheduleSession moves a session of one of the instructor's courses.
+// POST /instructor/sessions/:session_id/reschedule
+func InstructorRescheduleSession(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	sessionID, input, ok := bindSessionReschedule(c)
+	if !ok {
+		return
+	}
+
+	change, err := service.InstructorRescheduleSession(instructorID, sessionID, input)
+	if err != nil {
+		writeSessionChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session rescheduled", "change": change})
+}
+
+// ListSessionChanges returns the cancel/reschedule audit log of a session.
+// GET /classes/sessions/:session_id/changes (manager only)
+func ListSessionChanges(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	changes, err := service.ListSessionChanges(uint(sessionID))
+	if err != nil {
+		writeSessionChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"changes": changes})
+}
+
+// ListMyNotifications returns the authenticated user's session change notifications.
+// GET /classes/notifications
+func ListMyNotifications(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	notifications, err := service.ListUserSessionNotifications(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+func bindSessionCancel(c *gin.Context) (uint, model.SessionCancelInput, bool) {
+	var input model.SessionCancelInput
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return 0, input, false
+	}
+
+	// The body is optional; a reason may be omitted.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return 0, input, false
+		}
+	}
+	return uint(sessionID), input, true
+}
+
+func bindSessionReschedule(c *gin.Context) (uint, model.SessionRescheduleInput, bool) {
+	var input model.SessionRescheduleInput
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return 0, input, false
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, input, false
+	}
+	return uint(sessionID), input, true
+}
+
+func writeSessionChangeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "session not found", "instructor not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "forbidden":
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case "session is not scheduled", "another session of this class is already on that date", "room is already booked at that time":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "end_at must be after start_at", "start_at must be in the future", "capacity exceeds room capacity":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
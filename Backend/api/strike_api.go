@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ManagerListMemberStrikes returns a member's standing and strike history.
+// GET /manager/users/:id/strikes (manager only)
+func ManagerListMemberStrikes(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	standing, history, err := service.ManagerListMemberStrikes(uint(userID))
+	if err != nil {
+		writeStrikeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standing": standing, "history": history})
+}
+
+// ManagerClearMemberStrikes clears all of a member's active strikes.
+// DELETE /manager/users/:id/strikes (manager only)
+func ManagerClearMemberStrikes(c *gin.Context) {
+	clearMemberStrikes(c, 0)
+}
+
+// ManagerClearMemberStrike clears one of a member's strikes.
+// DELETE /manager/users/:id/strikes/:strike_id (manager only)
+func ManagerClearMemberStrike(c *gin.Context) {
+	strikeID, err := strconv.ParseUint(c.Param("strike_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid strike ID"})
+		return
+	}
+	clearMemberStrikes(c, uint(strikeID))
+}
+
+func clearMemberStrikes(c *gin.Context, strikeID uint) {
+	managerID := currentPrincipal(c).UserID
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	standing, err := service.ManagerClearMemberStrikes(managerID, uint(userID), strikeID)
+	if err != nil {
+		writeStrikeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standing": standing})
+}
+
+func writeStrikeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found", "strike not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "strike already cleared":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
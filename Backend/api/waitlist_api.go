@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"my-course-backend/model"
+	"my-course-backend/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JoinWaitlist queues the authenticated user for a full session: the one in session_id, or else the
+// next session of the class in course_id.
+// POST /classes/waitlist
+func JoinWaitlist(c *gin.Context) {
+	var input model.WaitlistRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.CourseID == 0 && input.SessionID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course_id or session_id is required"})
+		return
+	}
+
+	userID := currentPrincipal(c).UserID
+
+	var enrollment *model.Enrollment
+	var err error
+	if input.SessionID != 0 {
+		enrollment, err = service.JoinSessionWaitlist(userID, input.SessionID)
+	} else {
+		enrollment, err = service.JoinWaitlist(userID, input.CourseID)
+	}
+	if err != nil {
+		if writeBookingPolicyRefusal(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "user not found", "class not found", "session not found", "no upcoming session found for this class":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "enrollment already exists", "class is not full", "class schedule overlaps with an existing enrolled class",
+			"session is not open for registration":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "invalid class schedule":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":           "Joined waitlist successfully",
+		"waitlist_position": enrollment.WaitlistPosition,
+		"session_id":        enrollment.SessionID,
+	})
+}
+
+// ListMyWaitlist returns the authenticated user's waitlist entries and positions.
+// GET /classes/waitlist
+func ListMyWaitlist(c *gin.Context) {
+	userID := currentPrincipal(c).UserID
+
+	waitlist, err := service.ListUserWaitlist(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": waitlist})
+}
+
+// ListClassWaitlist returns the waitlist for a class's upcoming sessions (manager only).
+// GET /classes/:id/waitlist
+func ListClassWaitlist(c *gin.Context) {
+	classIDStr := c.Param("id")
+	classID, err := strconv.ParseUint(classIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	waitlist, err := service.ListClassWaitlist(uint(classID))
+	if err != nil {
+		if err.Error() == "class not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": waitlist})
+}
+
+// InstructorListCourseWaitlist returns the waitlist for one of the instructor's courses.
+// GET /instructor/courses/:id/waitlist
+func InstructorListCourseWaitlist(c *gin.Context) {
+	instructorID := currentPrincipal(c).UserID
+
+	courseIDStr := c.Param("id")
+	courseID64, err := strconv.ParseUint(courseIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	waitlist, err := service.ListInstructorCourseWaitlist(instructorID, uint(courseID64))
+	if err != nil {
+		switch err.Error() {
+		case "forbidden":
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case "class not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "class not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": waitlist})
+}
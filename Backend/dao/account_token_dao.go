@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// CreateAccountToken inserts a token and marks the user's earlier unused tokens of the same purpose
+// as used, in one transaction.
+func CreateAccountToken(token *model.AccountToken) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", token.CreatedAt).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// GetAccountTokenByHash retrieves a token of a purpose by the hash of its value.
+func GetAccountTokenByHash(purpose string, hash string) (*model.AccountToken, error) {
+	var token model.AccountToken
+	if err := db.DB.Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// consumeAccountToken marks an unused token used. Returns false when it was already used.
+func consumeAccountToken(tx *gorm.DB, id uint, now time.Time) (bool, error) {
+	result := tx.Model(&model.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResetPasswordWithToken uses a reset token and stores the new password hash in one transaction.
+// Returns false without changing anything when the token was already used.
+func ResetPasswordWithToken(tokenID uint, userID uint, passwordHash string, now time.Time) (bool, error) {
+	reset := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		consumed, err := consumeAccountToken(tx, tokenID, now)
+		if err != nil || !consumed {
+			return err
+		}
+		reset = true
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("password", passwordHash).Error
+	})
+	return reset, err
+}
+
+// VerifyEmailWithToken uses a verification token and marks the user's email verified in one transaction.
+// Returns false without changing anything when the token was already used.
+func VerifyEmailWithToken(tokenID uint, userID uint, now time.Time) (bool, error) {
+	verified := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		consumed, err := consumeAccountToken(tx, tokenID, now)
+		if err != nil || !consumed {
+			return err
+		}
+		verified = true
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("email_verified_at", now).Error
+	})
+	return verified, err
+}
+
+// UpdateUserPassword stores a new password hash.
+func UpdateUserPassword(userID uint, passwordHash string) error {
+	return db.DB.Model(&model.User{}).Where("id = ?", userID).Update("password", passwordHash).Error
+}
+
+// DeleteExpiredAccountTokens removes tokens that expired before now.
+func DeleteExpiredAccountTokens(now time.Time) (int64, error) {
+	result := db.DB.Where("expires_at < ?", now).Delete(&model.AccountToken{})
+	return result.RowsAffected, result.Error
+}
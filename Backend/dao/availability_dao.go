@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// ListInstructorAvailability returns the weekly availability windows of an instructor.
+func ListInstructorAvailability(instructorID uint) ([]model.InstructorAvailability, error) {
+	var windows []model.InstructorAvailability
+	if err := db.DB.Where("instructor_id = ?", instructorID).
+		Order("id ASC").
+		Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// ReplaceInstructorAvailability swaps all availability windows of an instructor for the given ones.
+func ReplaceInstructorAvailability(instructorID uint, windows []model.InstructorAvailability) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("instructor_id = ?", instructorID).
+			Delete(&model.InstructorAvailability{}).Error; err != nil {
+			return err
+		}
+		if len(windows) == 0 {
+			return nil
+		}
+		for i := range windows {
+			windows[i].InstructorID = instructorID
+		}
+		return tx.Create(&windows).Error
+	})
+}
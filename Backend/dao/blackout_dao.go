@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// ListBlackoutDates returns blackout dates on or after fromDate (YYYY-MM-DD) ordered by date.
+// An empty fromDate returns all of them.
+func ListBlackoutDates(fromDate string) ([]model.BlackoutDate, error) {
+	query := db.DB.Order("date ASC")
+	if fromDate != "" {
+		query = query.Where("date >= ?", fromDate)
+	}
+
+	var blackouts []model.BlackoutDate
+	if err := query.Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+	return blackouts, nil
+}
+
+// GetBlackoutDateByID retrieves a blackout date by ID.
+func GetBlackoutDateByID(id uint) (*model.BlackoutDate, error) {
+	var blackout model.BlackoutDate
+	if err := db.DB.First(&blackout, id).Error; err != nil {
+		return nil, err
+	}
+	return &blackout, nil
+}
+
+// CheckBlackoutDateTaken reports whether another blackout already covers the date.
+func CheckBlackoutDateTaken(date string, excludeID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.BlackoutDate{}).
+		Where("date = ? AND id != ?", date, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateBlackoutDate inserts a blackout date.
+func CreateBlackoutDate(blackout *model.BlackoutDate) error {
+	return db.DB.Create(blackout).Error
+}
+
+// UpdateBlackoutDate saves a blackout date.
+func UpdateBlackoutDate(blackout *model.BlackoutDate) error {
+	return db.DB.Save(blackout).Error
+}
+
+// DeleteBlackoutDateByID deletes a blackout date.
+func DeleteBlackoutDateByID(id uint) error {
+	return db.DB.Delete(&model.BlackoutDate{}, id).Error
+}
+
+// ListBlackoutDateSet returns the blackout dates on or after fromDate as a set keyed by YYYY-MM-DD.
+func ListBlackoutDateSet(fromDate string) (map[string]bool, error) {
+	var dates []string
+	if err := db.DB.Model(&model.BlackoutDate{}).
+		Where("date >= ?", fromDate).
+		Pluck("date", &dates).Error; err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		set[d] = true
+	}
+	return set, nil
+}
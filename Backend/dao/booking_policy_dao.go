@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// ListBookingPolicies returns all booking policies ordered by name.
+func ListBookingPolicies() ([]model.BookingPolicy, error) {
+	var policies []model.BookingPolicy
+	if err := db.DB.Order("name ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetBookingPolicyByID retrieves a booking policy by ID.
+func GetBookingPolicyByID(id uint) (*model.BookingPolicy, error) {
+	var policy model.BookingPolicy
+	if err := db.DB.First(&policy, id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetBookingPolicyByCategory retrieves the policy attached to a course category, ignoring case.
+func GetBookingPolicyByCategory(category string) (*model.BookingPolicy, error) {
+	var policy model.BookingPolicy
+	if err := db.DB.Where("LOWER(category) = LOWER(?)", category).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// CheckBookingPolicyNameTaken reports whether another policy already uses the name.
+func CheckBookingPolicyNameTaken(name string, excludeID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.BookingPolicy{}).
+		Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CheckBookingPolicyCategoryTaken reports whether another policy is already attached to the category.
+func CheckBookingPolicyCategoryTaken(category string, excludeID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.BookingPolicy{}).
+		Where("LOWER(category) = LOWER(?) AND id != ?", category, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateBookingPolicy inserts a booking policy.
+func CreateBookingPolicy(policy *model.BookingPolicy) error {
+	return db.DB.Create(policy).Error
+}
+
+// UpdateBookingPolicy saves a booking policy.
+func UpdateBookingPolicy(policy *model.BookingPolicy) error {
+	return db.DB.Save(policy).Error
+}
+
+// DeleteBookingPolicyByID deletes a booking policy and detaches it from its courses.
+func DeleteBookingPolicyByID(id uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Course{}).
+			Where("booking_policy_id = ?", id).
+			Update("booking_policy_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.BookingPolicy{}, id).Error
+	})
+}
+
+// CountActiveBookingsByUser returns the user's seated enrollments in scheduled sessions that have not started yet.
+func CountActiveBookingsByUser(userID uint, now time.Time) (int64, error) {
+	var count int64
+	if err := db.DB.Model(&model.Enrollment{}).
+		Joins("INNER JOIN ClassSession ON ClassSession.id = Enrollment.session_id").
+		Where("Enrollment.user_id = ? AND Enrollment.status = ? AND ClassSession.status = 'scheduled' AND ClassSession.start_at > ?",
+			userID, model.EnrollmentStatusEnrolled, now.UTC()).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountBookingsByUserOnDate returns the user's seated or attended enrollments in sessions on one date.
+func CountBookingsByUserOnDate(userID uint, sessionDate string) (int64, error) {
+	var count int64
+	if err := db.DB.Model(&model.Enrollment{}).
+		Joins("INNER JOIN ClassSession ON ClassSession.id = Enrollment.session_id").
+		Where("Enrollment.user_id = ? AND Enrollment.status IN ? AND ClassSession.status != 'canceled' AND ClassSession.session_date = ?",
+			userID, []string{model.EnrollmentStatusEnrolled, model.EnrollmentStatusAttended}, sessionDate).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
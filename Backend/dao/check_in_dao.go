@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// GetEnrollmentByID retrieves an enrollment with its user and session.
+func GetEnrollmentByID(id uint) (*model.Enrollment, error) {
+	var enrollment model.Enrollment
+	if err := db.DB.Preload("User").Preload("Session").First(&enrollment, id).Error; err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// MarkEnrollmentCheckedIn records a check-in and marks the enrollment attended.
+// Returns false when the enrollment is no longer seated or was already checked in.
+func MarkEnrollmentCheckedIn(id uint, at time.Time, by *uint) (bool, error) {
+	result := db.DB.Model(&model.Enrollment{}).
+		Where("id = ? AND status = ? AND checked_in_at IS NULL", id, model.EnrollmentStatusEnrolled).
+		Updates(map[string]interface{}{
+			"status":        model.EnrollmentStatusAttended,
+			"checked_in_at": at,
+			"checked_in_by": by,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkEndedEnrollmentsMissed marks seated enrollments that never checked in as missed once the same
+// 15-minute grace period as SyncEndedEnrollmentsToAttended has passed. Returns the enrollments it changed.
+func MarkEndedEnrollmentsMissed() ([]model.Enrollment, error) {
+	cutoff := time.Now().Add(-15 * time.Minute).UTC()
+	var enrollments []model.Enrollment
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Session").
+			Where("status = ? AND checked_in_at IS NULL AND session_id IN (SELECT id FROM ClassSession WHERE end_at < ?)",
+				model.EnrollmentStatusEnrolled, cutoff).
+			Order("id ASC").
+			Find(&enrollments).Error; err != nil {
+			return err
+		}
+		if len(enrollments) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(enrollments))
+		for i := range enrollments {
+			ids[i] = enrollments[i].ID
+			enrollments[i].Status = model.EnrollmentStatusMissed
+		}
+		return tx.Model(&model.Enrollment{}).Where("id IN ?", ids).Update("status", model.EnrollmentStatusMissed).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// ListMarkableSessionEnrollments returns a session's seated and already marked enrollments,
+// the ones attendance can be recorded for.
+func ListMarkableSessionEnrollments(sessionID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Preload("Session").
+		Where("session_id = ? AND status IN ?", sessionID, markableStatuses).
+		Order("id ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// UpdateSessionAttendance sets the status of each enrollment, keyed by enrollment ID, in one transaction.
+// Every enrollment must still belong to the session and be markable, otherwise nothing is changed.
+func UpdateSessionAttendance(sessionID uint, statuses map[uint]string) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		for enrollmentID, status := range statuses {
+			result := tx.Model(&model.Enrollment{}).
+				Where("id = ? AND session_id = ? AND status IN ?", enrollmentID, sessionID, markableStatuses).
+				Update("status", status)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("enrollment changed during update")
+			}
+		}
+		return nil
+	})
+}
+
+var markableStatuses = []string{model.EnrollmentStatusEnrolled, model.EnrollmentStatusAttended, model.EnrollmentStatusMissed}
@@ -2,17 +2,19 @@ package dao
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"my-course-backend/db"
 	"my-course-backend/model"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // SyncEndedEnrollmentsToAttended marks enrollments as attended after a 15-minute grace period post class end.
 func SyncEndedEnrollmentsToAttended() error {
-	cutoff := time.Now().Add(-15 * time.Minute)
+	cutoff := time.Now().Add(-15 * time.Minute).UTC()
 	return db.DB.Model(&model.Enrollment{}).
 		Where("status = ? AND session_id IN (SELECT id FROM ClassSession WHERE end_at < ?)", model.EnrollmentStatusEnrolled, cutoff).
 		Update("status", model.EnrollmentStatusAttended).Error
@@ -21,19 +23,171 @@ func SyncEndedEnrollmentsToAttended() error {
 // GetCourseByID retrieves a course by ID.
 func GetCourseByID(id uint) (*model.Course, error) {
 	var class model.Course
-	if err := db.DB.First(&class, id).Error; err != nil {
+	if err := db.DB.Preload("Slots", orderedSlots).First(&class, id).Error; err != nil {
 		return nil, err
 	}
 	return &class, nil
 }
 
-// ListClasses retrieves all courses.
-func ListClasses() ([]model.Course, error) {
+// orderedSlots keeps preloaded course slots in the order they were entered.
+func orderedSlots(tx *gorm.DB) *gorm.DB {
+	return tx.Order("id ASC")
+}
+
+// ReplaceCourseSlotsTx replaces all weekly slots of a course within tx.
+func ReplaceCourseSlotsTx(tx *gorm.DB, courseID uint, slots []model.CourseSlot) error {
+	if err := tx.Where("course_id = ?", courseID).Delete(&model.CourseSlot{}).Error; err != nil {
+		return err
+	}
+	for i := range slots {
+		slots[i].ID = 0
+		slots[i].CourseID = courseID
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	return tx.Create(&slots).Error
+}
+
+// nextSessionSeatsSQL returns the effective capacity of each course's next scheduled session and its seated
+// (enrolled, attended or missed) enrollments. A session's own capacity, which already holds the cap of the room
+// it was moved to, overrides the course capacity. It takes markableStatuses as its only argument.
+const nextSessionSeatsSQL = `
+	SELECT cs.course_id AS course_id, COALESCE(NULLIF(cs.capacity, 0), c.capacity) AS capacity, COUNT(e.id) AS seated
+	FROM ClassSession cs
+	JOIN Course c ON c.id = cs.course_id
+	JOIN (
+		SELECT course_id, MIN(session_date) AS first_date
+		FROM ClassSession
+		WHERE status = 'scheduled'
+		GROUP BY course_id
+	) nxt ON nxt.course_id = cs.course_id AND nxt.first_date = cs.session_date
+	LEFT JOIN Enrollment e ON e.session_id = cs.id AND e.course_id = cs.course_id AND e.status IN ?
+	WHERE cs.status = 'scheduled'
+	GROUP BY cs.course_id`
+
+// nextSessionOpenSQL is the number of open seats in a course's next session, or its capacity without one.
+const nextSessionOpenSQL = `(COALESCE(seats.capacity, "Course".capacity) - COALESCE(seats.seated, 0))`
+
+// classSortColumns maps ClassFilter.Sort fields to ORDER BY expressions.
+var classSortColumns = map[string]string{
+	"start_time": `"Course".start_time`,
+	"name":       `LOWER("Course".course_name)`,
+	"spots":      nextSessionOpenSQL,
+	"capacity":   `"Course".capacity`,
+}
+
+// ListClasses retrieves one page of courses matching the filter with their room, plus the total match count.
+func ListClasses(filter model.ClassFilter) ([]model.Course, int64, error) {
+	query := db.DB.Model(&model.Course{}).
+		Joins("LEFT JOIN ("+nextSessionSeatsSQL+`) seats ON seats.course_id = "Course".id`, markableStatuses)
+
+	if filter.RoomID != nil {
+		query = query.Where(`"Course".room_id = ?`, *filter.RoomID)
+	}
+	if filter.InstructorID != nil {
+		query = query.Where(`"Course".instructor_id = ? OR "Course".id IN (SELECT course_id FROM CourseInstructor WHERE instructor_id = ?)`,
+			*filter.InstructorID, *filter.InstructorID)
+	}
+	if filter.Category != "" {
+		query = query.Where(`LOWER(TRIM("Course".category)) = LOWER(?)`, filter.Category)
+	}
+	if filter.Weekday != "" || filter.StartAfter != nil || filter.EndBefore != nil {
+		query = query.Where(`EXISTS (SELECT 1 FROM CourseSlot s WHERE s.course_id = "Course".id`+classSlotBounds("s", filter)+`)`+
+			` OR (NOT EXISTS (SELECT 1 FROM CourseSlot s WHERE s.course_id = "Course".id)`+classSlotBounds(`"Course"`, filter)+`)`,
+			append(classSlotBoundArgs(filter), classSlotBoundArgs(filter)...)...)
+	}
+	if filter.Search != "" {
+		like := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where(`LOWER("Course".course_name) LIKE ? OR LOWER("Course".description) LIKE ? OR LOWER("Course".course_code) LIKE ?`,
+			like, like, like)
+	}
+	if filter.OnlyOpen {
+		query = query.Where(nextSessionOpenSQL + " > 0")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortField, direction := strings.TrimPrefix(filter.Sort, "-"), "ASC"
+	if strings.HasPrefix(filter.Sort, "-") {
+		direction = "DESC"
+	}
+	column, ok := classSortColumns[sortField]
+	if !ok {
+		column = classSortColumns["start_time"]
+	}
+
+	query = query.Select(`"Course".*`).
+		Preload("Room").
+		Preload("Slots", orderedSlots).
+		Order(column + " " + direction).
+		Order(`"Course".id ASC`)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset((filter.Page - 1) * filter.Limit)
+	}
+
 	var classes []model.Course
-	if err := db.DB.Order("start_time ASC").Find(&classes).Error; err != nil {
+	if err := query.Find(&classes).Error; err != nil {
+		return nil, 0, err
+	}
+	return classes, total, nil
+}
+
+// classSlotBounds renders the slot conditions of a class filter against a table holding weekday/start_time/end_time.
+// Courses that predate CourseSlot are matched on their own columns.
+func classSlotBounds(table string, filter model.ClassFilter) string {
+	var sql string
+	if filter.Weekday != "" {
+		sql += " AND LOWER(SUBSTR(TRIM(" + table + ".weekday), 1, 3)) = ?"
+	}
+	if filter.StartAfter != nil {
+		sql += " AND " + table + ".start_time >= ?"
+	}
+	if filter.EndBefore != nil {
+		sql += " AND " + table + ".end_time <= ?"
+	}
+	return sql
+}
+
+// classSlotBoundArgs returns the arguments of classSlotBounds in order.
+func classSlotBoundArgs(filter model.ClassFilter) []interface{} {
+	var args []interface{}
+	if filter.Weekday != "" {
+		args = append(args, filter.Weekday)
+	}
+	if filter.StartAfter != nil {
+		args = append(args, *filter.StartAfter)
+	}
+	if filter.EndBefore != nil {
+		args = append(args, *filter.EndBefore)
+	}
+	return args
+}
+
+// ListNextSessionSeats returns the effective capacity and seated enrollments of each course's next session,
+// keyed by course ID. Courses without a scheduled session are left out.
+func ListNextSessionSeats(courseIDs []uint) (map[uint]model.SessionSeats, error) {
+	seats := make(map[uint]model.SessionSeats, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return seats, nil
+	}
+
+	var rows []struct {
+		CourseID uint
+		Capacity int
+		Seated   int64
+	}
+	if err := db.DB.Raw("SELECT course_id, capacity, seated FROM ("+nextSessionSeatsSQL+") WHERE course_id IN ?", markableStatuses, courseIDs).
+		Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return classes, nil
+	for _, row := range rows {
+		seats[row.CourseID] = model.SessionSeats{Capacity: row.Capacity, Seated: row.Seated}
+	}
+	return seats, nil
 }
 
 // ListCategories returns all distinct non-empty categories.
@@ -83,13 +237,14 @@ func GetNextScheduledSession(courseID uint) (*model.ClassSession, error) {
 	return &session, nil
 }
 
-// CountEnrollmentsByClass returns the number of enrollments for a course.
+// CountEnrollmentsByClass returns the number of enrollments holding a seat in the next session of a course.
+// Waitlisted enrollments do not hold a seat and are excluded.
 func CountEnrollmentsByClass(courseID uint) (int64, error) {
 	var count int64
 	if err := db.DB.Model(&model.Enrollment{}).
-		Where(`course_id = ? AND session_id IN (
+		Where(`course_id = ? AND status IN ? AND session_id IN (
 			SELECT id FROM ClassSession WHERE course_id = ? AND status = 'scheduled' ORDER BY session_date ASC LIMIT 1
-		)`, courseID, courseID).
+		)`, courseID, markableStatuses, courseID).
 		Count(&count).Error; err != nil {
 		return 0, err
 	}
@@ -101,19 +256,85 @@ func CreateEnrollment(enrollment *model.Enrollment) error {
 	return db.DB.Create(enrollment).Error
 }
 
-// DeleteEnrollment removes a user enrollment by user and course IDs.
-func DeleteEnrollment(userID uint, courseID uint) error {
-	result := db.DB.Where(`user_id = ? AND course_id = ? AND session_id IN (
-		SELECT id FROM ClassSession WHERE course_id = ? AND status = 'scheduled' ORDER BY session_date ASC LIMIT 1
-	) AND status = 'enrolled'`, userID, courseID, courseID).
-		Delete(&model.Enrollment{})
-	if result.Error != nil {
-		return result.Error
+// DeleteEnrollment removes a user's enrolled or waitlisted entry for the next session of a course
+// and returns the deleted row. Remaining waitlist positions for that session are compacted.
+func DeleteEnrollment(userID uint, courseID uint) (*model.Enrollment, error) {
+	return deleteActiveEnrollment(nextSessionEnrollment(userID, courseID))
+}
+
+// DeleteSessionEnrollment removes a user's enrolled or waitlisted entry for a specific session
+// and returns the deleted row.
+func DeleteSessionEnrollment(userID uint, sessionID uint) (*model.Enrollment, error) {
+	return deleteActiveEnrollment(sessionEnrollment(userID, sessionID))
+}
+
+// GetActiveEnrollment returns the user's enrolled or waitlisted entry for the next session of a course,
+// with the session loaded.
+func GetActiveEnrollment(userID uint, courseID uint) (*model.Enrollment, error) {
+	return findActiveEnrollment(nextSessionEnrollment(userID, courseID))
+}
+
+// GetActiveSessionEnrollment returns the user's enrolled or waitlisted entry for a specific session,
+// with the session loaded.
+func GetActiveSessionEnrollment(userID uint, sessionID uint) (*model.Enrollment, error) {
+	return findActiveEnrollment(sessionEnrollment(userID, sessionID))
+}
+
+func nextSessionEnrollment(userID uint, courseID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where(`user_id = ? AND course_id = ? AND session_id IN (
+			SELECT id FROM ClassSession WHERE course_id = ? AND status = 'scheduled' ORDER BY session_date ASC LIMIT 1
+		)`, userID, courseID, courseID)
+	}
+}
+
+func sessionEnrollment(userID uint, sessionID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("user_id = ? AND session_id = ?", userID, sessionID)
+	}
+}
+
+// findActiveEnrollment returns the first enrolled or waitlisted row matched by scope.
+func findActiveEnrollment(scope func(tx *gorm.DB) *gorm.DB) (*model.Enrollment, error) {
+	var enrollment model.Enrollment
+	if err := db.DB.Scopes(scope).
+		Preload("Session").
+		Where("status IN ?", []string{model.EnrollmentStatusEnrolled, model.EnrollmentStatusWaitlisted}).
+		First(&enrollment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("enrollment not found")
+	return &enrollment, nil
+}
+
+// deleteActiveEnrollment deletes the first enrolled or waitlisted row matched by scope.
+func deleteActiveEnrollment(scope func(tx *gorm.DB) *gorm.DB) (*model.Enrollment, error) {
+	var enrollment model.Enrollment
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(scope).
+			Where("status IN ?", []string{model.EnrollmentStatusEnrolled, model.EnrollmentStatusWaitlisted}).
+			First(&enrollment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("enrollment not found")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&model.Enrollment{}, enrollment.ID).Error; err != nil {
+			return err
+		}
+
+		if enrollment.Status == model.EnrollmentStatusWaitlisted && enrollment.SessionID != nil {
+			return renumberWaitlist(tx, *enrollment.SessionID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &enrollment, nil
 }
 
 // ListEnrollmentsByClass returns enrollments for a course with user info.
@@ -133,8 +354,9 @@ func ListEnrolledCoursesByUser(userID uint) ([]model.Course, error) {
 	var courses []model.Course
 	if err := db.DB.Joins("INNER JOIN Enrollment ON Enrollment.course_id = Course.id").
 		Joins("INNER JOIN ClassSession ON ClassSession.id = Enrollment.session_id").
-		Where("Enrollment.user_id = ? AND Enrollment.status = ? AND ClassSession.session_date >= DATE('now')", userID, "enrolled").
+		Where("Enrollment.user_id = ? AND Enrollment.status = ? AND ClassSession.session_date >= ?", userID, "enrolled", model.FacilityToday()).
 		Order("ClassSession.session_date ASC, Course.start_time ASC").
+		Preload("Slots", orderedSlots).
 		Find(&courses).Error; err != nil {
 		return nil, err
 	}
@@ -143,12 +365,47 @@ func ListEnrolledCoursesByUser(userID uint) ([]model.Course, error) {
 
 // NEW: CreateCourse inserts a new course.
 func CreateCourse(course *model.Course) error {
-	return db.DB.Create(course).Error
+	return db.DB.Omit(clause.Associations).Create(course).Error
+}
+
+// CreateCourseWithSchedule inserts a new course with its weekly slots, its lead instructor assignment and
+// its first sessions in one transaction, so a failure leaves no partial course behind.
+func CreateCourseWithSchedule(course *model.Course, slots []model.CourseSlot, sessions []model.ClassSession) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(course).Error; err != nil {
+			return err
+		}
+
+		if len(slots) > 0 {
+			for i := range slots {
+				slots[i].ID = 0
+				slots[i].CourseID = course.ID
+			}
+			if err := tx.Create(&slots).Error; err != nil {
+				return err
+			}
+		}
+
+		if course.InstructorID != nil {
+			lead := model.CourseInstructor{CourseID: course.ID, InstructorID: *course.InstructorID, Role: model.CourseInstructorRoleLead}
+			if err := tx.Omit(clause.Associations).Create(&lead).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(sessions) == 0 {
+			return nil
+		}
+		for i := range sessions {
+			sessions[i].CourseID = course.ID
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sessions).Error
+	})
 }
 
-// NEW: UpdateCourse updates an existing course (all fields).
-func UpdateCourse(course *model.Course) error {
-	return db.DB.Save(course).Error
+// UpdateCourseTx updates an existing course (all fields) within tx.
+func UpdateCourseTx(tx *gorm.DB, course *model.Course) error {
+	return tx.Omit(clause.Associations).Save(course).Error
 }
 
 // NEW: DeleteCourseByID deletes a course by ID.
@@ -165,6 +422,15 @@ func CreateDailyActivity(activity *model.UserDailyActivity) error {
 // Uses the session date (when the class actually happened) instead of enroll_time,
 // and only includes past sessions so upcoming classes are excluded from analytics.
 func BackfillUserDailyActivityFromEnrollments(userID uint) error {
+	return backfillDailyActivity("AND e.user_id = ?", userID)
+}
+
+// BackfillDailyActivityFromEnrollments syncs missing daily rows for every user.
+func BackfillDailyActivityFromEnrollments() error {
+	return backfillDailyActivity("")
+}
+
+func backfillDailyActivity(userFilter string, args ...interface{}) error {
 	query := `
 		INSERT INTO UserDailyActivity (enrollment_id, user_id, course_id, activity_date, created_at)
 		SELECT e.id, e.user_id, e.course_id,
@@ -172,9 +438,9 @@ func BackfillUserDailyActivityFromEnrollments(userID uint) error {
 			CURRENT_TIMESTAMP
 		FROM Enrollment e
 		LEFT JOIN ClassSession cs ON cs.id = e.session_id
-		WHERE e.user_id = ?
-		AND e.status = 'attended'
-		AND COALESCE(cs.session_date, DATE(e.enroll_time)) <= DATE('now')
+		WHERE e.status = 'attended'
+		` + userFilter + `
+		AND COALESCE(cs.session_date, DATE(e.enroll_time)) <= ?
 		AND NOT EXISTS (
 			SELECT 1
 			FROM UserDailyActivity uda
@@ -182,7 +448,8 @@ func BackfillUserDailyActivityFromEnrollments(userID uint) error {
 		)
 	`
 
-	return db.DB.Exec(query, userID).Error
+	args = append(args, model.FacilityToday())
+	return db.DB.Exec(query, args...).Error
 }
 
 // GetUserActivityStats returns total activity stats in a date range.
@@ -0,0 +1,166 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListCourseInstructors returns the instructors assigned to a course, lead first.
+func ListCourseInstructors(courseID uint) ([]model.CourseInstructor, error) {
+	var assignments []model.CourseInstructor
+	if err := db.DB.Where("course_id = ?", courseID).
+		Preload("Instructor").
+		Order("CASE role WHEN 'lead' THEN 0 ELSE 1 END, id ASC").
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// IsCourseInstructor reports whether an instructor is assigned to a course as lead or assistant.
+func IsCourseInstructor(courseID uint, instructorID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.CourseInstructor{}).
+		Where("course_id = ? AND instructor_id = ?", courseID, instructorID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SetCourseInstructor assigns an instructor to a course with a role. Assigning a lead replaces the
+// previous lead and mirrors the new one on Course.instructor_id and Course.instructor.
+func SetCourseInstructor(courseID uint, instructor *model.Instructor, role string) (*model.CourseInstructor, error) {
+	var assignment *model.CourseInstructor
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		assignment, err = SetCourseInstructorTx(tx, courseID, instructor, role)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// SetCourseInstructorTx is SetCourseInstructor within tx.
+func SetCourseInstructorTx(tx *gorm.DB, courseID uint, instructor *model.Instructor, role string) (*model.CourseInstructor, error) {
+	assignment := model.CourseInstructor{
+		CourseID:     courseID,
+		InstructorID: instructor.ID,
+		Role:         role,
+	}
+
+	if role == model.CourseInstructorRoleLead {
+		if err := tx.Where("course_id = ? AND role = ? AND instructor_id != ?", courseID, model.CourseInstructorRoleLead, instructor.ID).
+			Delete(&model.CourseInstructor{}).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&model.Course{}).
+			Where("id = ?", courseID).
+			Updates(map[string]interface{}{
+				"instructor_id": instructor.ID,
+				"instructor":    instructor.Name,
+			}).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		// Demoting the current lead leaves the course without one.
+		if err := clearCourseLead(tx, courseID, instructor.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_id"}, {Name: "instructor_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&assignment).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("course_id = ? AND instructor_id = ?", courseID, instructor.ID).First(&assignment).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// RemoveCourseInstructor unassigns an instructor from a course and returns whether a row was removed.
+// Removing the lead clears Course.instructor_id and Course.instructor.
+func RemoveCourseInstructor(courseID uint, instructorID uint) (bool, error) {
+	removed := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("course_id = ? AND instructor_id = ?", courseID, instructorID).
+			Delete(&model.CourseInstructor{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected > 0
+		return clearCourseLead(tx, courseID, instructorID)
+	})
+	return removed, err
+}
+
+// clearCourseLead unsets the course's lead columns if they point at the given instructor.
+func clearCourseLead(tx *gorm.DB, courseID uint, instructorID uint) error {
+	return tx.Model(&model.Course{}).
+		Where("id = ? AND instructor_id = ?", courseID, instructorID).
+		Updates(map[string]interface{}{
+			"instructor_id": nil,
+			"instructor":    "",
+		}).Error
+}
+
+// SetSessionSubstitute assigns (or clears, when instructorID is nil) the substitute of a session.
+func SetSessionSubstitute(sessionID uint, instructorID *uint) error {
+	return db.DB.Model(&model.ClassSession{}).
+		Where("id = ?", sessionID).
+		Update("substitute_instructor_id", instructorID).Error
+}
+
+// ListSubstituteSessionIDs returns the sessions of a course an instructor covers as substitute.
+func ListSubstituteSessionIDs(courseID uint, instructorID uint) ([]uint, error) {
+	var ids []uint
+	if err := db.DB.Model(&model.ClassSession{}).
+		Where("course_id = ? AND substitute_instructor_id = ?", courseID, instructorID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListUpcomingSubstituteSessions returns the upcoming sessions an instructor covers as substitute.
+func ListUpcomingSubstituteSessions(instructorID uint) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Where("substitute_instructor_id = ? AND status = 'scheduled' AND session_date >= ?", instructorID, model.FacilityToday()).
+		Order("start_at ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListEnrollmentsBySessions returns the enrollments of the given sessions with user info.
+func ListEnrollmentsBySessions(sessionIDs []uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Where("session_id IN ?", sessionIDs).
+		Preload("User").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// UpdateSessionEnrollmentStatus sets the status of a user's enrollments in the given sessions.
+func UpdateSessionEnrollmentStatus(userID uint, sessionIDs []uint, status string) (bool, error) {
+	tx := db.DB.Model(&model.Enrollment{}).
+		Where("user_id = ? AND session_id IN ?", userID, sessionIDs).
+		Update("status", status)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
@@ -1,16 +1,79 @@
 package dao
 
 import (
+	"time"
+
 	"my-course-backend/db"
 	"my-course-backend/model"
 )
 
-// ListCoursesByInstructorName returns all courses whose instructor column matches the given name (case-insensitive, trimmed).
-func ListCoursesByInstructorName(name string) ([]model.Course, error) {
+// GetInstructorByID retrieves an instructor profile by ID.
+func GetInstructorByID(id uint) (*model.Instructor, error) {
+	var instructor model.Instructor
+	if err := db.DB.First(&instructor, id).Error; err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// GetInstructorByUserID retrieves the instructor profile of a user.
+func GetInstructorByUserID(userID uint) (*model.Instructor, error) {
+	var instructor model.Instructor
+	if err := db.DB.Where("user_id = ?", userID).First(&instructor).Error; err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// EnsureInstructorProfile creates the instructor profile of a user if it does not exist yet.
+func EnsureInstructorProfile(userID uint, name string) error {
+	instructor := model.Instructor{UserID: userID, Name: name}
+	return db.DB.Where("user_id = ?", userID).FirstOrCreate(&instructor).Error
+}
+
+// ListActiveInstructors returns the profiles of users that currently hold the Instructor role.
+func ListActiveInstructors() ([]model.Instructor, error) {
+	var instructors []model.Instructor
+	if err := db.DB.
+		Where(`user_id IN (SELECT id FROM "User" WHERE role_id = 4)`).
+		Order("name ASC, id ASC").
+		Find(&instructors).Error; err != nil {
+		return nil, err
+	}
+	return instructors, nil
+}
+
+// UpdateInstructorProfile saves the self-editable profile fields of an instructor.
+func UpdateInstructorProfile(instructor *model.Instructor) error {
+	return db.DB.Model(instructor).
+		Select("bio", "photo_url", "specialties", "certifications").
+		Updates(instructor).Error
+}
+
+// ListUpcomingInstructorSessions returns the scheduled sessions an instructor teaches from today until the given date.
+// Sessions covered by a substitute are attributed to the substitute instead of the course instructors.
+func ListUpcomingInstructorSessions(instructorID uint, until time.Time) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Where("status = 'scheduled' AND session_date >= ? AND session_date < ?", model.FacilityToday(), model.FacilityDate(until)).
+		Where(`substitute_instructor_id = ? OR (substitute_instructor_id IS NULL AND course_id IN (
+			SELECT id FROM "Course" WHERE instructor_id = ?
+			UNION SELECT course_id FROM "CourseInstructor" WHERE instructor_id = ?))`,
+			instructorID, instructorID, instructorID).
+		Order("start_at ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListCoursesByInstructorID returns all courses the given instructor profile leads or assists.
+func ListCoursesByInstructorID(instructorID uint) ([]model.Course, error) {
 	var courses []model.Course
 	if err := db.DB.
-		Where("LOWER(TRIM(instructor)) = LOWER(TRIM(?))", name).
+		Where("instructor_id = ? OR id IN (SELECT course_id FROM CourseInstructor WHERE instructor_id = ?)", instructorID, instructorID).
 		Order("start_time ASC").
+		Preload("Slots", orderedSlots).
 		Find(&courses).Error; err != nil {
 		return nil, err
 	}
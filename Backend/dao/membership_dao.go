@@ -0,0 +1,193 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// ListMembershipPlans returns membership plans ordered by name; activeOnly hides retired plans.
+func ListMembershipPlans(activeOnly bool) ([]model.MembershipPlan, error) {
+	var plans []model.MembershipPlan
+	query := db.DB.Order("name ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// GetMembershipPlanByID retrieves a membership plan by ID.
+func GetMembershipPlanByID(id uint) (*model.MembershipPlan, error) {
+	var plan model.MembershipPlan
+	if err := db.DB.First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// CheckMembershipPlanNameTaken reports whether another plan already uses the name.
+func CheckMembershipPlanNameTaken(name string, excludeID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.MembershipPlan{}).
+		Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateMembershipPlan inserts a membership plan.
+func CreateMembershipPlan(plan *model.MembershipPlan) error {
+	return db.DB.Create(plan).Error
+}
+
+// UpdateMembershipPlan saves a membership plan.
+func UpdateMembershipPlan(plan *model.MembershipPlan) error {
+	return db.DB.Save(plan).Error
+}
+
+// CreateMemberSubscription inserts a subscription and, for credit plans, its grant ledger entry in one transaction.
+func CreateMemberSubscription(subscription *model.MemberSubscription, grant *model.CreditLedgerEntry) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		return createMemberSubscription(tx, subscription, grant)
+	})
+}
+
+func createMemberSubscription(tx *gorm.DB, subscription *model.MemberSubscription, grant *model.CreditLedgerEntry) error {
+	if err := tx.Omit("Plan").Create(subscription).Error; err != nil {
+		return err
+	}
+	if grant == nil {
+		return nil
+	}
+	grant.SubscriptionID = &subscription.ID
+	return appendLedgerEntry(tx, grant, false)
+}
+
+// GetMemberSubscriptionByID retrieves a subscription with its plan.
+func GetMemberSubscriptionByID(id uint) (*model.MemberSubscription, error) {
+	var subscription model.MemberSubscription
+	if err := db.DB.Preload("Plan").First(&subscription, id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListMemberSubscriptions returns the user's subscriptions, newest first.
+func ListMemberSubscriptions(userID uint) ([]model.MemberSubscription, error) {
+	var subscriptions []model.MemberSubscription
+	if err := db.DB.Preload("Plan").
+		Where("user_id = ?", userID).
+		Order("starts_on DESC, id DESC").
+		Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// HasActiveUnlimitedSubscription reports whether the user holds an active unlimited plan covering the date.
+func HasActiveUnlimitedSubscription(userID uint, date string) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.MemberSubscription{}).
+		Joins(`INNER JOIN MembershipPlan ON MembershipPlan.id = MemberSubscription.plan_id`).
+		Where(`MemberSubscription.user_id = ? AND MemberSubscription.status = ? AND MembershipPlan.kind = ?
+			AND MemberSubscription.starts_on <= ? AND (MemberSubscription.ends_on IS NULL OR MemberSubscription.ends_on >= ?)`,
+			userID, model.SubscriptionStatusActive, model.PlanKindUnlimited, date, date).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CancelMemberSubscription marks a subscription canceled and ends it on endsOn.
+func CancelMemberSubscription(id uint, endsOn string) error {
+	return db.DB.Model(&model.MemberSubscription{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": model.SubscriptionStatusCanceled, "ends_on": endsOn}).Error
+}
+
+// GetCreditBalance returns the user's class credit balance.
+func GetCreditBalance(userID uint) (int, error) {
+	return creditBalance(db.DB, userID)
+}
+
+// ListCreditLedger returns the user's ledger entries, newest first.
+func ListCreditLedger(userID uint) ([]model.CreditLedgerEntry, error) {
+	var entries []model.CreditLedgerEntry
+	if err := db.DB.Where("user_id = ?", userID).Order("id DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AppendCreditLedgerEntry records a balance change. Unless allowNegative is set,
+// a change that would take the balance below zero fails with "insufficient credits".
+func AppendCreditLedgerEntry(entry *model.CreditLedgerEntry, allowNegative bool) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		return appendLedgerEntry(tx, entry, allowNegative)
+	})
+}
+
+// AppendCreditLedgerEntryTx is AppendCreditLedgerEntry within tx.
+func AppendCreditLedgerEntryTx(tx *gorm.DB, entry *model.CreditLedgerEntry, allowNegative bool) error {
+	return appendLedgerEntry(tx, entry, allowNegative)
+}
+
+// GetEnrollmentCreditChargeTx returns the credits an enrollment still holds: its booking debits net of refunds.
+func GetEnrollmentCreditChargeTx(tx *gorm.DB, enrollmentID uint) (int, error) {
+	var net int
+	if err := tx.Model(&model.CreditLedgerEntry{}).
+		Where("enrollment_id = ? AND reason IN ?", enrollmentID, []string{model.CreditReasonBooking, model.CreditReasonRefund}).
+		Select("COALESCE(SUM(delta), 0)").
+		Scan(&net).Error; err != nil {
+		return 0, err
+	}
+	return -net, nil
+}
+
+func appendLedgerEntry(tx *gorm.DB, entry *model.CreditLedgerEntry, allowNegative bool) error {
+	balance, err := creditBalance(tx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if !allowNegative && entry.Delta < 0 && balance+entry.Delta < 0 {
+		return errors.New("insufficient credits")
+	}
+	entry.BalanceAfter = balance + entry.Delta
+	return tx.Create(entry).Error
+}
+
+func creditBalance(tx *gorm.DB, userID uint) (int, error) {
+	var balance int
+	if err := tx.Model(&model.CreditLedgerEntry{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(delta), 0)").
+		Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// ListChargedEndedWaitlistEnrollments returns waitlisted enrollments whose session ended before cutoff
+// and which still hold a booking charge.
+func ListChargedEndedWaitlistEnrollments(cutoff time.Time) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Model(&model.Enrollment{}).
+		Joins("INNER JOIN ClassSession ON ClassSession.id = Enrollment.session_id").
+		Where(`Enrollment.status = ? AND ClassSession.end_at < ? AND (
+			SELECT COALESCE(SUM(delta), 0) FROM CreditLedgerEntry
+			WHERE CreditLedgerEntry.enrollment_id = Enrollment.id AND CreditLedgerEntry.reason IN ?) < 0`,
+			model.EnrollmentStatusWaitlisted, cutoff.UTC(), []string{model.CreditReasonBooking, model.CreditReasonRefund}).
+		Order("Enrollment.id ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreatePaymentOrder inserts a payment order.
+func CreatePaymentOrder(order *model.PaymentOrder) error {
+	return db.DB.Omit("Plan").Create(order).Error
+}
+
+// GetPaymentOrderByID retrieves a payment order with its plan.
+func GetPaymentOrderByID(id uint) (*model.PaymentOrder, error) {
+	var order model.PaymentOrder
+	if err := db.DB.Preload("Plan").First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetPaymentOrderByIntent retrieves the order for a provider's intent.
+func GetPaymentOrderByIntent(provider string, intentID string) (*model.PaymentOrder, error) {
+	var order model.PaymentOrder
+	if err := db.DB.Preload("Plan").
+		Where("provider = ? AND provider_intent_id = ?", provider, intentID).
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListPaymentOrdersByUser returns the user's orders, newest first.
+func ListPaymentOrdersByUser(userID uint) ([]model.PaymentOrder, error) {
+	var orders []model.PaymentOrder
+	if err := db.DB.Preload("Plan").
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// UpdatePaymentOrderStatus moves an order to status if it is currently in one of from, storing the webhook
+// event that caused it in the same transaction when event is set. Returns false when the order was not in
+// a matching status or the event was already stored.
+func UpdatePaymentOrderStatus(id uint, from []string, status string, event *model.PaymentEvent) (bool, error) {
+	updated := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if recorded, err := recordPaymentEvent(tx, event); err != nil || !recorded {
+			return err
+		}
+		result := tx.Model(&model.PaymentOrder{}).
+			Where("id = ? AND status IN ?", id, from).
+			Update("status", status)
+		updated = result.RowsAffected > 0
+		return result.Error
+	})
+	return updated, err
+}
+
+// MarkPaymentOrderPaid moves a pending or authorized order to paid and creates the subscription it bought,
+// with its credit grant, in one transaction with the webhook event that reported the payment, if any.
+// Returns false without granting anything when the order was already paid, failed or refunded, or the
+// event was already stored.
+func MarkPaymentOrderPaid(id uint, paidAt time.Time, subscription *model.MemberSubscription, grant *model.CreditLedgerEntry, event *model.PaymentEvent) (bool, error) {
+	paid := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if recorded, err := recordPaymentEvent(tx, event); err != nil || !recorded {
+			return err
+		}
+		result := tx.Model(&model.PaymentOrder{}).
+			Where("id = ? AND status IN ?", id, []string{model.PaymentStatusPending, model.PaymentStatusAuthorized}).
+			Updates(map[string]interface{}{"status": model.PaymentStatusPaid, "paid_at": paidAt})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := createMemberSubscription(tx, subscription, grant); err != nil {
+			return err
+		}
+		paid = true
+		return tx.Model(&model.PaymentOrder{}).Where("id = ?", id).Update("subscription_id", subscription.ID).Error
+	})
+	return paid, err
+}
+
+// MarkPaymentOrderRefunded moves a paid order to refunded, cancels its subscription as of endsOn and takes
+// back up to credits of the credits it granted, never below a zero balance, in one transaction with the
+// webhook event that reported the refund, if any. Returns false without revoking anything when the order
+// was not paid or the event was already stored.
+func MarkPaymentOrderRefunded(order *model.PaymentOrder, refundedAt time.Time, endsOn string, credits int, actorID uint, event *model.PaymentEvent) (bool, error) {
+	refunded := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if recorded, err := recordPaymentEvent(tx, event); err != nil || !recorded {
+			return err
+		}
+		result := tx.Model(&model.PaymentOrder{}).
+			Where("id = ? AND status = ?", order.ID, model.PaymentStatusPaid).
+			Updates(map[string]interface{}{"status": model.PaymentStatusRefunded, "refunded_at": refundedAt})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		refunded = true
+
+		if order.SubscriptionID != nil {
+			if err := tx.Model(&model.MemberSubscription{}).
+				Where("id = ? AND status = ?", *order.SubscriptionID, model.SubscriptionStatusActive).
+				Updates(map[string]interface{}{"status": model.SubscriptionStatusCanceled, "ends_on": endsOn}).Error; err != nil {
+				return err
+			}
+		}
+
+		balance, err := creditBalance(tx, order.UserID)
+		if err != nil {
+			return err
+		}
+		if balance < credits {
+			credits = balance
+		}
+		if credits <= 0 {
+			return nil
+		}
+		return appendLedgerEntry(tx, &model.CreditLedgerEntry{
+			UserID:         order.UserID,
+			Delta:          -credits,
+			Reason:         model.CreditReasonAdjustment,
+			Note:           "payment refunded",
+			SubscriptionID: order.SubscriptionID,
+			ActorID:        actorID,
+		}, false)
+	})
+	return refunded, err
+}
+
+// RecordPaymentEvent stores a webhook event that changes no order; a redelivered event is ignored.
+func RecordPaymentEvent(event *model.PaymentEvent) error {
+	_, err := recordPaymentEvent(db.DB, event)
+	return err
+}
+
+// recordPaymentEvent inserts a webhook event under its unique provider and event ID. Returns false when
+// the event is already stored, so a redelivery, even one racing the first delivery, applies nothing.
+// A nil event is always new.
+func recordPaymentEvent(tx *gorm.DB, event *model.PaymentEvent) (bool, error) {
+	if event == nil {
+		return true, nil
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// CreateRefreshToken inserts a refresh token.
+func CreateRefreshToken(token *model.RefreshToken) error {
+	return db.DB.Create(token).Error
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the hash of its value.
+func GetRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := db.DB.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken revokes an active token as rotated and inserts its replacement in one transaction.
+// Fails with "refresh token already used" when the token was revoked concurrently.
+func RotateRefreshToken(id uint, now time.Time, next *model.RefreshToken) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": model.RefreshRevokedRotated})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("refresh token already used")
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeRefreshTokenFamily revokes the active tokens of one session.
+func RevokeRefreshTokenFamily(familyID string, reason string, now time.Time) error {
+	return db.DB.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason}).Error
+}
+
+// RevokeUserRefreshTokens revokes the active tokens of every session of a user.
+func RevokeUserRefreshTokens(userID uint, reason string, now time.Time) (int64, error) {
+	result := db.DB.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpiredRefreshTokens removes tokens that expired before now. Revoked tokens are kept until
+// then so a replayed token is still recognised.
+func DeleteExpiredRefreshTokens(now time.Time) (int64, error) {
+	result := db.DB.Where("expires_at < ?", now).Delete(&model.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// ListRooms returns all rooms ordered by name.
+func ListRooms() ([]model.Room, error) {
+	var rooms []model.Room
+	if err := db.DB.Order("name ASC").Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+// GetRoomByID retrieves a room by ID.
+func GetRoomByID(id uint) (*model.Room, error) {
+	var room model.Room
+	if err := db.DB.First(&room, id).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// CheckRoomNameTaken reports whether another room already uses the name.
+func CheckRoomNameTaken(name string, excludeID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.Room{}).
+		Where("LOWER(name) = LOWER(?) AND id != ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateRoom inserts a room.
+func CreateRoom(room *model.Room) error {
+	return db.DB.Create(room).Error
+}
+
+// UpdateRoom saves a room.
+func UpdateRoom(room *model.Room) error {
+	return db.DB.Save(room).Error
+}
+
+// DeleteRoomByID deletes a room.
+func DeleteRoomByID(id uint) error {
+	return db.DB.Delete(&model.Room{}, id).Error
+}
+
+// CountRoomUsage returns how many courses and sessions are assigned to a room.
+func CountRoomUsage(roomID uint) (int64, error) {
+	var courses, sessions int64
+	if err := db.DB.Model(&model.Course{}).Where("room_id = ?", roomID).Count(&courses).Error; err != nil {
+		return 0, err
+	}
+	if err := db.DB.Model(&model.ClassSession{}).Where("room_id = ?", roomID).Count(&sessions).Error; err != nil {
+		return 0, err
+	}
+	return courses + sessions, nil
+}
+
+// GetMaxCourseCapacityInRoom returns the largest capacity among courses held in a room.
+func GetMaxCourseCapacityInRoom(roomID uint) (int, error) {
+	var capacity int
+	if err := db.DB.Model(&model.Course{}).
+		Where("room_id = ?", roomID).
+		Select("COALESCE(MAX(capacity), 0)").
+		Scan(&capacity).Error; err != nil {
+		return 0, err
+	}
+	return capacity, nil
+}
+
+// ListCoursesByRoom returns the courses held in a room.
+func ListCoursesByRoom(roomID uint) ([]model.Course, error) {
+	var courses []model.Course
+	if err := db.DB.Where("room_id = ?", roomID).Preload("Slots", orderedSlots).Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// ListScheduledSessionsInRoom returns scheduled sessions held in a room between two dates (inclusive).
+// A session is in the room when its own room_id, or else its course's room_id, matches.
+func ListScheduledSessionsInRoom(roomID uint, fromDate string, toDate string) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Joins(`JOIN "Course" ON "Course".id = ClassSession.course_id`).
+		Where("ClassSession.status = 'scheduled' AND ClassSession.session_date BETWEEN ? AND ?", fromDate, toDate).
+		Where(`COALESCE(ClassSession.room_id, "Course".room_id) = ?`, roomID).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SetSessionRoom assigns (or clears, when roomID is nil) the room of a session and updates its capacity.
+func SetSessionRoom(sessionID uint, roomID *uint, capacity int) error {
+	return db.DB.Model(&model.ClassSession{}).
+		Where("id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"room_id":  roomID,
+			"capacity": capacity,
+		}).Error
+}
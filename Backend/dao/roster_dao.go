@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// ListSessionsByCourse returns every session of a course in start order.
+func ListSessionsByCourse(courseID uint) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Where("course_id = ?", courseID).
+		Order("start_at ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CountSessionHeadcounts returns seated, waitlisted and checked-in counts keyed by session ID.
+func CountSessionHeadcounts(sessionIDs []uint) (map[uint]model.SessionHeadcount, error) {
+	counts := make(map[uint]model.SessionHeadcount, len(sessionIDs))
+	if len(sessionIDs) == 0 {
+		return counts, nil
+	}
+
+	type countRow struct {
+		SessionID  uint
+		Seated     int64
+		Waitlisted int64
+		CheckedIn  int64
+	}
+
+	var rows []countRow
+	if err := db.DB.Model(&model.Enrollment{}).
+		Select(`session_id,
+			SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS seated,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS waitlisted,
+			SUM(CASE WHEN checked_in_at IS NOT NULL THEN 1 ELSE 0 END) AS checked_in`,
+			markableStatuses, model.EnrollmentStatusWaitlisted).
+		Where("session_id IN ?", sessionIDs).
+		Group("session_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.SessionID] = model.SessionHeadcount{Seated: row.Seated, Waitlisted: row.Waitlisted, CheckedIn: row.CheckedIn}
+	}
+	return counts, nil
+}
+
+// ListSessionRosterEnrollments returns a session's seated, marked and waitlisted enrollments with their users.
+func ListSessionRosterEnrollments(sessionID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Preload("User").
+		Where("session_id = ? AND status IN ?", sessionID, append([]string{model.EnrollmentStatusWaitlisted}, markableStatuses...)).
+		Order("id ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// ListUserInfosByUserIDs returns the user_info rows of the given users keyed by user ID.
+func ListUserInfosByUserIDs(userIDs []uint) (map[uint]model.UserInfo, error) {
+	infos := make(map[uint]model.UserInfo, len(userIDs))
+	if len(userIDs) == 0 {
+		return infos, nil
+	}
+
+	var rows []model.UserInfo
+	if err := db.DB.Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		infos[row.UserID] = row
+	}
+	return infos, nil
+}
+
+// ListCourseAttendanceRecords returns the attended and missed enrollments of the given users in a course,
+// leaving out one session, with their sessions.
+func ListCourseAttendanceRecords(courseID uint, userIDs []uint, excludeSessionID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if len(userIDs) == 0 {
+		return enrollments, nil
+	}
+	if err := db.DB.Preload("Session").
+		Where("course_id = ? AND user_id IN ? AND status IN ? AND (session_id IS NULL OR session_id != ?)",
+			courseID, userIDs, []string{model.EnrollmentStatusAttended, model.EnrollmentStatusMissed}, excludeSessionID).
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
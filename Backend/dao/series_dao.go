@@ -0,0 +1,131 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateBookingSeries inserts a new booking series.
+func CreateBookingSeries(series *model.BookingSeries) error {
+	return db.DB.Create(series).Error
+}
+
+// GetBookingSeriesByID retrieves a booking series by ID.
+func GetBookingSeriesByID(id uint) (*model.BookingSeries, error) {
+	var series model.BookingSeries
+	if err := db.DB.First(&series, id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// CountActiveBookingSeries returns how many active series a user holds for a course.
+func CountActiveBookingSeries(userID uint, courseID uint) (int64, error) {
+	var count int64
+	if err := db.DB.Model(&model.BookingSeries{}).
+		Where("user_id = ? AND course_id = ? AND status = ?", userID, courseID, model.BookingSeriesStatusActive).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListActiveBookingSeriesByCourse returns active series for a course.
+func ListActiveBookingSeriesByCourse(courseID uint) ([]model.BookingSeries, error) {
+	var series []model.BookingSeries
+	if err := db.DB.Where("course_id = ? AND status = ?", courseID, model.BookingSeriesStatusActive).
+		Order("id ASC").
+		Find(&series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// ListActiveBookingSeriesByUser returns a user's active series.
+func ListActiveBookingSeriesByUser(userID uint) ([]model.BookingSeries, error) {
+	var series []model.BookingSeries
+	if err := db.DB.Where("user_id = ? AND status = ?", userID, model.BookingSeriesStatusActive).
+		Order("id ASC").
+		Find(&series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// ListBookingSeriesByUser returns all of a user's series with course and per-session items.
+func ListBookingSeriesByUser(userID uint) ([]model.BookingSeries, error) {
+	var series []model.BookingSeries
+	if err := db.DB.Where("user_id = ?", userID).
+		Preload("Course").
+		Preload("Items", func(tx *gorm.DB) *gorm.DB {
+			return tx.Joins("Session").Order("Session.session_date ASC")
+		}).
+		Order("id DESC").
+		Find(&series).Error; err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// ListBookingSeriesItems returns the recorded items of a series keyed by session ID.
+func ListBookingSeriesItems(seriesID uint) (map[uint]model.BookingSeriesItem, error) {
+	var items []model.BookingSeriesItem
+	if err := db.DB.Where("series_id = ?", seriesID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	bySession := make(map[uint]model.BookingSeriesItem, len(items))
+	for _, item := range items {
+		bySession[item.SessionID] = item
+	}
+	return bySession, nil
+}
+
+// UpsertBookingSeriesItem records the outcome for a session in a series.
+func UpsertBookingSeriesItem(seriesID uint, sessionID uint, status string) error {
+	item := model.BookingSeriesItem{
+		SeriesID:  seriesID,
+		SessionID: sessionID,
+		Status:    status,
+		UpdatedAt: time.Now(),
+	}
+	return db.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "series_id"}, {Name: "session_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+	}).Create(&item).Error
+}
+
+// ListScheduledSessionsInRange returns scheduled sessions of a course between two dates (inclusive).
+func ListScheduledSessionsInRange(courseID uint, startDate string, endDate string) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Where("course_id = ? AND status = 'scheduled' AND session_date BETWEEN ? AND ?", courseID, startDate, endDate).
+		Order("session_date ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// MarkSeriesItemsDropped stops series of a user from re-booking a session the user dropped.
+func MarkSeriesItemsDropped(userID uint, sessionID uint) error {
+	return db.DB.Model(&model.BookingSeriesItem{}).
+		Where("session_id = ? AND status = ? AND series_id IN (SELECT id FROM BookingSeries WHERE user_id = ?)",
+			sessionID, model.SeriesItemBooked, userID).
+		Updates(map[string]interface{}{
+			"status":     model.SeriesItemDropped,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// UpdateBookingSeriesStatus sets the status of a series.
+func UpdateBookingSeriesStatus(id uint, status string) error {
+	return db.DB.Model(&model.BookingSeries{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
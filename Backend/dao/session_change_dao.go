@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// CancelSessionTx marks a session as canceled within tx, cancels its enrolled and waitlisted rows,
+// and records the change and one notification per affected enrollment.
+// message builds the notification text for each affected enrollment.
+func CancelSessionTx(tx *gorm.DB, session *model.ClassSession, entry *model.SessionChangeLog, message string) ([]model.SessionNotification, error) {
+	if err := tx.Model(&model.ClassSession{}).
+		Where("id = ?", session.ID).
+		Update("status", "canceled").Error; err != nil {
+		return nil, err
+	}
+
+	affected, err := listActiveSessionEnrollments(tx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(affected) > 0 {
+		ids := make([]uint, 0, len(affected))
+		for _, e := range affected {
+			ids = append(ids, e.ID)
+		}
+		if err := tx.Model(&model.Enrollment{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":            model.EnrollmentStatusCanceled,
+				"waitlist_position": nil,
+			}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	notifications, err := recordSessionChange(tx, entry, affected, model.SessionNotificationCanceled, message)
+	if err != nil {
+		return nil, err
+	}
+	session.Status = "canceled"
+	return notifications, nil
+}
+
+// RescheduleSession moves a session to a new time and capacity. Its enrolled and waitlisted rows
+// move along with it; the change and one notification per affected enrollment are recorded.
+func RescheduleSession(session *model.ClassSession, startAt time.Time, endAt time.Time, capacity int, entry *model.SessionChangeLog, message string) ([]model.SessionNotification, error) {
+	rescheduledFrom := session.SessionDate
+	if session.RescheduledFrom != nil {
+		rescheduledFrom = *session.RescheduledFrom
+	}
+	sessionDate := model.FacilityDate(startAt)
+
+	var notifications []model.SessionNotification
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ClassSession{}).
+			Where("id = ?", session.ID).
+			Updates(map[string]interface{}{
+				"session_date":     sessionDate,
+				"start_at":         startAt.UTC(),
+				"end_at":           endAt.UTC(),
+				"capacity":         capacity,
+				"rescheduled_from": rescheduledFrom,
+			}).Error; err != nil {
+			return err
+		}
+
+		affected, err := listActiveSessionEnrollments(tx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		notifications, err = recordSessionChange(tx, entry, affected, model.SessionNotificationMoved, message)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.SessionDate = sessionDate
+	session.StartAt = startAt
+	session.EndAt = endAt
+	session.Capacity = capacity
+	session.RescheduledFrom = &rescheduledFrom
+	return notifications, nil
+}
+
+// listActiveSessionEnrollments returns the enrolled and waitlisted rows of a session.
+func listActiveSessionEnrollments(tx *gorm.DB, sessionID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := tx.Where("session_id = ? AND status IN ?", sessionID,
+		[]string{model.EnrollmentStatusEnrolled, model.EnrollmentStatusWaitlisted}).
+		Order("id ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// recordSessionChange inserts the audit entry and a notification for each affected enrollment.
+func recordSessionChange(tx *gorm.DB, entry *model.SessionChangeLog, affected []model.Enrollment, outcome string, message string) ([]model.SessionNotification, error) {
+	entry.AffectedEnrollments = len(affected)
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, err
+	}
+
+	notifications := make([]model.SessionNotification, 0, len(affected))
+	for _, e := range affected {
+		notifications = append(notifications, model.SessionNotification{
+			UserID:       e.UserID,
+			SessionID:    entry.SessionID,
+			ChangeID:     entry.ID,
+			EnrollmentID: e.ID,
+			Outcome:      outcome,
+			Message:      message,
+		})
+	}
+	if len(notifications) > 0 {
+		if err := tx.Create(&notifications).Error; err != nil {
+			return nil, err
+		}
+	}
+	return notifications, nil
+}
+
+// CheckSessionDateTaken reports whether another session of the course is on the given date.
+func CheckSessionDateTaken(courseID uint, sessionDate string, excludeSessionID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.ClassSession{}).
+		Where("course_id = ? AND session_date = ? AND id != ?", courseID, sessionDate, excludeSessionID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListRescheduledSessionDates returns the original dates of a course's moved sessions.
+func ListRescheduledSessionDates(courseID uint) (map[string]bool, error) {
+	var dates []string
+	if err := db.DB.Model(&model.ClassSession{}).
+		Where("course_id = ? AND rescheduled_from IS NOT NULL", courseID).
+		Pluck("rescheduled_from", &dates).Error; err != nil {
+		return nil, err
+	}
+
+	moved := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		moved[d] = true
+	}
+	return moved, nil
+}
+
+// ListSessionChanges returns the audit entries of a session, newest first.
+func ListSessionChanges(sessionID uint) ([]model.SessionChangeLog, error) {
+	var changes []model.SessionChangeLog
+	if err := db.DB.Where("session_id = ?", sessionID).
+		Order("id DESC").
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ListSessionNotificationsByUser returns a user's session notifications, newest first.
+func ListSessionNotificationsByUser(userID uint) ([]model.SessionNotification, error) {
+	var notifications []model.SessionNotification
+	if err := db.DB.Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSessionByID retrieves a class session with its course.
+func GetSessionByID(id uint) (*model.ClassSession, error) {
+	var session model.ClassSession
+	if err := db.DB.Preload("Course").First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListUpcomingSessionsByCourse returns scheduled sessions of a course from today onward.
+func ListUpcomingSessionsByCourse(courseID uint) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Where("course_id = ? AND status = 'scheduled' AND session_date >= ?", courseID, model.FacilityToday()).
+		Order("session_date ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CountSeatedEnrollmentsBySessions returns seated (enrolled, attended or missed) enrollment counts keyed by session ID.
+func CountSeatedEnrollmentsBySessions(sessionIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(sessionIDs))
+	if len(sessionIDs) == 0 {
+		return counts, nil
+	}
+
+	type countRow struct {
+		SessionID uint
+		Total     int64
+	}
+
+	var rows []countRow
+	if err := db.DB.Model(&model.Enrollment{}).
+		Select("session_id, COUNT(*) AS total").
+		Where("session_id IN ? AND status IN ?", sessionIDs, markableStatuses).
+		Group("session_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.SessionID] = row.Total
+	}
+	return counts, nil
+}
+
+// CountEnrollmentsBySession returns the number of enrollments holding a seat in a session.
+func CountEnrollmentsBySession(sessionID uint) (int64, error) {
+	counts, err := CountSeatedEnrollmentsBySessions([]uint{sessionID})
+	if err != nil {
+		return 0, err
+	}
+	return counts[sessionID], nil
+}
+
+// CheckSessionEnrollmentExists checks if a user already holds an enrollment for a session.
+func CheckSessionEnrollmentExists(userID uint, sessionID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.Enrollment{}).
+		Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListEnrolledSessionsByUser returns upcoming sessions a user holds a seat in.
+func ListEnrolledSessionsByUser(userID uint) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Joins("INNER JOIN Enrollment ON Enrollment.session_id = ClassSession.id").
+		Where("Enrollment.user_id = ? AND Enrollment.status = ? AND ClassSession.status = 'scheduled'", userID, model.EnrollmentStatusEnrolled).
+		Order("ClassSession.start_at ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdateScheduledSessionCapacityTx applies a new course capacity to scheduled sessions
+// that still use the previous course capacity. Per-session overrides are left alone.
+func UpdateScheduledSessionCapacityTx(tx *gorm.DB, courseID uint, previousCapacity int, capacity int) error {
+	return tx.Model(&model.ClassSession{}).
+		Where("course_id = ? AND status = 'scheduled' AND (capacity = ? OR capacity IS NULL OR capacity = 0)", courseID, previousCapacity).
+		Update("capacity", capacity).Error
+}
+
+// CreateClassSessionsTx inserts planned sessions within tx, skipping dates a course already has a session on.
+func CreateClassSessionsTx(tx *gorm.DB, sessions []model.ClassSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sessions).Error
+}
+
+// CompletePastSessions marks scheduled sessions that ended before now as completed.
+// Returns the number of sessions that were updated.
+func CompletePastSessions(now time.Time) (int64, error) {
+	result := db.DB.Model(&model.ClassSession{}).
+		Where("status = 'scheduled' AND end_at < ?", now.UTC()).
+		Update("status", "completed")
+	return result.RowsAffected, result.Error
+}
+
+// ListScheduledSessionsOnDate returns the scheduled sessions of every course on one date.
+func ListScheduledSessionsOnDate(sessionDate string) ([]model.ClassSession, error) {
+	var sessions []model.ClassSession
+	if err := db.DB.Preload("Course").
+		Where("session_date = ? AND status = 'scheduled'", sessionDate).
+		Order("start_at ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListScheduledSessionsOutsideTerm returns a course's upcoming scheduled sessions before startsOn or after endsOn.
+// Empty bounds are open-ended.
+func ListScheduledSessionsOutsideTerm(courseID uint, startsOn string, endsOn string) ([]model.ClassSession, error) {
+	query := db.DB.Preload("Course").
+		Where("course_id = ? AND status = 'scheduled' AND session_date >= ?", courseID, model.FacilityToday())
+	switch {
+	case startsOn != "" && endsOn != "":
+		query = query.Where("session_date < ? OR session_date > ?", startsOn, endsOn)
+	case startsOn != "":
+		query = query.Where("session_date < ?", startsOn)
+	case endsOn != "":
+		query = query.Where("session_date > ?", endsOn)
+	default:
+		return nil, nil
+	}
+
+	var sessions []model.ClassSession
+	if err := query.Order("session_date ASC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
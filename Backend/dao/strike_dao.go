@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// CreateMemberStrike inserts a strike.
+func CreateMemberStrike(strike *model.MemberStrike) error {
+	return db.DB.Create(strike).Error
+}
+
+// ListActiveStrikesByUser returns the user's uncleared strikes that occurred at or after since, oldest first.
+func ListActiveStrikesByUser(userID uint, since time.Time) ([]model.MemberStrike, error) {
+	var strikes []model.MemberStrike
+	if err := db.DB.Where("user_id = ? AND cleared_at IS NULL AND occurred_at >= ?", userID, since.UTC()).
+		Order("occurred_at ASC").
+		Find(&strikes).Error; err != nil {
+		return nil, err
+	}
+	return strikes, nil
+}
+
+// ListStrikesByUser returns all of the user's strikes, newest first.
+func ListStrikesByUser(userID uint) ([]model.MemberStrike, error) {
+	var strikes []model.MemberStrike
+	if err := db.DB.Where("user_id = ?", userID).
+		Order("occurred_at DESC").
+		Find(&strikes).Error; err != nil {
+		return nil, err
+	}
+	return strikes, nil
+}
+
+// GetMemberStrikeByID retrieves a strike by ID.
+func GetMemberStrikeByID(id uint) (*model.MemberStrike, error) {
+	var strike model.MemberStrike
+	if err := db.DB.First(&strike, id).Error; err != nil {
+		return nil, err
+	}
+	return &strike, nil
+}
+
+// ClearMemberStrikes marks the user's uncleared strikes as cleared. A non-zero strikeID clears only that strike.
+// Returns the number of strikes cleared.
+func ClearMemberStrikes(userID uint, strikeID uint, clearedBy uint, now time.Time) (int64, error) {
+	query := db.DB.Model(&model.MemberStrike{}).Where("user_id = ? AND cleared_at IS NULL", userID)
+	if strikeID != 0 {
+		query = query.Where("id = ?", strikeID)
+	}
+	result := query.Updates(map[string]interface{}{"cleared_at": now.UTC(), "cleared_by": clearedBy})
+	return result.RowsAffected, result.Error
+}
+
+// ListEnrollmentsForStatusUpdate returns the user's enrollments in the given sessions,
+// or in the whole course when sessionIDs is nil, with their sessions loaded.
+func ListEnrollmentsForStatusUpdate(userID uint, courseID uint, sessionIDs []uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	query := db.DB.Preload("Session").Where("user_id = ?", userID)
+	if sessionIDs != nil {
+		query = query.Where("session_id IN ?", sessionIDs)
+	} else {
+		query = query.Where("course_id = ?", courseID)
+	}
+	if err := query.Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// CheckNoShowStrikeExists reports whether an enrollment already has a no-show strike, cleared or not.
+func CheckNoShowStrikeExists(enrollmentID uint) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.MemberStrike{}).
+		Where("enrollment_id = ? AND reason = ?", enrollmentID, model.StrikeReasonNoShow).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteNoShowStrikes removes uncleared no-show strikes of enrollments that are no longer marked missed.
+func DeleteNoShowStrikes(enrollmentIDs []uint) error {
+	if len(enrollmentIDs) == 0 {
+		return nil
+	}
+	return db.DB.Where("enrollment_id IN ? AND reason = ? AND cleared_at IS NULL", enrollmentIDs, model.StrikeReasonNoShow).
+		Delete(&model.MemberStrike{}).Error
+}
@@ -51,7 +51,7 @@ func GetUserProfileByID(id uint) (*model.UserProfile, error) {
 	var profile model.UserProfile
 
 	err := db.DB.Table("User").
-		Select("User.name, User.email, User.avatar_url, user_info.date_of_birth, user_info.gender, user_info.phone_number, user_info.address").
+		Select("User.name, User.email, User.avatar_url, User.email_verified_at, user_info.date_of_birth, user_info.gender, user_info.phone_number, user_info.address, user_info.notes").
 		Joins("left join user_info on user_info.user_id = User.id").
 		Where("User.id = ?", id).
 		Scan(&profile).Error
@@ -81,6 +81,12 @@ func UpdateUserProfile(id uint, p model.UserProfile) error {
 			}
 		}
 
+		if p.Name != nil {
+			if err := syncInstructorName(tx, id, *p.Name); err != nil {
+				return err
+			}
+		}
+
 		var info model.UserInfo
 		err := tx.Where("user_id = ?", id).First(&info).Error
 
@@ -98,6 +104,9 @@ func UpdateUserProfile(id uint, p model.UserProfile) error {
 		if p.Address != nil {
 			infoUpdates["address"] = *p.Address
 		}
+		if p.Notes != nil {
+			infoUpdates["notes"] = *p.Notes
+		}
 
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			if len(infoUpdates) == 0 {
@@ -117,6 +126,9 @@ func UpdateUserProfile(id uint, p model.UserProfile) error {
 			if p.Address != nil {
 				newInfo.Address = *p.Address
 			}
+			if p.Notes != nil {
+				newInfo.Notes = *p.Notes
+			}
 
 			return tx.Create(&newInfo).Error
 		}
@@ -160,6 +172,12 @@ func UpdateUserProfilePatch(id uint, p model.UserProfilePatch) error {
 			}
 		}
 
+		if p.Name.Set && p.Name.Valid {
+			if err := syncInstructorName(tx, id, p.Name.Value); err != nil {
+				return err
+			}
+		}
+
 		/* -------------------- user_info table -------------------- */
 		var info model.UserInfo
 		err := tx.Where("user_id = ?", id).First(&info).Error
@@ -194,6 +212,13 @@ func UpdateUserProfilePatch(id uint, p model.UserProfilePatch) error {
 				infoUpdates["address"] = nil
 			}
 		}
+		if p.Notes.Set {
+			if p.Notes.Valid {
+				infoUpdates["notes"] = p.Notes.Value
+			} else {
+				infoUpdates["notes"] = nil
+			}
+		}
 
 		// If user_info doesn't exist, create it when there is at least one field to set.
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -201,7 +226,7 @@ func UpdateUserProfilePatch(id uint, p model.UserProfilePatch) error {
 			// - create a row with NULLs (allowed if columns nullable)
 			// - OR do nothing
 			// Here: if any field is Set (even null), we create the row.
-			shouldCreate := p.DateOfBirth.Set || p.Gender.Set || p.PhoneNumber.Set || p.Address.Set
+			shouldCreate := p.DateOfBirth.Set || p.Gender.Set || p.PhoneNumber.Set || p.Address.Set || p.Notes.Set
 			if !shouldCreate {
 				return nil
 			}
@@ -223,6 +248,9 @@ func UpdateUserProfilePatch(id uint, p model.UserProfilePatch) error {
 			if p.Address.Set && p.Address.Valid {
 				newInfo.Address = p.Address.Value
 			}
+			if p.Notes.Set && p.Notes.Valid {
+				newInfo.Notes = p.Notes.Value
+			}
 
 			return tx.Create(&newInfo).Error
 		}
@@ -255,4 +283,16 @@ func UpdateUserRoleByID(userID uint, roleID uint) error {
     return db.DB.Model(&model.User{}).
         Where("id = ?", userID).
         Update("role_id", roleID).Error
-}
\ No newline at end of file
+}
+
+// syncInstructorName keeps the instructor profile name and its courses' display name in sync with User.name.
+func syncInstructorName(tx *gorm.DB, userID uint, name string) error {
+	if err := tx.Model(&model.Instructor{}).
+		Where("user_id = ?", userID).
+		Update("name", name).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.Course{}).
+		Where("instructor_id IN (SELECT id FROM Instructor WHERE user_id = ?)", userID).
+		Update("instructor", name).Error
+}
@@ -0,0 +1,167 @@
+package dao
+
+import (
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// CreateWaitlistEnrollment inserts a waitlisted enrollment at the end of its session's queue.
+// The enrollment's SessionID must be set.
+func CreateWaitlistEnrollment(enrollment *model.Enrollment) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&model.Enrollment{}).
+			Select("COALESCE(MAX(waitlist_position), 0)").
+			Where("session_id = ? AND status = ?", *enrollment.SessionID, model.EnrollmentStatusWaitlisted).
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		position := last + 1
+		enrollment.Status = model.EnrollmentStatusWaitlisted
+		enrollment.WaitlistPosition = &position
+		return tx.Create(enrollment).Error
+	})
+}
+
+// PromoteWaitlistedEnrollments moves waitlisted enrollments of a session into the seats not held by an
+// enrolled, attended or missed enrollment, in queue order, and returns the promoted rows. Enrollments in
+// passedOver stay queued. Remaining positions are compacted.
+func PromoteWaitlistedEnrollments(sessionID uint, capacity int, passedOver []uint) ([]model.Enrollment, error) {
+	var promoted []model.Enrollment
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		promoted, err = PromoteWaitlistedEnrollmentsTx(tx, sessionID, capacity, passedOver)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// PromoteWaitlistedEnrollmentsTx is PromoteWaitlistedEnrollments within tx.
+func PromoteWaitlistedEnrollmentsTx(tx *gorm.DB, sessionID uint, capacity int, passedOver []uint) ([]model.Enrollment, error) {
+	var seated int64
+	if err := tx.Model(&model.Enrollment{}).
+		Where("session_id = ? AND status IN ?", sessionID, markableStatuses).
+		Count(&seated).Error; err != nil {
+		return nil, err
+	}
+
+	open := capacity - int(seated)
+	if open <= 0 {
+		return nil, nil
+	}
+
+	var promoted []model.Enrollment
+	query := tx.Where("session_id = ? AND status = ?", sessionID, model.EnrollmentStatusWaitlisted)
+	if len(passedOver) > 0 {
+		query = query.Where("id NOT IN ?", passedOver)
+	}
+	if err := query.Order("waitlist_position ASC, id ASC").
+		Limit(open).
+		Find(&promoted).Error; err != nil {
+		return nil, err
+	}
+	if len(promoted) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(promoted))
+	for i := range promoted {
+		ids = append(ids, promoted[i].ID)
+		promoted[i].Status = model.EnrollmentStatusEnrolled
+		promoted[i].WaitlistPosition = nil
+	}
+
+	if err := tx.Model(&model.Enrollment{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":            model.EnrollmentStatusEnrolled,
+			"waitlist_position": nil,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := renumberWaitlist(tx, sessionID); err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// ListSessionWaitlist returns a session's waitlisted enrollments in queue order.
+func ListSessionWaitlist(sessionID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Where("session_id = ? AND status = ?", sessionID, model.EnrollmentStatusWaitlisted).
+		Order("waitlist_position ASC, id ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// renumberWaitlist rewrites waitlist positions of a session as 1..n, keeping their relative order.
+func renumberWaitlist(tx *gorm.DB, sessionID uint) error {
+	var queued []model.Enrollment
+	if err := tx.Where("session_id = ? AND status = ?", sessionID, model.EnrollmentStatusWaitlisted).
+		Order("waitlist_position ASC, id ASC").
+		Find(&queued).Error; err != nil {
+		return err
+	}
+
+	for i := range queued {
+		position := i + 1
+		if queued[i].WaitlistPosition != nil && *queued[i].WaitlistPosition == position {
+			continue
+		}
+		if err := tx.Model(&model.Enrollment{}).
+			Where("id = ?", queued[i].ID).
+			Update("waitlist_position", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListWaitlistedSessionIDsByCourse returns scheduled sessions of a course that have a non-empty waitlist.
+func ListWaitlistedSessionIDsByCourse(courseID uint) ([]uint, error) {
+	var ids []uint
+	if err := db.DB.Model(&model.Enrollment{}).
+		Distinct("session_id").
+		Where(`course_id = ? AND status = ? AND session_id IN (
+			SELECT id FROM ClassSession WHERE course_id = ? AND status = 'scheduled'
+		)`, courseID, model.EnrollmentStatusWaitlisted, courseID).
+		Pluck("session_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListWaitlistByUser returns a user's waitlisted enrollments for upcoming sessions.
+func ListWaitlistByUser(userID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Joins("Session").
+		Preload("Course").
+		Where("Enrollment.user_id = ? AND Enrollment.status = ? AND Session.status = 'scheduled'", userID, model.EnrollmentStatusWaitlisted).
+		Order("Session.session_date ASC, Enrollment.waitlist_position ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// ListWaitlistByCourse returns the waitlist of every upcoming session of a course, in queue order.
+func ListWaitlistByCourse(courseID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	if err := db.DB.Joins("Session").
+		Preload("User").
+		Where("Enrollment.course_id = ? AND Enrollment.status = ? AND Session.status = 'scheduled'", courseID, model.EnrollmentStatusWaitlisted).
+		Order("Session.session_date ASC, Enrollment.waitlist_position ASC").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
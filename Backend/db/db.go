@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"my-course-backend/model"
+
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
@@ -25,20 +27,42 @@ func InitDB() {
 	}
 	DB.Exec("PRAGMA foreign_keys = ON;")
 	migrateUserInfoTable()
+	ensureUserInfoNotesColumn()
+	ensureUserEmailVerifiedColumn()
 	migrateEnrollmentTable()
-	migrateCourseInstructorColumn()
 	migrateCourseInstructorToName()
 	ensureInstructorTable()
 	ensureInstructorNameColumn()
+	ensureInstructorProfileColumns()
+	ensureInstructorAvailabilityTable()
+	ensureRoomTable()
+	ensureCourseSlotTable()
+	ensureCourseTermColumns()
+	ensureBlackoutDateTable()
+	ensureBookingPolicyTable()
+	ensureMemberStrikeTable()
+	ensureMembershipTables()
+	ensurePaymentTables()
+	ensureRefreshTokenTable()
+	ensureAccountTokenTable()
+	migrateCourseInstructorID()
 	ensureUserDailyActivityTable()
 	ensureClassSessionTable()
+	migrateClassSessionTimesToUTC()
 	migrateClassSessions()
 	migrateEnrollmentSessionIDs()
+	ensureEnrollmentCheckInColumns()
 	ensureEnrollmentUniqueConstraint()
+	ensureEnrollmentWaitlistColumn()
+	ensureBookingSeriesTables()
+	ensureSessionChangeTables()
+	ensureCourseInstructorTable()
 	// Normalize TIME values to HH:MM:SS for consistent scanning.
 	if DB.Migrator().HasTable("Course") {
 		DB.Exec("UPDATE Course SET start_time = start_time || ':00' WHERE start_time IS NOT NULL AND length(start_time) = 5;")
 		DB.Exec("UPDATE Course SET end_time = end_time || ':00' WHERE end_time IS NOT NULL AND length(end_time) = 5;")
+		DB.Exec("UPDATE CourseSlot SET start_time = start_time || ':00' WHERE length(start_time) = 5;")
+		DB.Exec("UPDATE CourseSlot SET end_time = end_time || ':00' WHERE length(end_time) = 5;")
 	}
 
 	log.Printf("Database connected and foreign keys enabled. Using %s", dbPath)
@@ -118,6 +142,30 @@ func migrateUserInfoTable() {
 	}
 }
 
+func ensureUserInfoNotesColumn() {
+	if DB == nil || !DB.Migrator().HasTable("user_info") || DB.Migrator().HasColumn("user_info", "notes") {
+		return
+	}
+	if err := DB.Exec(`ALTER TABLE user_info ADD COLUMN notes TEXT;`).Error; err != nil {
+		log.Printf("Failed to add notes column to user_info: %v", err)
+	}
+}
+
+// ensureUserEmailVerifiedColumn adds User.email_verified_at. Accounts that predate email verification
+// are treated as verified from their creation.
+func ensureUserEmailVerifiedColumn() {
+	if DB == nil || !DB.Migrator().HasTable("User") || DB.Migrator().HasColumn("User", "email_verified_at") {
+		return
+	}
+	if err := DB.Exec(`ALTER TABLE "User" ADD COLUMN email_verified_at DATETIME;`).Error; err != nil {
+		log.Printf("Failed to add email_verified_at column to User: %v", err)
+		return
+	}
+	if err := DB.Exec(`UPDATE "User" SET email_verified_at = COALESCE(created_at, CURRENT_TIMESTAMP);`).Error; err != nil {
+		log.Printf("Failed to mark existing users verified: %v", err)
+	}
+}
+
 func migrateEnrollmentTable() {
 	if DB == nil {
 		return
@@ -168,6 +216,68 @@ func ensureClassSessionTable() {
 	}
 }
 
+// classSessionUTCMigration names the one-time rewrite of session times in SchemaMigration.
+const classSessionUTCMigration = "class_session_times_utc"
+
+// migrateClassSessionTimesToUTC rewrites ClassSession start_at/end_at written before session
+// instants were stored in UTC. Those rows hold facility wall-clock times labelled as UTC, so each
+// is reinterpreted in the facility time zone. Runs only once, recorded in SchemaMigration.
+func migrateClassSessionTimesToUTC() {
+	if DB == nil || !DB.Migrator().HasTable("ClassSession") {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "SchemaMigration" (
+			name VARCHAR(100) PRIMARY KEY,
+			applied_at DATETIME NOT NULL
+		);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure SchemaMigration table exists: %v", err)
+		return
+	}
+
+	loc := model.FacilityLocation()
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO SchemaMigration (name, applied_at) VALUES (?, ?) ON CONFLICT(name) DO NOTHING`,
+			classSessionUTCMigration, time.Now().UTC())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		type sessionRow struct {
+			ID      uint
+			StartAt time.Time
+			EndAt   time.Time
+		}
+		var rows []sessionRow
+		if err := tx.Raw(`SELECT id, start_at, end_at FROM ClassSession`).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := tx.Exec(`UPDATE ClassSession SET start_at = ?, end_at = ? WHERE id = ?`,
+				reinterpretWallClock(row.StartAt, loc), reinterpretWallClock(row.EndAt, loc), row.ID).Error; err != nil {
+				return err
+			}
+		}
+		log.Printf("migrateClassSessionTimesToUTC: reinterpreted %d session(s) in %s", len(rows), loc)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to migrate ClassSession times to UTC: %v", err)
+	}
+}
+
+// reinterpretWallClock reads the UTC clock fields of t as a wall-clock time in loc.
+func reinterpretWallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC()
+}
+
 // migrateClassSessions generates past ClassSession rows (completed) and marks
 // sessions on or before today as "completed". Runs only once — skips if past
 // sessions already exist.
@@ -183,6 +293,9 @@ func migrateClassSessions() {
 		return
 	}
 
+	// Session dates and class times are facility wall-clock values.
+	loc := model.FacilityLocation()
+
 	// Determine the earliest enrollment date so we generate sessions that far back.
 	var earliest string
 	DB.Raw(`SELECT MIN(DATE(enroll_time)) FROM Enrollment`).Scan(&earliest)
@@ -190,14 +303,14 @@ func migrateClassSessions() {
 		earliest = time.Now().AddDate(0, -3, 0).Format("2006-01-02")
 	}
 
-	startDate, err := time.Parse("2006-01-02", earliest)
+	startDate, err := time.ParseInLocation("2006-01-02", earliest, loc)
 	if err != nil {
 		log.Printf("migrateClassSessions: failed to parse earliest date: %v", err)
 		return
 	}
 
-	today := time.Now().UTC()
-	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	today := model.FacilityNow()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
 
 	weekdayMap := map[string]time.Weekday{
 		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
@@ -205,7 +318,7 @@ func migrateClassSessions() {
 		"sat": time.Saturday,
 	}
 
-	// Fetch all courses.
+	// Fetch every weekly slot of every course.
 	type courseRow struct {
 		ID        uint
 		Weekday   string
@@ -214,7 +327,8 @@ func migrateClassSessions() {
 		Capacity  int
 	}
 	var courses []courseRow
-	DB.Raw(`SELECT id, weekday, start_time, end_time, capacity FROM Course`).Scan(&courses)
+	DB.Raw(`SELECT s.course_id AS id, s.weekday, s.start_time, s.end_time, c.capacity
+		FROM CourseSlot s JOIN Course c ON c.id = s.course_id`).Scan(&courses)
 
 	now := time.Now()
 	inserted := 0
@@ -239,8 +353,8 @@ func migrateClassSessions() {
 
 		for d.Before(today) || d.Equal(today) {
 			sessionDate := d.Format("2006-01-02")
-			startAt := time.Date(d.Year(), d.Month(), d.Day(), startH, startM, 0, 0, time.UTC)
-			endAt := time.Date(d.Year(), d.Month(), d.Day(), endH, endM, 0, 0, time.UTC)
+			startAt := time.Date(d.Year(), d.Month(), d.Day(), startH, startM, 0, 0, loc)
+			endAt := time.Date(d.Year(), d.Month(), d.Day(), endH, endM, 0, 0, loc)
 
 			status := "completed"
 			if d.After(today) {
@@ -251,7 +365,7 @@ func migrateClassSessions() {
 				INSERT INTO ClassSession (course_id, session_date, start_at, end_at, status, capacity, created_at, updated_at)
 				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 				ON CONFLICT(course_id, session_date) DO UPDATE SET status = excluded.status
-			`, c.ID, sessionDate, startAt.Format(time.RFC3339), endAt.Format(time.RFC3339),
+			`, c.ID, sessionDate, startAt.UTC().Format(time.RFC3339), endAt.UTC().Format(time.RFC3339),
 				status, c.Capacity, now.Format(time.RFC3339), now.Format(time.RFC3339)).Error
 
 			if err != nil {
@@ -334,6 +448,223 @@ func ensureEnrollmentUniqueConstraint() {
 	}
 }
 
+// ensureEnrollmentWaitlistColumn adds Enrollment.waitlist_position for existing databases.
+func ensureEnrollmentWaitlistColumn() {
+	if DB == nil || !DB.Migrator().HasTable("Enrollment") {
+		return
+	}
+
+	if !DB.Migrator().HasColumn("Enrollment", "waitlist_position") {
+		if err := DB.Exec(`ALTER TABLE "Enrollment" ADD COLUMN waitlist_position INTEGER;`).Error; err != nil {
+			log.Printf("Failed to add waitlist_position to Enrollment: %v", err)
+			return
+		}
+	}
+
+	if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_enrollment_session_status ON Enrollment (session_id, status)`).Error; err != nil {
+		log.Printf("ensureEnrollmentWaitlistColumn: %v", err)
+	}
+}
+
+// ensureBookingSeriesTables creates the BookingSeries and BookingSeriesItem tables if missing.
+func ensureBookingSeriesTables() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "BookingSeries" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			created_at DATETIME,
+			updated_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (course_id) REFERENCES "Course"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_booking_series_user_id ON "BookingSeries" (user_id);
+		CREATE INDEX IF NOT EXISTS idx_booking_series_course_id ON "BookingSeries" (course_id);
+
+		CREATE TABLE IF NOT EXISTS "BookingSeriesItem" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			series_id INTEGER NOT NULL,
+			session_id INTEGER NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			updated_at DATETIME,
+			UNIQUE(series_id, session_id),
+			FOREIGN KEY (series_id) REFERENCES "BookingSeries"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (session_id) REFERENCES "ClassSession"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_booking_series_item_session_id ON "BookingSeriesItem" (session_id);
+	`
+
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure BookingSeries tables exist: %v", err)
+	}
+}
+
+// migrateCourseInstructorID links Course to Instructor by ID. It creates missing Instructor
+// profiles for instructor users, then resolves Course.instructor names to Instructor.id.
+// Names that match no profile or more than one profile are left unresolved and logged.
+// Safe to run multiple times; idempotent.
+func migrateCourseInstructorID() {
+	if DB == nil || !DB.Migrator().HasTable("Course") || !DB.Migrator().HasTable("Instructor") {
+		return
+	}
+
+	if !DB.Migrator().HasColumn("Course", "instructor_id") {
+		if err := DB.Exec(`ALTER TABLE "Course" ADD COLUMN instructor_id INTEGER REFERENCES "Instructor"(id) ON DELETE SET NULL;`).Error; err != nil {
+			log.Printf("Failed to add instructor_id to Course: %v", err)
+			return
+		}
+	}
+	if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_course_instructor_id ON "Course" (instructor_id)`).Error; err != nil {
+		log.Printf("migrateCourseInstructorID: %v", err)
+	}
+
+	if err := DB.Exec(`
+		INSERT INTO "Instructor" (user_id, name)
+		SELECT u.id, u.name
+		FROM "User" u
+		WHERE u.role_id = 4
+		AND NOT EXISTS (SELECT 1 FROM "Instructor" i WHERE i.user_id = u.id);
+	`).Error; err != nil {
+		log.Printf("Failed to create missing Instructor profiles: %v", err)
+	}
+
+	if err := DB.Exec(`
+		UPDATE "Course"
+		SET instructor_id = (
+			SELECT i.id FROM "Instructor" i
+			WHERE LOWER(TRIM(i.name)) = LOWER(TRIM("Course".instructor))
+		)
+		WHERE instructor_id IS NULL
+		AND TRIM(COALESCE(instructor, '')) != ''
+		AND (
+			SELECT COUNT(*) FROM "Instructor" i
+			WHERE LOWER(TRIM(i.name)) = LOWER(TRIM("Course".instructor))
+		) = 1;
+	`).Error; err != nil {
+		log.Printf("Failed to resolve Course.instructor_id from instructor names: %v", err)
+		return
+	}
+
+	var unresolved int64
+	DB.Raw(`SELECT COUNT(*) FROM "Course" WHERE instructor_id IS NULL AND TRIM(COALESCE(instructor, '')) != ''`).Scan(&unresolved)
+	if unresolved > 0 {
+		log.Printf("migrateCourseInstructorID: %d courses have an instructor name that matches no single Instructor profile", unresolved)
+	}
+}
+
+// ensureCourseInstructorTable creates the CourseInstructor table, backfills lead rows from
+// Course.instructor_id, and adds ClassSession.substitute_instructor_id.
+func ensureCourseInstructorTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "CourseInstructor" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			course_id INTEGER NOT NULL,
+			instructor_id INTEGER NOT NULL,
+			role VARCHAR(20) NOT NULL,
+			created_at DATETIME,
+			UNIQUE(course_id, instructor_id),
+			FOREIGN KEY (course_id) REFERENCES "Course"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (instructor_id) REFERENCES "Instructor"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_course_instructor_instructor_id ON "CourseInstructor" (instructor_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure CourseInstructor table exists: %v", err)
+		return
+	}
+
+	if DB.Migrator().HasTable("Course") && DB.Migrator().HasColumn("Course", "instructor_id") {
+		if err := DB.Exec(`
+			INSERT INTO "CourseInstructor" (course_id, instructor_id, role, created_at)
+			SELECT c.id, c.instructor_id, 'lead', CURRENT_TIMESTAMP
+			FROM "Course" c
+			WHERE c.instructor_id IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM "CourseInstructor" ci
+				WHERE ci.course_id = c.id AND ci.instructor_id = c.instructor_id
+			);
+		`).Error; err != nil {
+			log.Printf("Failed to backfill CourseInstructor lead rows: %v", err)
+		}
+	}
+
+	if DB.Migrator().HasTable("ClassSession") && !DB.Migrator().HasColumn("ClassSession", "substitute_instructor_id") {
+		if err := DB.Exec(`ALTER TABLE "ClassSession" ADD COLUMN substitute_instructor_id INTEGER REFERENCES "Instructor"(id) ON DELETE SET NULL;`).Error; err != nil {
+			log.Printf("Failed to add substitute_instructor_id to ClassSession: %v", err)
+			return
+		}
+	}
+	if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_class_session_substitute ON "ClassSession" (substitute_instructor_id)`).Error; err != nil {
+		log.Printf("ensureCourseInstructorTable: %v", err)
+	}
+}
+
+// ensureSessionChangeTables adds ClassSession.rescheduled_from and creates the
+// SessionChangeLog and SessionNotification tables if missing.
+func ensureSessionChangeTables() {
+	if DB == nil {
+		return
+	}
+
+	if DB.Migrator().HasTable("ClassSession") && !DB.Migrator().HasColumn("ClassSession", "rescheduled_from") {
+		if err := DB.Exec(`ALTER TABLE "ClassSession" ADD COLUMN rescheduled_from DATE;`).Error; err != nil {
+			log.Printf("Failed to add rescheduled_from to ClassSession: %v", err)
+		}
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "SessionChangeLog" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			action VARCHAR(20) NOT NULL,
+			actor_id INTEGER NOT NULL,
+			reason TEXT,
+			previous_start_at DATETIME NOT NULL,
+			previous_end_at DATETIME NOT NULL,
+			previous_capacity INTEGER,
+			new_start_at DATETIME,
+			new_end_at DATETIME,
+			new_capacity INTEGER,
+			affected_enrollments INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME,
+			FOREIGN KEY (session_id) REFERENCES "ClassSession"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_session_change_log_session_id ON "SessionChangeLog" (session_id);
+		CREATE INDEX IF NOT EXISTS idx_session_change_log_course_id ON "SessionChangeLog" (course_id);
+
+		CREATE TABLE IF NOT EXISTS "SessionNotification" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			session_id INTEGER NOT NULL,
+			change_id INTEGER NOT NULL,
+			enrollment_id INTEGER NOT NULL,
+			outcome VARCHAR(20) NOT NULL,
+			message TEXT NOT NULL,
+			created_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (change_id) REFERENCES "SessionChangeLog"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_session_notification_user_id ON "SessionNotification" (user_id);
+		CREATE INDEX IF NOT EXISTS idx_session_notification_change_id ON "SessionNotification" (change_id);
+	`
+
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure session change tables exist: %v", err)
+	}
+}
+
 // parseHHMM parses "HH:MM" or "HH:MM:SS" into hour and minute.
 func parseHHMM(s string) (int, int) {
 	s = strings.TrimSpace(s)
@@ -370,28 +701,20 @@ func ensureUserDailyActivityTable() {
 	}
 }
 
-func migrateCourseInstructorColumn() {
-	if DB == nil {
-		return
-	}
-
-	if DB.Migrator().HasTable("Course") && !DB.Migrator().HasColumn("Course", "instructor_id") {
-		if err := DB.Exec(`ALTER TABLE "Course" ADD COLUMN instructor_id INTEGER;`).Error; err != nil {
-			log.Printf("Failed to add instructor_id to Course: %v", err)
-		}
-	}
-}
-
-// migrateCourseInstructorToName migrates Course.instructor_id (INTEGER FK to User.id)
+// migrateCourseInstructorToName migrates the legacy Course.instructor_id (INTEGER FK to User.id)
 // to Course.instructor (TEXT — instructor's display name).
+// Only databases without the instructor column still hold the legacy User-based instructor_id;
+// the current instructor_id references Instructor.id and is handled by migrateCourseInstructorID.
 // Safe to run multiple times; idempotent.
 func migrateCourseInstructorToName() {
 	if DB == nil || !DB.Migrator().HasTable("Course") {
 		return
 	}
 
+	legacy := !DB.Migrator().HasColumn("Course", "instructor")
+
 	// Add new `instructor` column if missing.
-	if !DB.Migrator().HasColumn("Course", "instructor") {
+	if legacy {
 		if err := DB.Exec(`ALTER TABLE "Course" ADD COLUMN instructor TEXT;`).Error; err != nil {
 			log.Printf("Failed to add instructor column to Course: %v", err)
 			return
@@ -399,7 +722,7 @@ func migrateCourseInstructorToName() {
 	}
 
 	// Backfill instructor names from User table using the old instructor_id, if still present.
-	if DB.Migrator().HasColumn("Course", "instructor_id") {
+	if legacy && DB.Migrator().HasColumn("Course", "instructor_id") {
 		if err := DB.Exec(`
             UPDATE Course
             SET instructor = (SELECT name FROM "User" WHERE "User".id = Course.instructor_id)
@@ -463,3 +786,411 @@ func ensureInstructorNameColumn() {
 		log.Printf("Failed to backfill Instructor.name from User.name: %v", err)
 	}
 }
+
+// ensureInstructorProfileColumns adds the photo, specialties and certifications columns to Instructor.
+// Specialties and certifications are stored as JSON arrays.
+func ensureInstructorProfileColumns() {
+	if DB == nil || !DB.Migrator().HasTable("Instructor") {
+		return
+	}
+
+	for _, column := range []string{"photo_url", "specialties", "certifications"} {
+		if DB.Migrator().HasColumn("Instructor", column) {
+			continue
+		}
+		if err := DB.Exec(fmt.Sprintf(`ALTER TABLE "Instructor" ADD COLUMN %s TEXT;`, column)).Error; err != nil {
+			log.Printf("Failed to add %s column to Instructor: %v", column, err)
+		}
+	}
+}
+
+// ensureInstructorAvailabilityTable creates the InstructorAvailability table if missing.
+func ensureInstructorAvailabilityTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "InstructorAvailability" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			instructor_id INTEGER NOT NULL,
+			weekday VARCHAR(16) NOT NULL,
+			start_time TIME NOT NULL,
+			end_time TIME NOT NULL,
+			created_at DATETIME,
+			FOREIGN KEY (instructor_id) REFERENCES "Instructor"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_instructor_availability_instructor_id ON "InstructorAvailability" (instructor_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure InstructorAvailability table exists: %v", err)
+	}
+}
+
+// ensureRoomTable creates the Room table and adds room_id to Course and ClassSession.
+func ensureRoomTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "Room" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			capacity INTEGER NOT NULL,
+			equipment TEXT,
+			created_at DATETIME
+		);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure Room table exists: %v", err)
+		return
+	}
+
+	for _, table := range []string{"Course", "ClassSession"} {
+		if !DB.Migrator().HasTable(table) {
+			continue
+		}
+		if !DB.Migrator().HasColumn(table, "room_id") {
+			if err := DB.Exec(fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN room_id INTEGER REFERENCES "Room"(id) ON DELETE SET NULL;`, table)).Error; err != nil {
+				log.Printf("Failed to add room_id to %s: %v", table, err)
+				continue
+			}
+		}
+		index := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_room_id ON "%s" (room_id)`, strings.ToLower(table), table)
+		if err := DB.Exec(index).Error; err != nil {
+			log.Printf("ensureRoomTable: %v", err)
+		}
+	}
+}
+
+// ensureCourseSlotTable creates the CourseSlot table and moves each course's single weekday/start/end slot into it.
+func ensureCourseSlotTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "CourseSlot" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			course_id INTEGER NOT NULL,
+			weekday VARCHAR(16) NOT NULL,
+			start_time TIME NOT NULL,
+			end_time TIME NOT NULL,
+			FOREIGN KEY (course_id) REFERENCES "Course"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_course_slot_course_id ON "CourseSlot" (course_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure CourseSlot table exists: %v", err)
+		return
+	}
+	if !DB.Migrator().HasTable("Course") {
+		return
+	}
+
+	// Courses without a valid weekday have no schedule and get no slot.
+	backfill := `
+		INSERT INTO "CourseSlot" (course_id, weekday, start_time, end_time)
+		SELECT c.id, TRIM(c.weekday), c.start_time, c.end_time
+		FROM "Course" c
+		WHERE LOWER(SUBSTR(TRIM(c.weekday), 1, 3)) IN ('mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun')
+			AND c.start_time IS NOT NULL AND c.end_time IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM "CourseSlot" s WHERE s.course_id = c.id)
+	`
+	if err := DB.Exec(backfill).Error; err != nil {
+		log.Printf("ensureCourseSlotTable: failed to migrate course schedules: %v", err)
+	}
+}
+
+// ensureCourseTermColumns adds the starts_on/ends_on term bounds to Course.
+func ensureCourseTermColumns() {
+	if DB == nil || !DB.Migrator().HasTable("Course") {
+		return
+	}
+
+	for _, column := range []string{"starts_on", "ends_on"} {
+		if DB.Migrator().HasColumn("Course", column) {
+			continue
+		}
+		if err := DB.Exec(fmt.Sprintf(`ALTER TABLE "Course" ADD COLUMN %s DATE;`, column)).Error; err != nil {
+			log.Printf("Failed to add %s column to Course: %v", column, err)
+		}
+	}
+}
+
+// ensureBlackoutDateTable creates the BlackoutDate table if missing.
+func ensureBlackoutDateTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "BlackoutDate" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date DATE NOT NULL UNIQUE,
+			reason TEXT,
+			created_at DATETIME
+		);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure BlackoutDate table exists: %v", err)
+	}
+}
+
+// ensureBookingPolicyTable creates the BookingPolicy table and adds booking_policy_id to Course.
+func ensureBookingPolicyTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "BookingPolicy" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			category TEXT UNIQUE,
+			opens_hours_before INTEGER NOT NULL DEFAULT 0,
+			closes_minutes_before INTEGER NOT NULL DEFAULT 0,
+			cancel_cutoff_minutes INTEGER NOT NULL DEFAULT 0,
+			max_active_bookings INTEGER NOT NULL DEFAULT 0,
+			max_bookings_per_day INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME
+		);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure BookingPolicy table exists: %v", err)
+		return
+	}
+
+	if !DB.Migrator().HasTable("Course") {
+		return
+	}
+	if !DB.Migrator().HasColumn("Course", "booking_policy_id") {
+		if err := DB.Exec(`ALTER TABLE "Course" ADD COLUMN booking_policy_id INTEGER REFERENCES "BookingPolicy"(id) ON DELETE SET NULL;`).Error; err != nil {
+			log.Printf("Failed to add booking_policy_id to Course: %v", err)
+			return
+		}
+	}
+	if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_course_booking_policy_id ON "Course" (booking_policy_id)`).Error; err != nil {
+		log.Printf("ensureBookingPolicyTable: %v", err)
+	}
+}
+
+// ensureMemberStrikeTable creates the MemberStrike table.
+func ensureMemberStrikeTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "MemberStrike" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			course_id INTEGER NOT NULL,
+			session_id INTEGER,
+			enrollment_id INTEGER,
+			reason VARCHAR(32) NOT NULL,
+			occurred_at DATETIME NOT NULL,
+			cleared_at DATETIME,
+			cleared_by INTEGER,
+			created_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_member_strike_user_id ON "MemberStrike" (user_id);
+		CREATE INDEX IF NOT EXISTS idx_member_strike_enrollment_id ON "MemberStrike" (enrollment_id);
+		CREATE INDEX IF NOT EXISTS idx_member_strike_occurred_at ON "MemberStrike" (occurred_at);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure MemberStrike table exists: %v", err)
+	}
+}
+
+// ensureMembershipTables creates the membership plan, subscription and credit ledger tables.
+// The ledger is append-only: triggers reject updates and deletes so every balance change stays auditable.
+func ensureMembershipTables() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "MembershipPlan" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			kind VARCHAR(32) NOT NULL,
+			credits INTEGER NOT NULL DEFAULT 0,
+			duration_days INTEGER NOT NULL DEFAULT 0,
+			price_cents INTEGER NOT NULL DEFAULT 0,
+			active BOOLEAN NOT NULL DEFAULT 1,
+			created_at DATETIME
+		);
+		CREATE TABLE IF NOT EXISTS "MemberSubscription" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			plan_id INTEGER NOT NULL,
+			starts_on DATE NOT NULL,
+			ends_on DATE,
+			status VARCHAR(16) NOT NULL DEFAULT 'active',
+			granted_by INTEGER,
+			created_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (plan_id) REFERENCES "MembershipPlan"(id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_member_subscription_user_id ON "MemberSubscription" (user_id);
+		CREATE TABLE IF NOT EXISTS "CreditLedgerEntry" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			delta INTEGER NOT NULL,
+			reason VARCHAR(32) NOT NULL,
+			note TEXT,
+			balance_after INTEGER NOT NULL,
+			subscription_id INTEGER,
+			enrollment_id INTEGER,
+			session_id INTEGER,
+			actor_id INTEGER NOT NULL,
+			created_at DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_credit_ledger_entry_user_id ON "CreditLedgerEntry" (user_id);
+		CREATE INDEX IF NOT EXISTS idx_credit_ledger_entry_enrollment_id ON "CreditLedgerEntry" (enrollment_id);
+		CREATE TRIGGER IF NOT EXISTS credit_ledger_entry_no_update BEFORE UPDATE ON "CreditLedgerEntry"
+		BEGIN
+			SELECT RAISE(ABORT, 'credit ledger is append-only');
+		END;
+		CREATE TRIGGER IF NOT EXISTS credit_ledger_entry_no_delete BEFORE DELETE ON "CreditLedgerEntry"
+		BEGIN
+			SELECT RAISE(ABORT, 'credit ledger is append-only');
+		END;
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure membership tables exist: %v", err)
+	}
+}
+
+func ensurePaymentTables() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "PaymentOrder" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			plan_id INTEGER NOT NULL,
+			amount_cents INTEGER NOT NULL,
+			currency VARCHAR(8) NOT NULL,
+			provider VARCHAR(32) NOT NULL,
+			provider_intent_id TEXT NOT NULL UNIQUE,
+			checkout_url TEXT,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			subscription_id INTEGER,
+			paid_at DATETIME,
+			refunded_at DATETIME,
+			created_at DATETIME,
+			updated_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+			FOREIGN KEY (plan_id) REFERENCES "MembershipPlan"(id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_payment_order_user_id ON "PaymentOrder" (user_id);
+		CREATE TABLE IF NOT EXISTS "PaymentEvent" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider VARCHAR(32) NOT NULL,
+			event_id TEXT NOT NULL,
+			type VARCHAR(64) NOT NULL,
+			order_id INTEGER,
+			payload TEXT,
+			received_at DATETIME
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_event_provider_event ON "PaymentEvent" (provider, event_id);
+		CREATE INDEX IF NOT EXISTS idx_payment_event_order_id ON "PaymentEvent" (order_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure payment tables exist: %v", err)
+	}
+}
+
+func ensureRefreshTokenTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "RefreshToken" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			family_id VARCHAR(64) NOT NULL,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			expires_at DATETIME NOT NULL,
+			revoked_at DATETIME,
+			revoked_reason VARCHAR(32),
+			created_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_refresh_token_user_id ON "RefreshToken" (user_id);
+		CREATE INDEX IF NOT EXISTS idx_refresh_token_family_id ON "RefreshToken" (family_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure refresh token table exists: %v", err)
+	}
+}
+
+func ensureAccountTokenTable() {
+	if DB == nil {
+		return
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS "AccountToken" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			purpose VARCHAR(32) NOT NULL,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			expires_at DATETIME NOT NULL,
+			used_at DATETIME,
+			created_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES "User"(id) ON UPDATE CASCADE ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_account_token_user_id ON "AccountToken" (user_id);
+	`
+	if err := DB.Exec(query).Error; err != nil {
+		log.Printf("Failed to ensure account token table exists: %v", err)
+	}
+}
+
+func ensureEnrollmentCheckInColumns() {
+	if DB == nil || !DB.Migrator().HasTable("Enrollment") {
+		return
+	}
+	if DB.Migrator().HasColumn("Enrollment", "checked_in_at") {
+		if !DB.Migrator().HasColumn("Enrollment", "checked_in_by") {
+			if err := DB.Exec(`ALTER TABLE "Enrollment" ADD COLUMN checked_in_by INTEGER;`).Error; err != nil {
+				log.Printf("Failed to add checked_in_by column to Enrollment: %v", err)
+			}
+		}
+		return
+	}
+
+	// Bookings of sessions that ended before check-in existed could never have checked in. Settle them
+	// as attended, as they were before, so the no-show policy only applies to sessions from now on.
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE "Enrollment" ADD COLUMN checked_in_at DATETIME;`).Error; err != nil {
+			return err
+		}
+		if !tx.Migrator().HasColumn("Enrollment", "checked_in_by") {
+			if err := tx.Exec(`ALTER TABLE "Enrollment" ADD COLUMN checked_in_by INTEGER;`).Error; err != nil {
+				return err
+			}
+		}
+		if !tx.Migrator().HasTable("ClassSession") {
+			return nil
+		}
+		result := tx.Exec(`UPDATE "Enrollment" SET status = 'attended'
+			WHERE status = 'enrolled' AND session_id IN (SELECT id FROM ClassSession WHERE end_at < ?)`, time.Now().UTC())
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("ensureEnrollmentCheckInColumns: settled %d ended enrollment(s) as attended", result.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to add check-in columns to Enrollment: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"my-course-backend/model"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupMigrationTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:migration_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	testDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	previous := DB
+	DB = testDB
+	t.Cleanup(func() {
+		DB = previous
+		if sqlDB, err := testDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+}
+
+func TestMigrateClassSessionTimesToUTC_ReinterpretsBaselineRowsOnce(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	model.SetFacilityLocation(loc)
+	t.Cleanup(func() { model.SetFacilityLocation(nil) })
+
+	ensureClassSessionTable()
+	// Rows written before the fix hold the 14:00 wall-clock time labelled as UTC.
+	if err := DB.Exec(`INSERT INTO ClassSession (course_id, session_date, start_at, end_at, status, capacity)
+		VALUES (1, '2026-04-08', '2026-04-08 14:00:00+00:00', '2026-04-08 15:30:00+00:00', 'scheduled', 10)`).Error; err != nil {
+		t.Fatalf("failed to seed baseline session: %v", err)
+	}
+
+	migrateClassSessionTimesToUTC()
+	migrateClassSessionTimesToUTC()
+
+	var session model.ClassSession
+	if err := DB.First(&session).Error; err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+
+	wantStart := time.Date(2026, 4, 8, 14, 0, 0, 0, loc)
+	wantEnd := time.Date(2026, 4, 8, 15, 30, 0, 0, loc)
+	if !session.StartAt.Equal(wantStart) || !session.EndAt.Equal(wantEnd) {
+		t.Fatalf("expected session %s-%s, got %s-%s", wantStart, wantEnd, session.StartAt, session.EndAt)
+	}
+	if got := session.StartAt.Format("15:04"); got != "14:00" {
+		t.Fatalf("expected the session to start at 14:00 facility time, got %s", got)
+	}
+}
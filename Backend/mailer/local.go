@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LogMailer writes every message to the server log instead of sending it. It is the default in dev.
+type LogMailer struct {
+	From string
+}
+
+// Send logs msg.
+func (m *LogMailer) Send(msg Message) error {
+	body, err := Format(m.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("mailer: message to %s\n%s", msg.To, body)
+	return nil
+}
+
+// FileMailer writes every message to its own .eml file in Dir, so dev and tests can open them.
+type FileMailer struct {
+	Dir  string
+	From string
+
+	mu   sync.Mutex
+	sent int
+}
+
+// NewFileMailer returns a mailer writing into dir, creating it if needed.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileMailer{Dir: dir, From: from}, nil
+}
+
+// Send writes msg to a new file named after the time it was sent.
+func (m *FileMailer) Send(msg Message) error {
+	now := time.Now()
+	body, err := Format(m.From, msg, now)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.sent++
+	name := fmt.Sprintf("%s-%04d.eml", now.UTC().Format("20060102T150405"), m.sent)
+	m.mu.Unlock()
+	return os.WriteFile(filepath.Join(m.Dir, name), body, 0o600)
+}
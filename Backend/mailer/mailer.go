@@ -0,0 +1,64 @@
+// Package mailer sends FitFlow's transactional email, such as password reset and email verification
+// links. SMTPMailer delivers through a mail server; FileMailer and LogMailer capture messages locally
+// for dev and tests.
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// DefaultFrom is the sender used when none is configured.
+const DefaultFrom = "FitFlow <no-reply@fitflow.local>"
+
+// ErrInvalidMessage is returned for messages without a valid recipient or with line breaks in a header.
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message is one plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends messages.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Format renders msg as an RFC 5322 message from the given sender.
+func Format(from string, msg Message, sentAt time.Time) ([]byte, error) {
+	if err := validate(msg); err != nil {
+		return nil, err
+	}
+	if from == "" {
+		from = DefaultFrom
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", sentAt.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+// validate rejects messages that could inject extra headers or recipients.
+func validate(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return ErrInvalidMessage
+	}
+	return nil
+}
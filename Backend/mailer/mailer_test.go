@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormat_RejectsHeaderInjection(t *testing.T) {
+	sentAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	body, err := Format("", Message{To: "member@example.com", Subject: "Reset your password", Body: "line one\nline two"}, sentAt)
+	if err != nil {
+		t.Fatalf("failed to format: %v", err)
+	}
+	text := string(body)
+	for _, want := range []string{"From: " + DefaultFrom + "\r\n", "To: member@example.com\r\n", "Subject: Reset your password\r\n", "\r\n\r\nline one\r\nline two"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in\n%s", want, text)
+		}
+	}
+
+	bad := []Message{
+		{To: "member@example.com\r\nBcc: everyone@example.com", Subject: "hi"},
+		{To: "member@example.com", Subject: "hi\r\nBcc: everyone@example.com"},
+		{To: "not an address", Subject: "hi"},
+	}
+	for _, msg := range bad {
+		if _, err := Format("", msg, sentAt); !errors.Is(err, ErrInvalidMessage) {
+			t.Fatalf("expected %+v to be rejected, got %v", msg, err)
+		}
+	}
+}
+
+func TestFileMailer_WritesOneFilePerMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	m, err := NewFileMailer(dir, "FitFlow <hello@fitflow.test>")
+	if err != nil {
+		t.Fatalf("failed to create mailer: %v", err)
+	}
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := m.Send(Message{To: to, Subject: "Verify your email", Body: "code"}); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 messages, got %v", files)
+	}
+	first, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(first), "From: FitFlow <hello@fitflow.test>") || !strings.Contains(string(first), "To: a@example.com") {
+		t.Fatalf("unexpected message:\n%s", first)
+	}
+}
+
+func TestNewSMTPMailer_ValidatesConfiguration(t *testing.T) {
+	if _, err := NewSMTPMailer("smtp.example.com", "", "", ""); err == nil {
+		t.Fatalf("expected an address without a port to be rejected")
+	}
+	if _, err := NewSMTPMailer("smtp.example.com:587", "", "", "nobody"); err == nil {
+		t.Fatalf("expected an invalid sender to be rejected")
+	}
+	m, err := NewSMTPMailer("smtp.example.com:587", "user", "pass", "")
+	if err != nil || m.From != DefaultFrom {
+		t.Fatalf("expected the default sender, got %+v (err %v)", m, err)
+	}
+}
@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer delivers messages through an SMTP server. The connection is upgraded with STARTTLS when
+// the server offers it; credentials are only sent over TLS or to localhost, as net/smtp enforces.
+type SMTPMailer struct {
+	// Addr is the server's host:port.
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailer returns a mailer for the server at addr. Without a username no authentication is used.
+func NewSMTPMailer(addr, username, password, from string) (*SMTPMailer, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+	if from == "" {
+		from = DefaultFrom
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	return &SMTPMailer{Addr: addr, Username: username, Password: password, From: from}, nil
+}
+
+// Send delivers msg.
+func (m *SMTPMailer) Send(msg Message) error {
+	body, err := Format(m.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	sender, _ := mail.ParseAddress(m.From)
+	recipient, _ := mail.ParseAddress(msg.To)
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	return smtp.SendMail(m.Addr, auth, sender.Address, []string{recipient.Address}, body)
+}
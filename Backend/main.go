@@ -2,13 +2,24 @@ package main
 
 import (
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"my-course-backend/db"
+	"my-course-backend/mailer"
 	"my-course-backend/model"
+	"my-course-backend/payments"
 	"my-course-backend/routes"
+	"my-course-backend/service"
+	"my-course-backend/tokens"
 )
 
 func main() {
+	// 0. Facility time zone, used by migrations that build sessions
+	loadFacilityTimeZone()
+
 	// 1. Initialize Database
 	db.InitDB()
 
@@ -37,13 +48,319 @@ func main() {
 	// 3. Seed Initial Data
 	seedRoles()
 
-	// 4. Initialize Router
+	// 4. No-show and late-cancel strike settings
+	configureStrikes()
+
+	// 4b. Membership enforcement for bookings
+	configureMemberships()
+
+	// 4c. Payment provider for membership checkout
+	configurePayments()
+
+	// 4d. Check-in codes, kiosks and the attendance policy
+	configureCheckIn()
+
+	// 4e. Access token signing keys and claims
+	configureTokens()
+
+	// 4f. Mailer and account email settings
+	configureAccounts()
+
+	// 4g. Start the background session scheduler once everything it applies is configured
+	startScheduler()
+
+	// 5. Initialize Router
 	r := routes.SetupRouter()
 
-	// 5. Start Server
+	// 6. Start Server
 	r.Run(":8080")
 }
 
+// loadFacilityTimeZone applies FITFLOW_TIMEZONE (an IANA name such as "America/New_York").
+// Without it the server's local time zone is used.
+func loadFacilityTimeZone() {
+	configured := strings.TrimSpace(os.Getenv("FITFLOW_TIMEZONE"))
+	if configured == "" {
+		return
+	}
+	if err := model.LoadFacilityLocation(configured); err != nil {
+		log.Printf("Invalid FITFLOW_TIMEZONE %q, using %s", configured, model.FacilityLocation())
+		return
+	}
+	log.Printf("Facility time zone: %s", configured)
+}
+
+// configureStrikes applies FITFLOW_STRIKE_LIMIT, FITFLOW_STRIKE_WINDOW_DAYS and
+// FITFLOW_LATE_CANCEL_WINDOW (a Go duration such as "12h") when set. A limit of 0 never suspends booking.
+func configureStrikes() {
+	settings := service.DefaultStrikeSettings
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_STRIKE_LIMIT")); configured != "" {
+		parsed, err := strconv.Atoi(configured)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid FITFLOW_STRIKE_LIMIT %q, using %d", configured, settings.Limit)
+		} else {
+			settings.Limit = parsed
+		}
+	}
+
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_STRIKE_WINDOW_DAYS")); configured != "" {
+		parsed, err := strconv.Atoi(configured)
+		if err != nil || parsed < 1 {
+			log.Printf("Invalid FITFLOW_STRIKE_WINDOW_DAYS %q, using %s", configured, settings.Window)
+		} else {
+			settings.Window = time.Duration(parsed) * 24 * time.Hour
+		}
+	}
+
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_LATE_CANCEL_WINDOW")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid FITFLOW_LATE_CANCEL_WINDOW %q, using %s", configured, settings.LateCancelWindow)
+		} else {
+			settings.LateCancelWindow = parsed
+		}
+	}
+
+	service.SetStrikeSettings(settings)
+}
+
+// configureMemberships applies FITFLOW_MEMBERSHIP_REQUIRED ("false" lets members book without
+// a plan or credits). Membership is required by default.
+func configureMemberships() {
+	configured := strings.TrimSpace(os.Getenv("FITFLOW_MEMBERSHIP_REQUIRED"))
+	if configured == "" {
+		return
+	}
+	required, err := strconv.ParseBool(configured)
+	if err != nil {
+		log.Printf("Invalid FITFLOW_MEMBERSHIP_REQUIRED %q, requiring membership", configured)
+		return
+	}
+	service.SetMembershipRequired(required)
+}
+
+// configurePayments applies FITFLOW_PAYMENT_PROVIDER, FITFLOW_PAYMENT_WEBHOOK_SECRET and
+// FITFLOW_PAYMENT_CURRENCY. Only the fake provider ships, and it grants plans without taking money, so it
+// also needs FITFLOW_DEV_FAKE_PAYMENTS=true. Payments stay disabled otherwise, and without a webhook secret.
+func configurePayments() {
+	providerName := strings.TrimSpace(os.Getenv("FITFLOW_PAYMENT_PROVIDER"))
+	switch providerName {
+	case "":
+		log.Printf("FITFLOW_PAYMENT_PROVIDER not set, payments disabled")
+		return
+	case payments.FakeProviderName:
+		if devFake, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("FITFLOW_DEV_FAKE_PAYMENTS"))); !devFake {
+			log.Printf("The %s payment provider needs FITFLOW_DEV_FAKE_PAYMENTS=true, payments disabled", providerName)
+			return
+		}
+	default:
+		log.Printf("Unknown FITFLOW_PAYMENT_PROVIDER %q, payments disabled", providerName)
+		return
+	}
+
+	secret := strings.TrimSpace(os.Getenv("FITFLOW_PAYMENT_WEBHOOK_SECRET"))
+	if secret == "" {
+		log.Printf("FITFLOW_PAYMENT_WEBHOOK_SECRET not set, payments disabled")
+		return
+	}
+	currency := strings.ToLower(strings.TrimSpace(os.Getenv("FITFLOW_PAYMENT_CURRENCY")))
+	service.SetPaymentProvider(payments.NewFakeProvider(secret), currency)
+}
+
+// configureCheckIn applies FITFLOW_ATTENDANCE_POLICY ("check_in" or "assume_attended"),
+// FITFLOW_CHECKIN_CODE_TTL and FITFLOW_CHECKIN_OPENS_BEFORE (Go durations), FITFLOW_CHECKIN_SECRET
+// and FITFLOW_KIOSK_KEY when set.
+func configureCheckIn() {
+	settings := service.DefaultCheckInSettings
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_ATTENDANCE_POLICY")); configured != "" {
+		if configured != model.AttendancePolicyCheckIn && configured != model.AttendancePolicyAssumeAttended {
+			log.Printf("Invalid FITFLOW_ATTENDANCE_POLICY %q, using %s", configured, settings.Policy)
+		} else {
+			settings.Policy = configured
+		}
+	}
+
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_CHECKIN_CODE_TTL")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid FITFLOW_CHECKIN_CODE_TTL %q, using %s", configured, settings.CodeTTL)
+		} else {
+			settings.CodeTTL = parsed
+		}
+	}
+
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_CHECKIN_OPENS_BEFORE")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid FITFLOW_CHECKIN_OPENS_BEFORE %q, using %s", configured, settings.OpensBefore)
+		} else {
+			settings.OpensBefore = parsed
+		}
+	}
+
+	service.SetCheckInSettings(settings)
+	service.SetCheckInSecret(strings.TrimSpace(os.Getenv("FITFLOW_CHECKIN_SECRET")))
+	service.SetKioskKey(strings.TrimSpace(os.Getenv("FITFLOW_KIOSK_KEY")))
+}
+
+// configureTokens applies FITFLOW_JWT_KEYS, a comma-separated list of kid:alg:path entries
+// (alg is HS256, RS256 or EdDSA; path holds the secret or PEM private key), signing with
+// FITFLOW_JWT_SIGNING_KID or the first key. FITFLOW_JWT_SECRET is a shorthand for a single HS256 key.
+// FITFLOW_JWT_ISSUER, FITFLOW_JWT_AUDIENCE, FITFLOW_JWT_TTL and FITFLOW_REFRESH_TOKEN_TTL (Go durations)
+// override the claims and lifetimes.
+// Without keys startup fails unless FITFLOW_DEV_JWT=true, which signs with a random key per process.
+func configureTokens() {
+	settings := service.DefaultTokenSettings
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_JWT_ISSUER")); configured != "" {
+		settings.Issuer = configured
+	}
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_JWT_AUDIENCE")); configured != "" {
+		settings.Audience = configured
+	}
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_JWT_TTL")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid FITFLOW_JWT_TTL %q, using %s", configured, settings.TTL)
+		} else {
+			settings.TTL = parsed
+		}
+	}
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_REFRESH_TOKEN_TTL")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid FITFLOW_REFRESH_TOKEN_TTL %q, using %s", configured, settings.RefreshTTL)
+		} else {
+			settings.RefreshTTL = parsed
+		}
+	}
+	service.SetTokenSettings(settings)
+
+	var keys []*tokens.Key
+	for _, entry := range strings.Split(os.Getenv("FITFLOW_JWT_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			log.Fatalf("Invalid FITFLOW_JWT_KEYS entry %q, expected kid:alg:path", entry)
+		}
+		material, err := os.ReadFile(parts[2])
+		if err != nil {
+			log.Fatalf("Cannot read JWT key %s: %v", parts[0], err)
+		}
+		key, err := tokens.ParseKey(parts[0], parts[1], material)
+		if err != nil {
+			log.Fatalf("Invalid JWT key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if secret := strings.TrimSpace(os.Getenv("FITFLOW_JWT_SECRET")); secret != "" {
+		key, err := tokens.NewHMACKey("default", []byte(secret))
+		if err != nil {
+			log.Fatalf("Invalid FITFLOW_JWT_SECRET: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		if devJWT, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("FITFLOW_DEV_JWT"))); !devJWT {
+			log.Fatalf("No FITFLOW_JWT_KEYS or FITFLOW_JWT_SECRET set; set FITFLOW_DEV_JWT=true to sign with a random development key")
+		}
+		service.SetTokenKeys(service.NewDevKeyring())
+		log.Printf("FITFLOW_DEV_JWT set, signing tokens with a random development key that does not survive a restart")
+		return
+	}
+
+	signingID := strings.TrimSpace(os.Getenv("FITFLOW_JWT_SIGNING_KID"))
+	if signingID == "" {
+		signingID = keys[0].ID
+	}
+	keyring, err := tokens.NewKeyring(signingID, keys...)
+	if err != nil {
+		log.Fatalf("Invalid JWT keys: %v", err)
+	}
+	service.SetTokenKeys(keyring)
+	log.Printf("Signing access tokens with key %s (%s)", signingID, strings.Join(keyring.Algorithms(), ", "))
+}
+
+// configureAccounts applies FITFLOW_MAILER ("log", "file" or "smtp"), FITFLOW_MAIL_FROM, FITFLOW_MAIL_DIR for
+// the file mailer, FITFLOW_SMTP_ADDR, FITFLOW_SMTP_USERNAME and FITFLOW_SMTP_PASSWORD for SMTP,
+// FITFLOW_APP_URL for links in emails and FITFLOW_REQUIRE_VERIFIED_EMAIL. Mail is logged by default.
+func configureAccounts() {
+	settings := service.DefaultAccountSettings
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_APP_URL")); configured != "" {
+		settings.AppURL = configured
+	}
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_REQUIRE_VERIFIED_EMAIL")); configured != "" {
+		required, err := strconv.ParseBool(configured)
+		if err != nil {
+			log.Printf("Invalid FITFLOW_REQUIRE_VERIFIED_EMAIL %q, not requiring verification", configured)
+		} else {
+			settings.RequireVerifiedEmail = required
+		}
+	}
+	service.SetAccountSettings(settings)
+
+	from := strings.TrimSpace(os.Getenv("FITFLOW_MAIL_FROM"))
+	switch kind := strings.TrimSpace(os.Getenv("FITFLOW_MAILER")); kind {
+	case "", "log":
+		service.SetMailer(&mailer.LogMailer{From: from})
+	case "file":
+		dir := strings.TrimSpace(os.Getenv("FITFLOW_MAIL_DIR"))
+		if dir == "" {
+			dir = "mail"
+		}
+		fileMailer, err := mailer.NewFileMailer(dir, from)
+		if err != nil {
+			log.Fatalf("Cannot use FITFLOW_MAIL_DIR %q: %v", dir, err)
+		}
+		service.SetMailer(fileMailer)
+		log.Printf("Writing outgoing mail to %s", dir)
+	case "smtp":
+		smtpMailer, err := mailer.NewSMTPMailer(
+			strings.TrimSpace(os.Getenv("FITFLOW_SMTP_ADDR")),
+			strings.TrimSpace(os.Getenv("FITFLOW_SMTP_USERNAME")),
+			os.Getenv("FITFLOW_SMTP_PASSWORD"),
+			from,
+		)
+		if err != nil {
+			log.Fatalf("Invalid SMTP configuration: %v", err)
+		}
+		service.SetMailer(smtpMailer)
+	default:
+		log.Printf("Unknown FITFLOW_MAILER %q, logging mail instead", kind)
+		service.SetMailer(&mailer.LogMailer{From: from})
+	}
+}
+
+// startScheduler starts the background scheduler using FITFLOW_SCHEDULER_INTERVAL
+// (a Go duration such as "15m") and FITFLOW_SESSION_HORIZON_WEEKS when set.
+func startScheduler() {
+	interval := service.DefaultSchedulerInterval
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_SCHEDULER_INTERVAL")); configured != "" {
+		parsed, err := time.ParseDuration(configured)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid FITFLOW_SCHEDULER_INTERVAL %q, using %s", configured, interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	horizonWeeks := service.DefaultSessionHorizonWeeks
+	if configured := strings.TrimSpace(os.Getenv("FITFLOW_SESSION_HORIZON_WEEKS")); configured != "" {
+		parsed, err := strconv.Atoi(configured)
+		if err != nil || parsed < 1 {
+			log.Printf("Invalid FITFLOW_SESSION_HORIZON_WEEKS %q, using %d", configured, horizonWeeks)
+		} else {
+			horizonWeeks = parsed
+		}
+	}
+
+	if err := service.StartScheduler(interval, horizonWeeks); err != nil {
+		log.Printf("Failed to start scheduler: %v", err)
+	}
+}
+
 func seedRoles() {
 	type fixedRole struct {
 		ID   uint
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Account token purposes.
+const (
+	AccountTokenPasswordReset     = "password_reset"
+	AccountTokenEmailVerification = "email_verification"
+)
+
+// AccountToken is a single-use token mailed to a user to reset their password or verify their email.
+// Only the SHA-256 of the token is stored; issuing a new token of a purpose invalidates older ones.
+type AccountToken struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID    uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	Purpose   string     `gorm:"column:purpose;not null" json:"purpose"`
+	TokenHash string     `gorm:"column:token_hash;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (AccountToken) TableName() string { return "AccountToken" }
+
+// ForgotPasswordInput asks for a password reset link.
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordInput sets a new password with a mailed reset token.
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// VerifyEmailInput confirms an email address with a mailed verification token.
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangePasswordInput changes the signed-in user's password.
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Outcomes of one row of a bulk attendance update.
+const (
+	AttendanceResultUpdated   = "updated"
+	AttendanceResultUnchanged = "unchanged"
+	AttendanceResultRejected  = "rejected"
+)
+
+// AttendanceMark sets one member's status in a session.
+type AttendanceMark struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Status string `json:"status" binding:"required"`
+}
+
+// SessionAttendanceInput marks attendance for several members of one session at once.
+type SessionAttendanceInput struct {
+	Marks []AttendanceMark `json:"marks" binding:"required,min=1,dive"`
+}
+
+// AttendanceMarkResult reports what happened to one mark of a bulk update.
+type AttendanceMarkResult struct {
+	UserID       uint   `json:"user_id"`
+	EnrollmentID uint   `json:"enrollment_id,omitempty"`
+	Status       string `json:"status"`
+	Result       string `json:"result"`
+	Error        string `json:"error,omitempty"`
+}
+
+// SessionHeadcount counts a session's enrollments for instructor session lists.
+type SessionHeadcount struct {
+	Seated     int64 `json:"seated"`
+	Waitlisted int64 `json:"waitlisted"`
+	CheckedIn  int64 `json:"checked_in"`
+}
+
+// InstructorSession is a session of a course with its headcount.
+type InstructorSession struct {
+	ClassSession
+	SessionHeadcount
+}
+
+// AttendanceHistory summarizes a member's past sessions of one course.
+type AttendanceHistory struct {
+	Attended       int64      `json:"attended"`
+	Missed         int64      `json:"missed"`
+	LastAttendedAt *time.Time `json:"last_attended_at"`
+}
+
+// RosterEntry is one member on a session roster.
+type RosterEntry struct {
+	EnrollmentID     uint              `json:"enrollment_id"`
+	UserID           uint              `json:"user_id"`
+	Name             string            `json:"name"`
+	Email            string            `json:"email"`
+	PhoneNumber      string            `json:"phone_number"`
+	Status           string            `json:"status"`
+	WaitlistPosition *int              `json:"waitlist_position"`
+	CheckedIn        bool              `json:"checked_in"`
+	CheckedInAt      *time.Time        `json:"checked_in_at"`
+	Notes            string            `json:"notes"`
+	History          AttendanceHistory `json:"history"`
+}
+
+// SessionRoster lists who is booked into a session: seated members first, then the waitlist in queue order.
+type SessionRoster struct {
+	Session ClassSession  `json:"session"`
+	Entries []RosterEntry `json:"entries"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// InstructorAvailability is a weekly window in which an instructor can teach.
+// An instructor without any windows is treated as available at all times.
+type InstructorAvailability struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	InstructorID uint      `gorm:"column:instructor_id;not null;index" json:"instructor_id"`
+	Weekday      string    `gorm:"column:weekday;not null" json:"weekday"`
+	StartTime    TimeOnly  `gorm:"column:start_time;type:time;not null" json:"start_time"`
+	EndTime      TimeOnly  `gorm:"column:end_time;type:time;not null" json:"end_time"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (InstructorAvailability) TableName() string { return "InstructorAvailability" }
+
+// AvailabilityWindowInput is one weekly availability window.
+type AvailabilityWindowInput struct {
+	Weekday   string `json:"weekday" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"` // "08:00" or "08:00:00"
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+// InstructorAvailabilityInput replaces all availability windows of an instructor.
+type InstructorAvailabilityInput struct {
+	Windows []AvailabilityWindowInput `json:"windows" binding:"dive"`
+}
+
+// Reasons an instructor assignment conflicts with their schedule.
+const (
+	ScheduleConflictOverlap     = "overlap"
+	ScheduleConflictUnavailable = "unavailable"
+)
+
+// InstructorScheduleConflict describes why a course slot does not fit an instructor's schedule.
+// CourseID and CourseName identify the overlapping course for "overlap" conflicts.
+type InstructorScheduleConflict struct {
+	InstructorID   uint     `json:"instructor_id"`
+	InstructorName string   `json:"instructor_name"`
+	Reason         string   `json:"reason"`
+	CourseID       uint     `json:"course_id,omitempty"`
+	CourseName     string   `json:"course_name,omitempty"`
+	Weekday        string   `json:"weekday"`
+	StartTime      TimeOnly `json:"start_time"`
+	EndTime        TimeOnly `json:"end_time"`
+}
+
+// InstructorSchedule is the manager view of an instructor's weekly commitments.
+type InstructorSchedule struct {
+	Instructor   Instructor                   `json:"instructor"`
+	Availability []InstructorAvailability     `json:"availability"`
+	Courses      []Course                     `json:"courses"`
+	Sessions     []ClassSession               `json:"sessions"`
+	Conflicts    []InstructorScheduleConflict `json:"conflicts"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// BlackoutDate is a day the gym is closed, such as a public holiday or facility closure.
+// No sessions are generated on it and scheduled sessions on it are canceled.
+type BlackoutDate struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Date      string    `gorm:"column:date;not null;uniqueIndex" json:"date"` // YYYY-MM-DD
+	Reason    string    `gorm:"column:reason" json:"reason"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (BlackoutDate) TableName() string { return "BlackoutDate" }
+
+// BlackoutDateInput creates or updates a blackout date.
+type BlackoutDateInput struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason string `json:"reason"`
+}
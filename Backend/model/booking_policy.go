@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// BookingPolicy controls when members may book and cancel sessions and how many bookings they may hold.
+// It applies to the courses linked to it and, through Category, to every other course of that category.
+// Zero values switch a rule off.
+type BookingPolicy struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Name string `gorm:"column:name;not null;uniqueIndex" json:"name"`
+
+	// Category makes the policy the default for courses of that category without a policy of their own.
+	Category *string `gorm:"column:category;uniqueIndex" json:"category"`
+
+	// OpensHoursBefore is how many hours before a session starts booking opens; 0 keeps booking always open.
+	OpensHoursBefore int `gorm:"column:opens_hours_before;not null;default:0" json:"opens_hours_before"`
+	// ClosesMinutesBefore is how many minutes before a session starts booking closes.
+	ClosesMinutesBefore int `gorm:"column:closes_minutes_before;not null;default:0" json:"closes_minutes_before"`
+	// CancelCutoffMinutes is how many minutes before a session starts members can no longer drop it.
+	CancelCutoffMinutes int `gorm:"column:cancel_cutoff_minutes;not null;default:0" json:"cancel_cutoff_minutes"`
+
+	// MaxActiveBookings caps a member's upcoming seated bookings across all courses.
+	MaxActiveBookings int `gorm:"column:max_active_bookings;not null;default:0" json:"max_active_bookings"`
+	// MaxBookingsPerDay caps a member's seated bookings on one facility day.
+	MaxBookingsPerDay int `gorm:"column:max_bookings_per_day;not null;default:0" json:"max_bookings_per_day"`
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (BookingPolicy) TableName() string { return "BookingPolicy" }
+
+// BookingPolicyInput creates or updates a booking policy. An empty Category leaves the policy unattached to categories.
+type BookingPolicyInput struct {
+	Name                string `json:"name" binding:"required"`
+	Category            string `json:"category"`
+	OpensHoursBefore    int    `json:"opens_hours_before" binding:"min=0"`
+	ClosesMinutesBefore int    `json:"closes_minutes_before" binding:"min=0"`
+	CancelCutoffMinutes int    `json:"cancel_cutoff_minutes" binding:"min=0"`
+	MaxActiveBookings   int    `json:"max_active_bookings" binding:"min=0"`
+	MaxBookingsPerDay   int    `json:"max_bookings_per_day" binding:"min=0"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+const (
+	BookingSeriesStatusActive   = "active"
+	BookingSeriesStatusCanceled = "canceled"
+)
+
+// Per-session outcomes of a series booking.
+const (
+	SeriesItemBooked        = "booked"
+	SeriesItemAlreadyBooked = "already_booked"
+	SeriesItemFull          = "full"
+	SeriesItemOverlap       = "overlap"
+	SeriesItemWindowNotOpen = "window_not_open"
+	SeriesItemLimitReached  = "limit_reached"
+	SeriesItemDropped       = "dropped"
+	SeriesItemCanceled      = "canceled"
+)
+
+// BookingSeries books every session of a course within a date range for one member.
+type BookingSeries struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID    uint      `gorm:"column:user_id;not null;index" json:"user_id"`
+	CourseID  uint      `gorm:"column:course_id;not null;index" json:"course_id"`
+	StartDate string    `gorm:"column:start_date;not null" json:"start_date"` // YYYY-MM-DD
+	EndDate   string    `gorm:"column:end_date;not null" json:"end_date"`     // YYYY-MM-DD
+	Status    string    `gorm:"column:status;not null;default:'active'" json:"status"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	Course Course              `gorm:"foreignKey:CourseID" json:"course"`
+	Items  []BookingSeriesItem `gorm:"foreignKey:SeriesID" json:"items"`
+}
+
+// BookingSeriesItem records the booking outcome of one session in a series.
+type BookingSeriesItem struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	SeriesID  uint      `gorm:"column:series_id;not null;uniqueIndex:idx_series_item_session" json:"series_id"`
+	SessionID uint      `gorm:"column:session_id;not null;uniqueIndex:idx_series_item_session" json:"session_id"`
+	Status    string    `gorm:"column:status;not null" json:"status"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	Session *ClassSession `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+}
+
+func (BookingSeries) TableName() string {
+	return "BookingSeries"
+}
+
+func (BookingSeriesItem) TableName() string {
+	return "BookingSeriesItem"
+}
+
+// Retryable reports whether a later sync may still book this session.
+func (i BookingSeriesItem) Retryable() bool {
+	return i.Status == SeriesItemWindowNotOpen || i.Status == SeriesItemFull || i.Status == SeriesItemLimitReached
+}
+
+// BookingSeriesRequest is the payload for booking a course for a date range.
+type BookingSeriesRequest struct {
+	CourseID  uint   `json:"course_id" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD
+}
+
+// BookingSeriesResult reports what happened to one session during a series sync.
+type BookingSeriesResult struct {
+	SessionID   uint   `json:"session_id"`
+	SessionDate string `json:"session_date"`
+	Status      string `json:"status"`
+}
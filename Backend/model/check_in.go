@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Attendance policies decide what happens to seated enrollments nobody marked once their session ends.
+const (
+	// AttendancePolicyCheckIn marks members who never checked in as missed.
+	AttendancePolicyCheckIn = "check_in"
+	// AttendancePolicyAssumeAttended marks every seated member attended, as before check-in existed.
+	AttendancePolicyAssumeAttended = "assume_attended"
+)
+
+// CheckInCode is the short-lived signed code a member shows as a QR code to check in.
+type CheckInCode struct {
+	Token        string    `json:"token"`
+	EnrollmentID uint      `json:"enrollment_id"`
+	SessionID    uint      `json:"session_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// CheckInInput is a scanned check-in code.
+type CheckInInput struct {
+	Token string `json:"token" binding:"required"`
+}
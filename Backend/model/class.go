@@ -1,11 +1,19 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 const (
 	EnrollmentStatusEnrolled = "enrolled"
 	EnrollmentStatusAttended = "attended"
 	EnrollmentStatusMissed   = "missed"
+	// EnrollmentStatusWaitlisted marks a queued enrollment for a full session.
+	EnrollmentStatusWaitlisted = "waitlisted"
+	// EnrollmentStatusCanceled marks an enrollment whose session was canceled.
+	EnrollmentStatusCanceled = "canceled"
 )
 
 // Course represents the Course table in SQLite.
@@ -26,11 +34,59 @@ type Course struct {
 	Category string `gorm:"column:category" json:"category"`
 	Weekday  string `gorm:"column:weekday" json:"weekday"`
 
-	Instructor string `gorm:"column:instructor" json:"instructor"`
+	// Slots are the weekly meeting times of the course. Weekday, StartTime and EndTime mirror the first slot
+	// for clients that only know a single meeting time.
+	Slots []CourseSlot `gorm:"foreignKey:CourseID" json:"slots"`
+
+	// StartsOn and EndsOn bound the term in which sessions are generated (YYYY-MM-DD, inclusive); nil is open-ended.
+	StartsOn *string `gorm:"column:starts_on" json:"starts_on"`
+	EndsOn   *string `gorm:"column:ends_on" json:"ends_on"`
+
+	// InstructorID links the course to its Instructor profile; Instructor is that profile's display name.
+	InstructorID *uint  `gorm:"column:instructor_id;index" json:"instructor_id"`
+	Instructor   string `gorm:"column:instructor" json:"instructor"`
+
+	// RoomID is the studio the course is held in; sessions may override it.
+	RoomID *uint `gorm:"column:room_id;index" json:"room_id"`
+	Room   *Room `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+
+	// BookingPolicyID overrides the policy of the course's category; without either the default policy applies.
+	BookingPolicyID *uint `gorm:"column:booking_policy_id;index" json:"booking_policy_id"`
 
 	Spot int `gorm:"-" json:"spot"`
+
+	// ScheduleWarnings lists instructor conflicts accepted when the course was saved with allow_schedule_conflicts.
+	ScheduleWarnings []InstructorScheduleConflict `gorm:"-" json:"schedule_warnings,omitempty"`
+}
+
+// ClassFilter narrows, orders and pages the class catalog. Zero values do not filter.
+type ClassFilter struct {
+	RoomID       *uint
+	InstructorID *uint
+	Category     string
+	// Weekday, StartAfter and EndBefore match classes with at least one weekly slot inside the bounds.
+	Weekday    string
+	StartAfter *TimeOnly
+	EndBefore  *TimeOnly
+	// Search matches name, description or course code.
+	Search   string
+	OnlyOpen bool
+	// Sort is one of ClassSortFields, optionally prefixed with "-" for descending order.
+	Sort string
+	// Page is 1-based; a Limit of 0 returns every match.
+	Page  int
+	Limit int
 }
 
+// SessionSeats is the effective capacity of a session and the enrollments seated in it.
+type SessionSeats struct {
+	Capacity int
+	Seated   int64
+}
+
+// ClassSortFields are the accepted ClassFilter.Sort values.
+var ClassSortFields = []string{"start_time", "name", "spots", "capacity"}
+
 // ClassSession represents a single occurrence of a recurring course.
 type ClassSession struct {
 	ID          uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
@@ -41,10 +97,35 @@ type ClassSession struct {
 	Status      string    `gorm:"column:status;not null;default:'scheduled'" json:"status"` // scheduled, canceled, completed
 	Capacity    int       `gorm:"column:capacity" json:"capacity"`                          // override if set, else use Course.Capacity
 
+	// RescheduledFrom is the originally generated date of a moved session, so it is not generated again.
+	RescheduledFrom *string `gorm:"column:rescheduled_from" json:"rescheduled_from,omitempty"` // YYYY-MM-DD
+
+	// SubstituteInstructorID is the Instructor profile covering this session instead of the course instructors.
+	SubstituteInstructorID *uint `gorm:"column:substitute_instructor_id;index" json:"substitute_instructor_id"`
+
+	// RoomID overrides the course's room for this session when set.
+	RoomID *uint `gorm:"column:room_id;index" json:"room_id"`
+
 	Course Course `gorm:"foreignKey:CourseID" json:"course"`
 	Spot   int    `gorm:"-" json:"spot"`
 }
 
+// BeforeSave stores session instants in UTC so text comparisons in SQLite stay ordered
+// regardless of the offset they were built with.
+func (s *ClassSession) BeforeSave(tx *gorm.DB) error {
+	s.StartAt = s.StartAt.UTC()
+	s.EndAt = s.EndAt.UTC()
+	return nil
+}
+
+// AfterFind renders session instants in the facility time zone.
+func (s *ClassSession) AfterFind(tx *gorm.DB) error {
+	loc := FacilityLocation()
+	s.StartAt = s.StartAt.In(loc)
+	s.EndAt = s.EndAt.In(loc)
+	return nil
+}
+
 // Enrollment is the join table between User and Course/ClassSession.
 type Enrollment struct {
 	ID        uint  `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -58,6 +139,14 @@ type Enrollment struct {
 
 	Status     string    `gorm:"column:status;not null" json:"status"`
 	EnrollTime time.Time `gorm:"column:enroll_time;autoCreateTime" json:"enroll_time"`
+
+	// WaitlistPosition is the 1-based queue position while Status is "waitlisted".
+	WaitlistPosition *int `gorm:"column:waitlist_position" json:"waitlist_position"`
+
+	// CheckedInAt is when the member's check-in code was scanned; CheckedInBy is the staff member
+	// who scanned it, nil at a kiosk.
+	CheckedInAt *time.Time `gorm:"column:checked_in_at" json:"checked_in_at"`
+	CheckedInBy *uint      `gorm:"column:checked_in_by" json:"checked_in_by"`
 }
 
 func (Course) TableName() string {
@@ -75,3 +164,9 @@ func (Enrollment) TableName() string {
 type EnrollmentRequest struct {
 	CourseID uint `json:"course_id" binding:"required"`
 }
+
+// WaitlistRequest queues for a specific session when SessionID is set, otherwise for the next session of CourseID.
+type WaitlistRequest struct {
+	CourseID  uint `json:"course_id"`
+	SessionID uint `json:"session_id"`
+}
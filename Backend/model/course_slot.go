@@ -0,0 +1,20 @@
+package model
+
+// CourseSlot is one weekly meeting time of a course. A course meets once per slot every week
+// and may have at most one slot per weekday.
+type CourseSlot struct {
+	ID        uint     `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	CourseID  uint     `gorm:"column:course_id;not null;index" json:"course_id"`
+	Weekday   string   `gorm:"column:weekday;not null" json:"weekday"`
+	StartTime TimeOnly `gorm:"column:start_time;type:time;not null" json:"start_time"`
+	EndTime   TimeOnly `gorm:"column:end_time;type:time;not null" json:"end_time"`
+}
+
+func (CourseSlot) TableName() string { return "CourseSlot" }
+
+// CourseSlotInput is one weekly meeting time in a course create/update request.
+type CourseSlotInput struct {
+	Weekday   string `json:"weekday" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"` // "08:00" or "08:00:00"
+	EndTime   string `json:"end_time" binding:"required"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"sync/atomic"
+	"time"
+
+	// Embed the time zone database so FITFLOW_TIMEZONE works on hosts without zoneinfo.
+	_ "time/tzdata"
+)
+
+// facilityLocation is the time zone the gym operates in. Class times of day, session dates,
+// enrollment windows and analytics days are all interpreted in it.
+var facilityLocation atomic.Pointer[time.Location]
+
+// FacilityLocation returns the facility time zone, defaulting to the server's local zone.
+func FacilityLocation() *time.Location {
+	if loc := facilityLocation.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// SetFacilityLocation changes the facility time zone. A nil location restores the default.
+func SetFacilityLocation(loc *time.Location) {
+	facilityLocation.Store(loc)
+}
+
+// LoadFacilityLocation sets the facility time zone from an IANA name such as "America/New_York".
+func LoadFacilityLocation(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	SetFacilityLocation(loc)
+	return nil
+}
+
+// FacilityNow returns the current time in the facility time zone.
+func FacilityNow() time.Time {
+	return time.Now().In(FacilityLocation())
+}
+
+// FacilityDate returns the facility calendar date (YYYY-MM-DD) of an instant.
+func FacilityDate(t time.Time) string {
+	return t.In(FacilityLocation()).Format("2006-01-02")
+}
+
+// FacilityToday returns today's facility calendar date (YYYY-MM-DD).
+func FacilityToday() string {
+	return FacilityDate(time.Now())
+}
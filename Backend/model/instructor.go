@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // Instructor represents an instructor profile, one-to-one with a User (role_id=4).
 type Instructor struct {
 	ID     uint   `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
@@ -7,7 +9,65 @@ type Instructor struct {
 	Name   string `gorm:"column:name" json:"name"`
 	Bio    string `gorm:"column:bio" json:"bio"`
 
+	PhotoURL       string   `gorm:"column:photo_url" json:"photo_url"`
+	Specialties    []string `gorm:"column:specialties;serializer:json" json:"specialties"`
+	Certifications []string `gorm:"column:certifications;serializer:json" json:"certifications"`
+
 	User User `gorm:"foreignKey:UserID" json:"user"`
 }
 
 func (Instructor) TableName() string { return "Instructor" }
+
+// InstructorProfileInput updates an instructor's own profile. Omitted fields are left unchanged.
+type InstructorProfileInput struct {
+	Bio            *string   `json:"bio"`
+	PhotoURL       *string   `json:"photo_url"`
+	Specialties    *[]string `json:"specialties"`
+	Certifications *[]string `json:"certifications"`
+}
+
+// InstructorDirectoryEntry is the public view of an instructor with their upcoming schedule.
+type InstructorDirectoryEntry struct {
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	Bio            string   `json:"bio"`
+	PhotoURL       string   `json:"photo_url"`
+	Specialties    []string `json:"specialties"`
+	Certifications []string `json:"certifications"`
+
+	Courses  []Course       `json:"courses"`
+	Sessions []ClassSession `json:"sessions"`
+}
+
+// Roles an instructor can hold on a course.
+const (
+	CourseInstructorRoleLead      = "lead"
+	CourseInstructorRoleAssistant = "assistant"
+)
+
+// CourseInstructor assigns an instructor to a course with a role. The lead is mirrored on Course.InstructorID.
+type CourseInstructor struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	CourseID     uint      `gorm:"column:course_id;not null;uniqueIndex:idx_course_instructor_pair" json:"course_id"`
+	InstructorID uint      `gorm:"column:instructor_id;not null;uniqueIndex:idx_course_instructor_pair;index" json:"instructor_id"`
+	Role         string    `gorm:"column:role;not null" json:"role"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Instructor Instructor `gorm:"foreignKey:InstructorID" json:"instructor"`
+}
+
+func (CourseInstructor) TableName() string { return "CourseInstructor" }
+
+// CourseInstructorInput assigns an instructor to a course.
+type CourseInstructorInput struct {
+	InstructorID uint   `json:"instructor_id" binding:"required"`
+	Role         string `json:"role" binding:"required,oneof=lead assistant"`
+
+	// AllowScheduleConflicts assigns the instructor even if the course double-books them.
+	AllowScheduleConflicts bool `json:"allow_schedule_conflicts"`
+}
+
+// SessionSubstituteInput assigns a substitute instructor to one session.
+type SessionSubstituteInput struct {
+	InstructorID uint `json:"instructor_id" binding:"required"`
+}
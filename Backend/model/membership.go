@@ -0,0 +1,115 @@
+package model
+
+import "time"
+
+// Membership plan kinds. Unlimited plans cover every booking while active;
+// class packs and drop-ins grant credits that are spent one per booking.
+const (
+	PlanKindUnlimited = "unlimited"
+	PlanKindClassPack = "class_pack"
+	PlanKindDropIn    = "drop_in"
+)
+
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusCanceled = "canceled"
+)
+
+// Credit ledger reasons.
+const (
+	CreditReasonGrant      = "grant"
+	CreditReasonBooking    = "booking"
+	CreditReasonRefund     = "refund"
+	CreditReasonAdjustment = "adjustment"
+)
+
+// MembershipPlan is something a member can hold: monthly unlimited, a class pack or a drop-in.
+type MembershipPlan struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Name string `gorm:"column:name;not null;uniqueIndex" json:"name"`
+	Kind string `gorm:"column:kind;not null" json:"kind"`
+
+	// Credits is how many class credits a class pack or drop-in grants. Granted credits do not expire.
+	Credits int `gorm:"column:credits;not null;default:0" json:"credits"`
+	// DurationDays is how long a subscription lasts; 0 never expires. Only unlimited plans need an active one to book.
+	DurationDays int `gorm:"column:duration_days;not null;default:0" json:"duration_days"`
+	PriceCents   int `gorm:"column:price_cents;not null;default:0" json:"price_cents"`
+
+	// Active plans can be granted; inactive ones only remain on existing subscriptions.
+	Active    bool      `gorm:"column:active;not null;default:true" json:"active"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (MembershipPlan) TableName() string { return "MembershipPlan" }
+
+// MembershipPlanInput creates or updates a membership plan.
+type MembershipPlanInput struct {
+	Name         string `json:"name" binding:"required"`
+	Kind         string `json:"kind" binding:"required,oneof=unlimited class_pack drop_in"`
+	Credits      int    `json:"credits" binding:"min=0"`
+	DurationDays int    `json:"duration_days" binding:"min=0"`
+	PriceCents   int    `json:"price_cents" binding:"min=0"`
+	Active       *bool  `json:"active"`
+}
+
+// MemberSubscription is a plan held by a member between two dates (YYYY-MM-DD, inclusive).
+type MemberSubscription struct {
+	ID       uint           `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID   uint           `gorm:"column:user_id;not null;index" json:"user_id"`
+	PlanID   uint           `gorm:"column:plan_id;not null" json:"plan_id"`
+	Plan     MembershipPlan `gorm:"foreignKey:PlanID" json:"plan"`
+	StartsOn string         `gorm:"column:starts_on;not null" json:"starts_on"`
+	EndsOn   *string        `gorm:"column:ends_on" json:"ends_on"` // nil never expires
+	Status   string         `gorm:"column:status;not null;default:'active'" json:"status"`
+
+	// GrantedBy is the manager who granted the subscription; nil when the member bought it.
+	GrantedBy *uint     `gorm:"column:granted_by" json:"granted_by"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (MemberSubscription) TableName() string { return "MemberSubscription" }
+
+// SubscriptionGrantInput grants a plan to a member. StartsOn defaults to today.
+type SubscriptionGrantInput struct {
+	PlanID   uint   `json:"plan_id" binding:"required"`
+	StartsOn string `json:"starts_on"`
+	Note     string `json:"note"`
+}
+
+// CreditLedgerEntry is one append-only change to a member's class credit balance.
+// Entries are never updated or deleted; corrections are new adjustment entries.
+type CreditLedgerEntry struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID uint   `gorm:"column:user_id;not null;index" json:"user_id"`
+	Delta  int    `gorm:"column:delta;not null" json:"delta"`
+	Reason string `gorm:"column:reason;not null" json:"reason"`
+	Note   string `gorm:"column:note" json:"note"`
+
+	// BalanceAfter is the member's balance once this entry applied.
+	BalanceAfter int `gorm:"column:balance_after;not null" json:"balance_after"`
+
+	SubscriptionID *uint `gorm:"column:subscription_id" json:"subscription_id"`
+	EnrollmentID   *uint `gorm:"column:enrollment_id;index" json:"enrollment_id"`
+	SessionID      *uint `gorm:"column:session_id" json:"session_id"`
+
+	// ActorID is the user who caused the entry: the member for bookings, the manager for grants and adjustments.
+	ActorID   uint      `gorm:"column:actor_id;not null" json:"actor_id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (CreditLedgerEntry) TableName() string { return "CreditLedgerEntry" }
+
+// CreditAdjustmentInput adds or removes credits by hand. A note explaining the change is required.
+type CreditAdjustmentInput struct {
+	Delta int    `json:"delta" binding:"required"`
+	Note  string `json:"note" binding:"required"`
+}
+
+// MemberBalance is a member's credits, subscriptions and ledger.
+type MemberBalance struct {
+	UserID        uint                 `json:"user_id"`
+	Credits       int                  `json:"credits"`
+	Unlimited     bool                 `json:"unlimited"`
+	Subscriptions []MemberSubscription `json:"subscriptions"`
+	Ledger        []CreditLedgerEntry  `json:"ledger"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Payment order statuses. Orders move pending -> authorized -> paid, and paid orders may be refunded;
+// pending or authorized orders fail when the provider declines them.
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusPaid       = "paid"
+	PaymentStatusFailed     = "failed"
+	PaymentStatusRefunded   = "refunded"
+)
+
+// PaymentOrder is a member's purchase of a membership plan through a payment provider.
+type PaymentOrder struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID      uint           `gorm:"column:user_id;not null;index" json:"user_id"`
+	PlanID      uint           `gorm:"column:plan_id;not null" json:"plan_id"`
+	Plan        MembershipPlan `gorm:"foreignKey:PlanID" json:"plan"`
+	AmountCents int            `gorm:"column:amount_cents;not null" json:"amount_cents"`
+	Currency    string         `gorm:"column:currency;not null" json:"currency"`
+
+	Provider         string `gorm:"column:provider;not null" json:"provider"`
+	ProviderIntentID string `gorm:"column:provider_intent_id;not null;uniqueIndex" json:"provider_intent_id"`
+	CheckoutURL      string `gorm:"column:checkout_url" json:"checkout_url"`
+	Status           string `gorm:"column:status;not null;default:'pending'" json:"status"`
+
+	// SubscriptionID is the subscription granted once the order was paid.
+	SubscriptionID *uint      `gorm:"column:subscription_id" json:"subscription_id"`
+	PaidAt         *time.Time `gorm:"column:paid_at" json:"paid_at"`
+	RefundedAt     *time.Time `gorm:"column:refunded_at" json:"refunded_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (PaymentOrder) TableName() string { return "PaymentOrder" }
+
+// PaymentEvent is a provider webhook that has been processed. The unique (provider, event_id)
+// pair makes redelivered webhooks no-ops.
+type PaymentEvent struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Provider   string    `gorm:"column:provider;not null;uniqueIndex:idx_payment_event_provider_event" json:"provider"`
+	EventID    string    `gorm:"column:event_id;not null;uniqueIndex:idx_payment_event_provider_event" json:"event_id"`
+	Type       string    `gorm:"column:type;not null" json:"type"`
+	OrderID    *uint     `gorm:"column:order_id;index" json:"order_id"`
+	Payload    string    `gorm:"column:payload" json:"payload"`
+	ReceivedAt time.Time `gorm:"column:received_at;autoCreateTime" json:"received_at"`
+}
+
+func (PaymentEvent) TableName() string { return "PaymentEvent" }
+
+// CheckoutInput starts the purchase of a membership plan.
+type CheckoutInput struct {
+	PlanID uint `json:"plan_id" binding:"required"`
+}
@@ -0,0 +1,27 @@
+package model
+
+// Role IDs of the seeded Role table.
+const (
+	RoleStudent      uint = 1
+	RoleSuperManager uint = 2
+	RoleManager      uint = 3
+	RoleInstructor   uint = 4
+)
+
+// Principal is the authenticated caller of a request, read once from its access token.
+type Principal struct {
+	UserID uint
+	RoleID uint
+	// TokenID is the token's jti claim.
+	TokenID string
+}
+
+// HasRole reports whether the principal holds one of roles.
+func (p Principal) HasRole(roles ...uint) bool {
+	for _, role := range roles {
+		if p.RoleID == role {
+			return true
+		}
+	}
+	return false
+}
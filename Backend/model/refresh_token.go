@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Reasons a refresh token was revoked.
+const (
+	RefreshRevokedRotated     = "rotated"
+	RefreshRevokedLogout      = "logout"
+	RefreshRevokedReuse       = "reuse"
+	RefreshRevokedRoleChanged = "role_changed"
+	RefreshRevokedPassword    = "password_changed"
+	RefreshRevokedUserDeleted = "user_deleted"
+)
+
+// RefreshToken is one refresh token of a login session. Every refresh revokes the presented token and
+// issues its replacement in the same family, so a family is one device's session. Only the SHA-256 of
+// the token is stored.
+type RefreshToken struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID        uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	FamilyID      string     `gorm:"column:family_id;not null;index" json:"family_id"`
+	TokenHash     string     `gorm:"column:token_hash;not null;uniqueIndex" json:"-"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	RevokedAt     *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	RevokedReason string     `gorm:"column:revoked_reason" json:"revoked_reason"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (RefreshToken) TableName() string { return "RefreshToken" }
+
+// AuthTokens is a short-lived access token with the refresh token that renews it.
+type AuthTokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the access token's lifetime in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// RefreshInput exchanges a refresh token for new tokens.
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput ends the session of a refresh token, or every session of its user with AllDevices.
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	AllDevices   bool   `json:"all_devices"`
+}
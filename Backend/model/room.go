@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Room is a studio where courses and sessions take place.
+type Room struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Name      string    `gorm:"column:name;not null;uniqueIndex" json:"name"`
+	Capacity  int       `gorm:"column:capacity;not null" json:"capacity"`
+	Equipment []string  `gorm:"column:equipment;serializer:json" json:"equipment"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (Room) TableName() string { return "Room" }
+
+// RoomInput creates or updates a room.
+type RoomInput struct {
+	Name      string   `json:"name" binding:"required"`
+	Capacity  int      `json:"capacity" binding:"required,min=1"`
+	Equipment []string `json:"equipment"`
+}
+
+// SessionRoomInput moves one session to another room. A nil RoomID falls back to the course's room.
+type SessionRoomInput struct {
+	RoomID *uint `json:"room_id"`
+}
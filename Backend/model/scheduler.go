@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// SchedulerRunResult summarizes what one scheduler pass changed.
+type SchedulerRunResult struct {
+	CoursesProcessed  int   `json:"courses_processed"`
+	SessionsCompleted int64 `json:"sessions_completed"`
+	Errors            int   `json:"errors"`
+}
+
+// SchedulerStatus is the admin-facing view of the background scheduler.
+type SchedulerStatus struct {
+	Running      bool                `json:"running"`
+	Interval     string              `json:"interval"`
+	HorizonWeeks int                 `json:"horizon_weeks"`
+	Runs         int                 `json:"runs"`
+	LastRunAt    *time.Time          `json:"last_run_at"`
+	LastDuration string              `json:"last_duration"`
+	LastError    string              `json:"last_error"`
+	LastResult   *SchedulerRunResult `json:"last_result"`
+	NextRunAt    *time.Time          `json:"next_run_at"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Session change actions recorded in the audit log.
+const (
+	SessionChangeCanceled    = "canceled"
+	SessionChangeRescheduled = "rescheduled"
+)
+
+// Outcomes of a session change for an affected enrollment.
+const (
+	SessionNotificationCanceled = "canceled"
+	SessionNotificationMoved    = "moved"
+)
+
+// SessionChangeLog is an audit entry for a canceled or rescheduled session.
+type SessionChangeLog struct {
+	ID                  uint       `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	SessionID           uint       `gorm:"column:session_id;not null;index" json:"session_id"`
+	CourseID            uint       `gorm:"column:course_id;not null;index" json:"course_id"`
+	Action              string     `gorm:"column:action;not null" json:"action"`
+	ActorID             uint       `gorm:"column:actor_id;not null" json:"actor_id"`
+	Reason              string     `gorm:"column:reason" json:"reason"`
+	PreviousStartAt     time.Time  `gorm:"column:previous_start_at;not null" json:"previous_start_at"`
+	PreviousEndAt       time.Time  `gorm:"column:previous_end_at;not null" json:"previous_end_at"`
+	PreviousCapacity    int        `gorm:"column:previous_capacity" json:"previous_capacity"`
+	NewStartAt          *time.Time `gorm:"column:new_start_at" json:"new_start_at"`
+	NewEndAt            *time.Time `gorm:"column:new_end_at" json:"new_end_at"`
+	NewCapacity         *int       `gorm:"column:new_capacity" json:"new_capacity"`
+	AffectedEnrollments int        `gorm:"column:affected_enrollments;not null;default:0" json:"affected_enrollments"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// SessionNotification tells a member how a session change affected their enrollment.
+type SessionNotification struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID       uint      `gorm:"column:user_id;not null;index" json:"user_id"`
+	SessionID    uint      `gorm:"column:session_id;not null" json:"session_id"`
+	ChangeID     uint      `gorm:"column:change_id;not null;index" json:"change_id"`
+	EnrollmentID uint      `gorm:"column:enrollment_id;not null" json:"enrollment_id"`
+	Outcome      string    `gorm:"column:outcome;not null" json:"outcome"`
+	Message      string    `gorm:"column:message;not null" json:"message"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (SessionChangeLog) TableName() string {
+	return "SessionChangeLog"
+}
+
+func (SessionNotification) TableName() string {
+	return "SessionNotification"
+}
+
+// SessionCancelInput is the payload for canceling a session.
+type SessionCancelInput struct {
+	Reason string `json:"reason"`
+}
+
+// SessionRescheduleInput is the payload for moving a session to a new time.
+type SessionRescheduleInput struct {
+	StartAt  time.Time `json:"start_at" binding:"required"` // RFC3339
+	EndAt    time.Time `json:"end_at" binding:"required"`   // RFC3339
+	Capacity *int      `json:"capacity" binding:"omitempty,min=1"`
+	Reason   string    `json:"reason"`
+}
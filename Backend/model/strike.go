@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Strike reasons.
+const (
+	StrikeReasonNoShow     = "no_show"
+	StrikeReasonLateCancel = "late_cancel"
+)
+
+// MemberStrike is a no-show or late cancellation counted against a member's booking privileges.
+type MemberStrike struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	UserID       uint   `gorm:"column:user_id;not null;index" json:"user_id"`
+	CourseID     uint   `gorm:"column:course_id;not null" json:"course_id"`
+	SessionID    *uint  `gorm:"column:session_id" json:"session_id"`
+	EnrollmentID *uint  `gorm:"column:enrollment_id;index" json:"enrollment_id"` // no-shows only; dropped enrollments are deleted
+	Reason       string `gorm:"column:reason;not null" json:"reason"`
+
+	// OccurredAt is when the missed session started or when the late cancel happened; the rolling window uses it.
+	OccurredAt time.Time `gorm:"column:occurred_at;not null;index" json:"occurred_at"`
+
+	// ClearedAt and ClearedBy are set when a manager forgives the strike.
+	ClearedAt *time.Time `gorm:"column:cleared_at" json:"cleared_at"`
+	ClearedBy *uint      `gorm:"column:cleared_by" json:"cleared_by"`
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (MemberStrike) TableName() string { return "MemberStrike" }
+
+// MemberStanding summarizes a member's active strikes and whether they may book.
+type MemberStanding struct {
+	Strikes       []MemberStrike `json:"strikes"`
+	ActiveStrikes int            `json:"active_strikes"`
+	StrikeLimit   int            `json:"strike_limit"`
+	WindowDays    int            `json:"window_days"`
+	Blocked       bool           `json:"blocked"`
+	BlockedUntil  *time.Time     `json:"blocked_until"`
+}
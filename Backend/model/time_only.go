@@ -8,7 +8,7 @@ import (
 	"time"
 )
 
-// TimeOnly stores a time-of-day without a date.
+// TimeOnly stores a time-of-day without a date, as wall-clock time in the facility time zone.
 type TimeOnly struct {
 	time.Time
 }
@@ -71,9 +71,9 @@ func (t *TimeOnly) parseString(value string) error {
 		return nil
 	}
 
-	parsed, err := time.ParseInLocation(timeOnlyLayout, value, time.Local)
+	parsed, err := time.ParseInLocation(timeOnlyLayout, value, FacilityLocation())
 	if err != nil {
-		parsed, err = time.ParseInLocation(timeOnlyShortLayout, value, time.Local)
+		parsed, err = time.ParseInLocation(timeOnlyShortLayout, value, FacilityLocation())
 		if err != nil {
 			return errors.New("invalid time format for TimeOnly")
 		}
@@ -18,6 +18,8 @@ type User struct {
 	RoleID    uint      `json:"role_id"`
 	Role      Role      `gorm:"foreignKey:RoleID" json:"role"`
 	CreatedAt time.Time `json:"created_at"`
+	// EmailVerifiedAt is when the user confirmed their email address; nil until then.
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"email_verified_at"`
 }
 
 type UserProfile struct {
@@ -28,6 +30,13 @@ type UserProfile struct {
 	Gender      *string `json:"gender"`
 	PhoneNumber *string `json:"phone_number"`
 	Address     *string `json:"address"`
+	// EmailVerifiedAt is nil until the member confirms their email address.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// Notes is what the member wants instructors to know, such as injuries; shown on session rosters.
+	Notes *string `json:"notes"`
+
+	// Standing is the member's no-show and late-cancel strike record.
+	Standing *MemberStanding `gorm:"-" json:"standing,omitempty"`
 }
 
 // PatchString distinguishes: missing vs null vs value
@@ -44,6 +53,7 @@ type UserProfilePatch struct {
 	Gender      PatchString `json:"gender"`
 	PhoneNumber PatchString `json:"phone_number"`
 	Address     PatchString `json:"address"`
+	Notes       PatchString `json:"notes"`
 }
 
 // UserInfo matches the user_info table structure.
@@ -54,6 +64,7 @@ type UserInfo struct {
 	Gender      string `json:"gender"`
 	PhoneNumber string `json:"phone_number"`
 	Address     string `json:"address"`
+	Notes       string `json:"notes"`
 }
 
 func (UserInfo) TableName() string { return "user_info" }
@@ -0,0 +1,145 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FakeProviderName is the name FakeProvider registers under.
+const FakeProviderName = "fake"
+
+// FakeProvider is an in-process payment provider. Intent and event IDs are sequential, so
+// runs are reproducible. Pay simulates the member completing checkout and returns the signed
+// webhook the provider would send.
+type FakeProvider struct {
+	secret string
+
+	mu      sync.Mutex
+	intents map[string]*Intent
+	intentN int
+	eventN  int
+}
+
+// NewFakeProvider returns a fake provider that signs its webhooks with webhookSecret.
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{secret: webhookSecret, intents: map[string]*Intent{}}
+}
+
+func (p *FakeProvider) Name() string { return FakeProviderName }
+
+func (p *FakeProvider) CreateCheckout(request CheckoutRequest) (*Intent, error) {
+	if request.AmountCents <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	currency := request.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.intentN++
+	intent := &Intent{
+		ID:          fmt.Sprintf("fake_pi_%d", p.intentN),
+		Status:      IntentRequiresPayment,
+		AmountCents: request.AmountCents,
+		Currency:    currency,
+	}
+	intent.CheckoutURL = "/payments/fake/intents/" + intent.ID + "/pay"
+	p.intents[intent.ID] = intent
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakeProvider) Capture(intentID string) (*Intent, error) {
+	return p.transition(intentID, IntentRequiresCapture, IntentSucceeded)
+}
+
+func (p *FakeProvider) Refund(intentID string, amountCents int) (*Intent, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	p.mu.Unlock()
+	if !ok {
+		return nil, errors.New("intent not found")
+	}
+	if amountCents <= 0 || amountCents > intent.AmountCents {
+		return nil, errors.New("invalid refund amount")
+	}
+	return p.transition(intentID, IntentSucceeded, IntentRefunded)
+}
+
+func (p *FakeProvider) ParseWebhook(payload []byte, header http.Header) (*Event, error) {
+	if !VerifySignature(p.secret, payload, header.Get(SignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.ID == "" || event.IntentID == "" {
+		return nil, errors.New("malformed webhook event")
+	}
+	return &event, nil
+}
+
+// Pay simulates the member authorizing the intent at checkout. It returns the signed
+// payment.authorized webhook; the caller delivers it.
+func (p *FakeProvider) Pay(intentID string) ([]byte, http.Header, error) {
+	if _, err := p.transition(intentID, IntentRequiresPayment, IntentRequiresCapture); err != nil {
+		return nil, nil, err
+	}
+	return p.Webhook(EventPaymentAuthorized, intentID)
+}
+
+// Decline simulates the member's payment failing and returns the signed payment.failed webhook.
+func (p *FakeProvider) Decline(intentID string) ([]byte, http.Header, error) {
+	if _, err := p.transition(intentID, IntentRequiresPayment, IntentFailed); err != nil {
+		return nil, nil, err
+	}
+	return p.Webhook(EventPaymentFailed, intentID)
+}
+
+// Webhook builds a signed webhook of eventType for the intent without changing it, for
+// replaying or simulating events sent from the provider's side.
+func (p *FakeProvider) Webhook(eventType string, intentID string) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, nil, errors.New("intent not found")
+	}
+	p.eventN++
+	event := Event{
+		ID:          fmt.Sprintf("fake_evt_%d", p.eventN),
+		Type:        eventType,
+		IntentID:    intent.ID,
+		AmountCents: intent.AmountCents,
+	}
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(SignatureHeader, Sign(p.secret, payload))
+	return payload, header, nil
+}
+
+func (p *FakeProvider) transition(intentID string, from string, to string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, errors.New("intent not found")
+	}
+	if intent.Status != from {
+		return nil, fmt.Errorf("intent is %s, not %s", intent.Status, from)
+	}
+	intent.Status = to
+	copied := *intent
+	return &copied, nil
+}
@@ -0,0 +1,66 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFakeProvider_CheckoutCaptureRefund(t *testing.T) {
+	provider := NewFakeProvider("secret")
+
+	first, err := provider.CreateCheckout(CheckoutRequest{AmountCents: 2500})
+	if err != nil {
+		t.Fatalf("failed to create checkout: %v", err)
+	}
+	second, _ := provider.CreateCheckout(CheckoutRequest{AmountCents: 900, Currency: "eur"})
+	if first.ID != "fake_pi_1" || second.ID != "fake_pi_2" || first.Currency != DefaultCurrency || second.Currency != "eur" {
+		t.Fatalf("expected sequential intents, got %+v and %+v", first, second)
+	}
+
+	if _, err := provider.Capture(first.ID); err == nil {
+		t.Fatalf("expected capture before payment to fail")
+	}
+	payload, header, err := provider.Pay(first.ID)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	event, err := provider.ParseWebhook(payload, header)
+	if err != nil || event.Type != EventPaymentAuthorized || event.IntentID != first.ID || event.ID != "fake_evt_1" {
+		t.Fatalf("expected a verified authorized event, got %+v (err %v)", event, err)
+	}
+
+	captured, err := provider.Capture(first.ID)
+	if err != nil || captured.Status != IntentSucceeded {
+		t.Fatalf("expected capture to succeed, got %+v (err %v)", captured, err)
+	}
+	if _, err := provider.Refund(first.ID, 3000); err == nil {
+		t.Fatalf("expected refunding more than was paid to fail")
+	}
+	refunded, err := provider.Refund(first.ID, 2500)
+	if err != nil || refunded.Status != IntentRefunded {
+		t.Fatalf("expected refund to succeed, got %+v (err %v)", refunded, err)
+	}
+}
+
+func TestFakeProvider_RejectsBadSignatures(t *testing.T) {
+	provider := NewFakeProvider("secret")
+	intent, _ := provider.CreateCheckout(CheckoutRequest{AmountCents: 100})
+	payload, header, err := provider.Webhook(EventPaymentSucceeded, intent.ID)
+	if err != nil {
+		t.Fatalf("failed to build webhook: %v", err)
+	}
+
+	tampered := append([]byte{}, payload...)
+	tampered[len(tampered)-2] = '9'
+	if _, err := provider.ParseWebhook(tampered, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected a tampered payload to be rejected, got %v", err)
+	}
+
+	other := NewFakeProvider("other-secret")
+	if _, err := other.ParseWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected a payload signed with another secret to be rejected, got %v", err)
+	}
+	if _, err := NewFakeProvider("").ParseWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected an unconfigured secret to reject every webhook, got %v", err)
+	}
+}
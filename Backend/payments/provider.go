@@ -0,0 +1,72 @@
+// Package payments abstracts the payment provider used to sell memberships and credit packs.
+// A provider creates checkout intents, captures and refunds them, and turns its signed
+// webhook requests into events. FakeProvider is a deterministic in-process provider for dev and tests.
+package payments
+
+import (
+	"errors"
+	"net/http"
+)
+
+// DefaultCurrency is used for checkouts when none is configured.
+const DefaultCurrency = "usd"
+
+// Intent statuses.
+const (
+	IntentRequiresPayment = "requires_payment"
+	IntentRequiresCapture = "requires_capture"
+	IntentSucceeded       = "succeeded"
+	IntentFailed          = "failed"
+	IntentRefunded        = "refunded"
+)
+
+// Webhook event types.
+const (
+	EventPaymentAuthorized = "payment.authorized"
+	EventPaymentSucceeded  = "payment.succeeded"
+	EventPaymentFailed     = "payment.failed"
+	EventPaymentRefunded   = "payment.refunded"
+)
+
+// ErrInvalidSignature is returned for webhook requests whose signature does not verify.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// CheckoutRequest asks a provider to start collecting a payment.
+type CheckoutRequest struct {
+	AmountCents int
+	Currency    string
+	Description string
+	// Reference is our identifier for the purchase, echoed back by the provider.
+	Reference string
+}
+
+// Intent is a provider's record of one payment.
+type Intent struct {
+	ID          string
+	Status      string
+	AmountCents int
+	Currency    string
+	// CheckoutURL is where the member completes the payment.
+	CheckoutURL string
+}
+
+// Event is a verified webhook notification about an intent.
+type Event struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	IntentID    string `json:"intent_id"`
+	AmountCents int    `json:"amount_cents"`
+}
+
+// Provider is a payment service FitFlow can sell through.
+type Provider interface {
+	// Name identifies the provider in webhook URLs and on stored orders.
+	Name() string
+	CreateCheckout(request CheckoutRequest) (*Intent, error)
+	// Capture collects an authorized intent.
+	Capture(intentID string) (*Intent, error)
+	// Refund returns amountCents of a captured intent to the payer.
+	Refund(intentID string, amountCents int) (*Intent, error)
+	// ParseWebhook verifies a webhook request's signature and decodes its event.
+	ParseWebhook(payload []byte, header http.Header) (*Event, error)
+}
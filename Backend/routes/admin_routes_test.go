@@ -0,0 +1,43 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"my-course-backend/model"
+	"my-course-backend/routes"
+)
+
+func TestSchedulerStatusEndpoint_SuperManagerOnly(t *testing.T) {
+	setupRouteTestDB(t)
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodGet, "/admin/scheduler", makeToken(t, 1, 3), nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/admin/scheduler/run", makeToken(t, 1, 2), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, "/admin/scheduler", makeToken(t, 1, 2), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		Scheduler model.SchedulerStatus `json:"scheduler"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Scheduler.Running {
+		t.Fatalf("expected scheduler not running in tests")
+	}
+	if response.Scheduler.LastRunAt == nil {
+		t.Fatalf("expected last run to be recorded after manual run")
+	}
+}
@@ -0,0 +1,231 @@
+package routes_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+
+	"my-course-backend/model"
+	"my-course-backend/routes"
+	"my-course-backend/service"
+	"my-course-backend/tokens"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// publicRoutes are the only routes reachable without a token.
+var publicRoutes = map[string]bool{
+	"POST /auth/register":               true,
+	"POST /auth/manager/register":       true,
+	"POST /auth/login":                  true,
+	"POST /auth/refresh":                true,
+	"POST /auth/logout":                 true,
+	"POST /auth/password/forgot":        true,
+	"POST /auth/password/reset":         true,
+	"POST /auth/email/verify":           true,
+	"GET /classes":                      true,
+	"GET /classes/categories":           true,
+	"GET /classes/:id":                  true,
+	"GET /classes/:id/sessions":         true,
+	"GET /classes/:id/instructors":      true,
+	"GET /rooms":                        true,
+	"GET /rooms/:id":                    true,
+	"GET /blackouts":                    true,
+	"GET /booking-policies":             true,
+	"GET /booking-policies/:id":         true,
+	"GET /memberships/plans":            true,
+	"POST /payments/webhooks/:provider": true,
+	"GET /instructors":                  true,
+	"GET /instructors/:id":              true,
+	"GET /.well-known/jwks.json":        true,
+}
+
+func TestRouter_EveryNonPublicRouteRequiresAToken(t *testing.T) {
+	setupRouteTestDB(t)
+	router := routes.SetupRouter()
+
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		if publicRoutes[key] {
+			continue
+		}
+		path := route.Path
+		for _, segment := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				path = strings.Replace(path, segment, "1", 1)
+			}
+		}
+		if rec := performJSONRequest(t, router, route.Method, path, "", nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without a token, got %d: %s", key, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestRouter_RolesAreEnforcedByTheRouteGroup(t *testing.T) {
+	setupRouteTestDB(t)
+	router := routes.SetupRouter()
+	studentToken := makeToken(t, 1, 1)
+	instructorToken := makeToken(t, 2, 4)
+
+	cases := []struct {
+		method, path, token string
+		want                int
+	}{
+		{http.MethodGet, "/manager/users", studentToken, http.StatusForbidden},
+		{http.MethodGet, "/admin/scheduler", studentToken, http.StatusForbidden},
+		{http.MethodDelete, "/users/1", studentToken, http.StatusForbidden},
+		{http.MethodGet, "/instructor/courses", studentToken, http.StatusForbidden},
+		{http.MethodPost, "/classes/register", instructorToken, http.StatusForbidden},
+		{http.MethodGet, "/manager/users", "not-a-token", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		if rec := performJSONRequest(t, router, tc.method, tc.path, tc.token, nil); rec.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tc.method, tc.path, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"id": 1, "role_id": 2}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to build token: %v", err)
+	}
+	if rec := performJSONRequest(t, router, http.MethodGet, "/admin/scheduler", unsigned, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unsigned token to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestJWKS_PublishesTheAsymmetricSigningKey(t *testing.T) {
+	setupRouteTestDB(t)
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	key, err := tokens.ParseKey("rsa-1", tokens.AlgRS256, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+	keyring, _ := tokens.NewKeyring("rsa-1", key)
+	service.SetTokenKeys(keyring)
+	t.Cleanup(func() { service.SetTokenKeys(service.NewDevKeyring()) })
+	router := routes.SetupRouter()
+
+	rec := performJSONRequest(t, router, http.MethodGet, "/.well-known/jwks.json", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var set tokens.JWKS
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatalf("failed to decode JWKS: %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "rsa-1" || set.Keys[0].Alg != tokens.AlgRS256 {
+		t.Fatalf("expected the rsa-1 key, got %+v", set.Keys)
+	}
+
+	// A token signed with the RS256 key authenticates requests.
+	studentToken := makeToken(t, 1, 1)
+	if rec := performJSONRequest(t, router, http.MethodGet, "/manager/users", studentToken, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected the RS256 token to authenticate (403 for a student), got %d", rec.Code)
+	}
+}
+
+func TestAuthSessions_LoginRefreshAndLogout(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, model.RoleStudent, "Student")
+	user := seedRouteUser(t, model.RoleStudent, "secret123")
+	router := routes.SetupRouter()
+
+	type tokenResponse struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	decode := func(body []byte) tokenResponse {
+		var response tokenResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			t.Fatalf("failed to decode tokens: %v", err)
+		}
+		return response
+	}
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/auth/login", "", map[string]string{"email": user.Email, "password": "secret123"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected login to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	login := decode(rec.Body.Bytes())
+	if login.Token == "" || login.RefreshToken == "" || login.ExpiresIn <= 0 {
+		t.Fatalf("expected an access and refresh token, got %s", rec.Body.String())
+	}
+
+	rec = performJSONRequest(t, router, http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": login.RefreshToken})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected refresh to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	refreshed := decode(rec.Body.Bytes())
+	if rec := performJSONRequest(t, router, http.MethodGet, "/auth/profile", refreshed.Token, nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected the refreshed access token to work, got %d", rec.Code)
+	}
+
+	if rec := performJSONRequest(t, router, http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": login.RefreshToken}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a used refresh token to be rejected, got %d", rec.Code)
+	}
+	if rec := performJSONRequest(t, router, http.MethodPost, "/auth/refresh", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a missing refresh token to be rejected, got %d", rec.Code)
+	}
+
+	// Reuse ended the session, so log in again and sign out of every device.
+	rec = performJSONRequest(t, router, http.MethodPost, "/auth/login", "", map[string]string{"email": user.Email, "password": "secret123"})
+	second := decode(rec.Body.Bytes())
+	rec = performJSONRequest(t, router, http.MethodPost, "/auth/logout", "", map[string]any{"refresh_token": second.RefreshToken, "all_devices": true})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected logout to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := performJSONRequest(t, router, http.MethodPost, "/auth/refresh", "", map[string]string{"refresh_token": second.RefreshToken}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a logged out refresh token to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestAccountEndpoints_ChangeAndResetPassword(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, model.RoleStudent, "Student")
+	user := seedRouteUser(t, model.RoleStudent, "secret123")
+	router := routes.SetupRouter()
+	token := issueRouteToken(t, user.Email, "secret123")
+
+	rec := performJSONRequest(t, router, http.MethodPut, "/auth/password", token, map[string]string{"current_password": "wrong", "new_password": "secret456"})
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a wrong current password to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = performJSONRequest(t, router, http.MethodPut, "/auth/password", token, map[string]string{"current_password": "secret123", "new_password": "123"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a short password to be rejected, got %d", rec.Code)
+	}
+	rec = performJSONRequest(t, router, http.MethodPut, "/auth/password", token, map[string]string{"current_password": "secret123", "new_password": "secret456"})
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "refresh_token") {
+		t.Fatalf("expected the password to change with new tokens, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := performJSONRequest(t, router, http.MethodPost, "/auth/login", "", map[string]string{"email": user.Email, "password": "secret456"}); rec.Code != http.StatusOK {
+		t.Fatalf("expected the new password to log in, got %d", rec.Code)
+	}
+
+	// Unknown and known addresses get the same answer.
+	for _, email := range []string{user.Email, "nobody@example.com"} {
+		rec := performJSONRequest(t, router, http.MethodPost, "/auth/password/forgot", "", map[string]string{"email": email})
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected 202 for %s, got %d", email, rec.Code)
+		}
+	}
+	rec = performJSONRequest(t, router, http.MethodPost, "/auth/password/reset", "", map[string]string{"token": "not-a-token", "new_password": "secret789"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown reset token to be rejected, got %d", rec.Code)
+	}
+	rec = performJSONRequest(t, router, http.MethodPost, "/auth/email/verify", "", map[string]string{"token": "not-a-token"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown verification token to be rejected, got %d", rec.Code)
+	}
+}
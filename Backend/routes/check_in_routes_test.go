@@ -0,0 +1,77 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"my-course-backend/api"
+	"my-course-backend/db"
+	"my-course-backend/model"
+	"my-course-backend/routes"
+	"my-course-backend/service"
+)
+
+func TestCheckInEndpoints_CodeAndKioskScan(t *testing.T) {
+	setupRouteTestDB(t)
+	service.SetKioskKey("front-desk")
+	t.Cleanup(func() { service.SetKioskKey("") })
+
+	seedRouteRole(t, 1, "Student")
+	student := seedRouteUser(t, 1, "secret123")
+	course := seedRouteCourse(t, "Rowing", 5, "Cardio")
+	startAt := time.Now().Add(10 * time.Minute)
+	session := model.ClassSession{
+		CourseID:    course.ID,
+		SessionDate: startAt.Format("2006-01-02"),
+		StartAt:     startAt,
+		EndAt:       startAt.Add(time.Hour),
+		Status:      "scheduled",
+		Capacity:    5,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		t.Fatalf("failed to seed class session: %v", err)
+	}
+	seedRouteEnrollmentForSession(t, student.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/classes/sessions/%d/check-in-code", session.ID), studentToken, nil)
+	var issued struct {
+		CheckIn model.CheckInCode `json:"check_in"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &issued); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if recorder.Code != http.StatusOK || issued.CheckIn.Token == "" {
+		t.Fatalf("expected a check-in code, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	payload := map[string]string{"token": issued.CheckIn.Token}
+	if recorder = performJSONRequest(t, router, http.MethodPost, "/check-in", studentToken, payload); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a member checking themselves in, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	scan := func(key string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/check-in", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(api.KioskKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	if recorder = scan("wrong-key"); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad kiosk key, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = scan("front-desk"); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the kiosk scan to succeed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = scan("front-desk"); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a second scan, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -43,14 +43,20 @@ func setupRouteTestDB(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.ClassSession{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
 	db.DB = testDB
+	service.SetMembershipRequired(false)
 }
 
 func seedRouteRole(t *testing.T, roleID uint, roleName string) model.Role {
@@ -198,7 +204,7 @@ func seedRouteEnrollmentAt(t *testing.T, userID uint, courseID uint, status stri
 func issueRouteToken(t *testing.T, email string, password string) string {
 	t.Helper()
 
-	token, _, err := service.LoginUserWithRole(model.LoginInput{
+	tokens, _, err := service.LoginUserWithRole(model.LoginInput{
 		Email:    email,
 		Password: password,
 	})
@@ -206,7 +212,7 @@ func issueRouteToken(t *testing.T, email string, password string) string {
 		t.Fatalf("failed to issue token: %v", err)
 	}
 
-	return token
+	return tokens.AccessToken
 }
 
 func performJSONRequest(t *testing.T, router http.Handler, method string, path string, token string, payload any) *httptest.ResponseRecorder {
@@ -396,6 +402,90 @@ func TestListClassesEndpoint_OK(t *testing.T) {
 	}
 }
 
+func TestListClassesEndpoint_FiltersByScheduleAndPages(t *testing.T) {
+	setupRouteTestDB(t)
+	morning := seedRouteCourseWithSchedule(t, "Sunrise Yoga", 10, "Wellness", "07:00", "08:00", "Monday")
+	seedRouteCourseWithSchedule(t, "Evening Yoga", 10, "Wellness", "19:00", "20:00", "Monday")
+	seedRouteCourseWithSchedule(t, "Tuesday Yoga", 10, "Wellness", "07:00", "08:00", "Tuesday")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodGet, "/classes?weekday=mon&end_before=12:00&q=yoga", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	var response struct {
+		Classes    []model.Course `json:"classes"`
+		Total      int64          `json:"total"`
+		TotalPages int            `json:"total_pages"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Total != 1 || len(response.Classes) != 1 || response.Classes[0].ID != morning.ID {
+		t.Fatalf("expected only the Monday morning class, got %s", recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, "/classes?sort=name&limit=2", "", nil)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Total != 3 || response.TotalPages != 2 || len(response.Classes) != 2 || response.Classes[0].CourseName != "Evening Yoga" {
+		t.Fatalf("expected first page of 2 sorted by name, got %s", recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, "/classes?start_after=7pm", "", nil)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestRoomsEndpoint_CreateAndFilterClasses(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 3, "Manager")
+	seedRouteRole(t, 1, "Student")
+	manager := seedRouteUser(t, 3, "secret123")
+	student := seedRouteUser(t, 1, "secret123")
+	managerToken := issueRouteToken(t, manager.Email, "secret123")
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	inRoom := seedRouteCourse(t, "Course A", 3, "Cardio")
+	seedRouteCourse(t, "Course B", 2, "Strength")
+	router := routes.SetupRouter()
+
+	payload := map[string]any{"name": "Studio 1", "capacity": 20, "equipment": []string{"Bikes"}}
+	recorder := performJSONRequest(t, router, http.MethodPost, "/rooms", studentToken, payload)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 for a student, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/rooms", managerToken, payload)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	var created struct {
+		Room model.Room `json:"room"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if err := db.DB.Model(&model.Course{}).Where("id = ?", inRoom.ID).Update("room_id", created.Room.ID).Error; err != nil {
+		t.Fatalf("failed to assign room: %v", err)
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/classes?room_id=%d", created.Room.ID), "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	var response struct {
+		Classes []model.Course `json:"classes"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Classes) != 1 || response.Classes[0].ID != inRoom.ID || response.Classes[0].Room == nil {
+		t.Fatalf("expected only the class in the room, got %s", recorder.Body.String())
+	}
+}
+
 func TestGetUserAnalyticsEndpoint_OK(t *testing.T) {
 	setupRouteTestDB(t)
 	seedRouteRole(t, 1, "Student")
@@ -474,3 +564,159 @@ func TestGetUserAnalyticsEndpoint_Forbidden(t *testing.T) {
 		t.Fatalf("unexpected error: %s", response.Error)
 	}
 }
+
+func TestRegisterSessionEndpoint_CreatedAndListed(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	user := seedRouteUser(t, 1, "secret123")
+	course := seedRouteCourse(t, "Barre", 2, "Core")
+	startAt := time.Now().Add(3 * time.Hour)
+	session := model.ClassSession{
+		CourseID:    course.ID,
+		SessionDate: startAt.Format("2006-01-02"),
+		StartAt:     startAt,
+		EndAt:       startAt.Add(time.Hour),
+		Status:      "scheduled",
+		Capacity:    2,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		t.Fatalf("failed to seed session: %v", err)
+	}
+	token := issueRouteToken(t, user.Email, "secret123")
+	router := routes.SetupRouter()
+
+	path := fmt.Sprintf("/classes/sessions/%d/register", session.ID)
+	recorder := performJSONRequest(t, router, http.MethodPost, path, token, nil)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/classes/%d/sessions", course.ID), "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		Sessions []model.ClassSession `json:"sessions"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, s := range response.Sessions {
+		if s.ID == session.ID && s.Spot != 1 {
+			t.Fatalf("expected booked session spot 1, got %d", s.Spot)
+		}
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, fmt.Sprintf("/classes/sessions/%d/drop", session.ID), token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestBlackoutsEndpoint_ManagerCRUD(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 3, "Manager")
+	seedRouteRole(t, 1, "Student")
+	manager := seedRouteUser(t, 3, "secret123")
+	student := seedRouteUser(t, 1, "secret123")
+	managerToken := issueRouteToken(t, manager.Email, "secret123")
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	router := routes.SetupRouter()
+
+	date := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	payload := map[string]any{"date": date, "reason": "Public holiday"}
+	recorder := performJSONRequest(t, router, http.MethodPost, "/blackouts", studentToken, payload)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 for a student, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/blackouts", managerToken, payload)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	var created struct {
+		Blackout model.BlackoutDate `json:"blackout"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/blackouts", managerToken, payload)
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for a duplicate date, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, "/blackouts", "", nil)
+	var listed struct {
+		Blackouts []model.BlackoutDate `json:"blackouts"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if recorder.Code != http.StatusOK || len(listed.Blackouts) != 1 || listed.Blackouts[0].Date != date {
+		t.Fatalf("expected the blackout in the public listing, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	path := fmt.Sprintf("/blackouts/%d", created.Blackout.ID)
+	recorder = performJSONRequest(t, router, http.MethodDelete, path, managerToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = performJSONRequest(t, router, http.MethodDelete, path, managerToken, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestBookingPolicyEndpoint_RefusalsCarryCodes(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 3, "Manager")
+	seedRouteRole(t, 1, "Student")
+	manager := seedRouteUser(t, 3, "secret123")
+	student := seedRouteUser(t, 1, "secret123")
+	managerToken := issueRouteToken(t, manager.Email, "secret123")
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	router := routes.SetupRouter()
+
+	payload := map[string]any{"name": "Strength rules", "category": "Strength", "opens_hours_before": 25, "cancel_cutoff_minutes": 120}
+	recorder := performJSONRequest(t, router, http.MethodPost, "/booking-policies", studentToken, payload)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 for a student, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = performJSONRequest(t, router, http.MethodPost, "/booking-policies", managerToken, payload)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	course := seedRouteCourse(t, "Deadlifts", 5, "Strength")
+	startAt := time.Now().Add(time.Hour)
+	session := model.ClassSession{
+		CourseID:    course.ID,
+		SessionDate: startAt.Format("2006-01-02"),
+		StartAt:     startAt,
+		EndAt:       startAt.Add(time.Hour),
+		Status:      "scheduled",
+		Capacity:    5,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		t.Fatalf("failed to seed session: %v", err)
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, fmt.Sprintf("/classes/sessions/%d/register", session.ID), studentToken, nil)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, fmt.Sprintf("/classes/sessions/%d/drop", session.ID), studentToken, nil)
+	var refusal struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &refusal); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if recorder.Code != http.StatusConflict || refusal.Code != "cancel_cutoff_passed" {
+		t.Fatalf("expected a 409 cancel_cutoff_passed refusal, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -1,15 +1,18 @@
 package routes_test
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"my-course-backend/db"
 	"my-course-backend/model"
 	"my-course-backend/routes"
+	"my-course-backend/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
@@ -35,14 +38,20 @@ func setupInstructorTestDB(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.ClassSession{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
 	db.DB = testDB
+	service.SetMembershipRequired(false)
 }
 
 func seedInstructorUser(t *testing.T) (model.User, string) {
@@ -84,22 +93,27 @@ func seedCourseWithInstructor(t *testing.T, instructorID uint, name string, capa
 	startTime, _ := model.ParseTimeOnly("09:00")
 	endTime, _ := model.ParseTimeOnly("10:00")
 
-	// Look up instructor's name so the Course.Instructor column is populated correctly.
+	// Link the course to the instructor's profile, creating it on first use.
 	var instructorUser model.User
 	if err := db.DB.First(&instructorUser, instructorID).Error; err != nil {
 		t.Fatalf("failed to load instructor user: %v", err)
 	}
+	profile := model.Instructor{UserID: instructorUser.ID, Name: instructorUser.Name}
+	if err := db.DB.Where("user_id = ?", instructorUser.ID).FirstOrCreate(&profile).Error; err != nil {
+		t.Fatalf("failed to seed instructor profile: %v", err)
+	}
 
 	course := model.Course{
-		CourseName: name,
-		CourseCode: fmt.Sprintf("INS-%d", time.Now().UnixNano()),
-		Capacity:   capacity,
-		Category:   "Fitness",
-		StartTime:  startTime,
-		EndTime:    endTime,
-		Weekday:    "Monday",
-		Instructor: instructorUser.Name,
-		Duration:   60,
+		CourseName:   name,
+		CourseCode:   fmt.Sprintf("INS-%d", time.Now().UnixNano()),
+		Capacity:     capacity,
+		Category:     "Fitness",
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Weekday:      "Monday",
+		InstructorID: &profile.ID,
+		Instructor:   profile.Name,
+		Duration:     60,
 	}
 	if err := db.DB.Create(&course).Error; err != nil {
 		t.Fatalf("failed to seed course: %v", err)
@@ -382,3 +396,203 @@ func TestInstructorUpdateEnrollmentStatus_EnrollmentNotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
 	}
 }
+
+func TestInstructorCancelSessionEndpoint(t *testing.T) {
+	setupInstructorTestDB(t)
+	instructor, token := seedInstructorUser(t)
+	student := seedTestUser(t)
+	course := seedCourseWithInstructor(t, instructor.ID, "Yoga", 5)
+	enrollment := seedEnrollmentWithSession(t, student.ID, course.ID, model.EnrollmentStatusEnrolled)
+	router := routes.SetupRouter()
+
+	path := fmt.Sprintf("/instructor/sessions/%d/cancel", *enrollment.SessionID)
+	w := performJSONRequest(t, router, http.MethodPost, path, makeToken(t, student.ID, 1), nil)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-instructor, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performJSONRequest(t, router, http.MethodPost, path, token, map[string]string{"reason": "Instructor sick"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Change model.SessionChangeLog `json:"change"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Change.AffectedEnrollments != 1 || resp.Change.Reason != "Instructor sick" {
+		t.Fatalf("unexpected change log entry: %+v", resp.Change)
+	}
+
+	w = performJSONRequest(t, router, http.MethodGet, "/classes/notifications", makeToken(t, student.ID, 1), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var listResp struct {
+		Notifications []model.SessionNotification `json:"notifications"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(listResp.Notifications) != 1 || listResp.Notifications[0].Outcome != model.SessionNotificationCanceled {
+		t.Fatalf("expected one canceled notification, got %+v", listResp.Notifications)
+	}
+}
+
+func TestInstructorProfileAndDirectory(t *testing.T) {
+	setupInstructorTestDB(t)
+	instructor, token := seedInstructorUser(t)
+	course := seedCourseWithInstructor(t, instructor.ID, "Yoga", 10)
+	router := routes.SetupRouter()
+
+	payload := map[string]any{
+		"bio":            "  Ten years of vinyasa.  ",
+		"photo_url":      "https://example.com/coach.jpg",
+		"specialties":    []string{"Yoga", " yoga ", "Mobility", ""},
+		"certifications": []string{"RYT-500"},
+	}
+	rec := performJSONRequest(t, router, http.MethodPatch, "/instructor/profile", token, payload)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = performJSONRequest(t, router, http.MethodGet, "/instructors", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Instructors []model.InstructorDirectoryEntry `json:"instructors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(resp.Instructors) != 1 {
+		t.Fatalf("expected 1 instructor, got %s", rec.Body.String())
+	}
+	entry := resp.Instructors[0]
+	if entry.Bio != "Ten years of vinyasa." || len(entry.Specialties) != 2 || entry.Certifications[0] != "RYT-500" {
+		t.Fatalf("unexpected profile fields: %+v", entry)
+	}
+	if len(entry.Courses) != 1 || entry.Courses[0].ID != course.ID || len(entry.Sessions) != 1 {
+		t.Fatalf("expected the course and its upcoming session, got %+v", entry)
+	}
+
+	rec = performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/instructors/%d", entry.ID), "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = performJSONRequest(t, router, http.MethodGet, "/instructors/9999", "", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// ─── PUT /instructor/sessions/:session_id/attendance ─────────────
+
+func TestInstructorMarkSessionAttendance_ReportsEachRow(t *testing.T) {
+	setupInstructorTestDB(t)
+	instructor, token := seedInstructorUser(t)
+	course := seedCourseWithInstructor(t, instructor.ID, "Rowing", 20)
+	present := seedTestUser(t)
+	absent := seedTestUser(t)
+	enrollment := seedEnrollmentWithSession(t, present.ID, course.ID, model.EnrollmentStatusEnrolled)
+	seedEnrollmentWithSession(t, absent.ID, course.ID, model.EnrollmentStatusEnrolled)
+	router := routes.SetupRouter()
+
+	path := fmt.Sprintf("/instructor/sessions/%d/attendance", *enrollment.SessionID)
+	body := map[string]interface{}{
+		"marks": []map[string]interface{}{
+			{"user_id": present.ID, "status": "attended"},
+			{"user_id": absent.ID, "status": "missed"},
+			{"user_id": 9999, "status": "attended"},
+		},
+	}
+	rec := performJSONRequest(t, router, http.MethodPut, path, token, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Results []model.AttendanceMarkResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(resp.Results) != 3 || resp.Results[0].Result != model.AttendanceResultUpdated ||
+		resp.Results[1].Result != model.AttendanceResultUpdated || resp.Results[2].Result != model.AttendanceResultRejected {
+		t.Fatalf("unexpected results: %s", rec.Body.String())
+	}
+
+	var stored model.Enrollment
+	db.DB.First(&stored, enrollment.ID)
+	if stored.Status != model.EnrollmentStatusAttended {
+		t.Fatalf("expected status 'attended', got '%s'", stored.Status)
+	}
+
+	rec = performJSONRequest(t, router, http.MethodPut, path, token, map[string]interface{}{"marks": []interface{}{}})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for no marks, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// ─── GET /instructor/courses/:id/sessions, /instructor/sessions/:session_id/roster ───
+
+func TestInstructorSessionRoster_ListsAndExports(t *testing.T) {
+	setupInstructorTestDB(t)
+	instructor, token := seedInstructorUser(t)
+	course := seedCourseWithInstructor(t, instructor.ID, "Barre", 20)
+	user := seedTestUser(t)
+	enrollment := seedEnrollmentWithSession(t, user.ID, course.ID, model.EnrollmentStatusEnrolled)
+	if err := db.DB.Create(&model.UserInfo{UserID: user.ID, Notes: "=HYPERLINK(\"x\")"}).Error; err != nil {
+		t.Fatalf("failed to seed user info: %v", err)
+	}
+	router := routes.SetupRouter()
+
+	rec := performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/instructor/courses/%d/sessions", course.ID), token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var sessions struct {
+		Sessions []model.InstructorSession `json:"sessions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(sessions.Sessions) != 1 || sessions.Sessions[0].ID != *enrollment.SessionID || sessions.Sessions[0].Seated != 1 {
+		t.Fatalf("unexpected sessions: %s", rec.Body.String())
+	}
+
+	path := fmt.Sprintf("/instructor/sessions/%d/roster", *enrollment.SessionID)
+	rec = performJSONRequest(t, router, http.MethodGet, path, token, nil)
+	var roster struct {
+		Roster model.SessionRoster `json:"roster"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &roster); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if rec.Code != http.StatusOK || len(roster.Roster.Entries) != 1 || roster.Roster.Entries[0].UserID != user.ID {
+		t.Fatalf("unexpected roster %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = performJSONRequest(t, router, http.MethodGet, path+"?format=csv", token, nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected a CSV export, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[1][1] != user.Email || rows[1][len(rows[1])-1] != "'=HYPERLINK(\"x\")" {
+		t.Fatalf("unexpected CSV rows: %q", rows)
+	}
+
+	student := seedTestUser(t)
+	rec = performJSONRequest(t, router, http.MethodGet, path, makeToken(t, student.ID, 1), nil)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a student, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
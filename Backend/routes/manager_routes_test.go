@@ -12,10 +12,10 @@ import (
 	"my-course-backend/db"
 	"my-course-backend/model"
 	"my-course-backend/routes"
+	"my-course-backend/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
@@ -39,14 +39,20 @@ func setupManagerRouteTestDB(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 		&model.ManagerInviteCode{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
 	db.DB = testDB
+	service.SetMembershipRequired(false)
 }
 
 func seedRole(t *testing.T, id uint, name string) {
@@ -79,21 +85,10 @@ func seedCourseForUpdate(t *testing.T) model.Course {
 	return c
 }
 
-// IMPORTANT: 你的 service/auth_service.go 里 jwtSecret 是硬编码的 []byte("my_super_secret_key_2026")。
-// 这里为了让 role 校验通过，需要生成同样 secret 的 token。
-var jwtSecretForTests = []byte("my_super_secret_key_2026")
-
 func makeToken(t *testing.T, userID uint, roleID uint) string {
 	t.Helper()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":      userID,
-		"email":   "test@example.com",
-		"role_id": roleID,
-		"exp":     time.Now().Add(2 * time.Hour).Unix(),
-	})
-
-	s, err := token.SignedString(jwtSecretForTests)
+	s, err := service.IssueAccessToken(userID, "test@example.com", roleID)
 	if err != nil {
 		t.Fatalf("failed to sign token: %v", err)
 	}
@@ -198,14 +193,20 @@ func setupManagerTestDBWithSessions(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.ClassSession{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 		&model.ManagerInviteCode{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 	db.DB = testDB
+	service.SetMembershipRequired(false)
 }
 
 func seedManagerCourseWithSession(t *testing.T, name string, capacity int) model.Course {
@@ -550,4 +551,304 @@ func TestManagerListUserEnrollments_UserNotFound(t *testing.T) {
 	if w.Code != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
 	}
-}
\ No newline at end of file
+}
+
+// ─── PUT /classes/sessions/:session_id/substitute ────────────────
+
+func TestManagerSetSessionSubstitute_GrantsInstructorAccess(t *testing.T) {
+	setupManagerTestDBWithSessions(t)
+	seedRole(t, 3, "Manager")
+	seedRole(t, 4, "Instructor")
+
+	user := model.User{Name: "Sub Coach", Email: fmt.Sprintf("sub-%d@example.com", time.Now().UnixNano()), Password: "pass", RoleID: 4}
+	db.DB.Create(&user)
+	profile := model.Instructor{UserID: user.ID, Name: user.Name}
+	db.DB.Create(&profile)
+
+	course := seedManagerCourseWithSession(t, "Spin", 10)
+	var session model.ClassSession
+	db.DB.Where("course_id = ?", course.ID).First(&session)
+
+	r := routes.SetupRouter()
+	instructorToken := makeToken(t, user.ID, 4)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/instructor/courses/%d/enrollments", course.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+instructorToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 before assignment, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body, _ := json.Marshal(map[string]uint{"instructor_id": profile.ID})
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/classes/sessions/%d/substitute", session.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+makeToken(t, 999, 3))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/instructor/courses/%d/enrollments", course.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+instructorToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for substitute, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/instructor/courses", nil)
+	req.Header.Set("Authorization", "Bearer "+instructorToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp struct {
+		SubstituteSessions []model.ClassSession `json:"substitute_sessions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.SubstituteSessions) != 1 || resp.SubstituteSessions[0].ID != session.ID {
+		t.Fatalf("expected covered session %d, got %s", session.ID, w.Body.String())
+	}
+}
+
+// ─── GET /manager/instructors/:id/schedule ───────────────────────
+
+func TestManagerInstructorScheduleAndConflict(t *testing.T) {
+	setupManagerTestDBWithSessions(t)
+	seedRole(t, 3, "Manager")
+	seedRole(t, 4, "Instructor")
+
+	user := model.User{Name: "Coach", Email: fmt.Sprintf("coach-%d@example.com", time.Now().UnixNano()), Password: "pass", RoleID: 4}
+	db.DB.Create(&user)
+	profile := model.Instructor{UserID: user.ID, Name: user.Name}
+	db.DB.Create(&profile)
+
+	r := routes.SetupRouter()
+	token := makeToken(t, 999, 3) // manager
+
+	create := func(code string, start string, end string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{
+			"name":          code,
+			"course_code":   code,
+			"start_time":    start,
+			"end_time":      end,
+			"capacity":      10,
+			"weekday":       "Wednesday",
+			"instructor_id": profile.ID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/classes", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := create("HIIT-1", "18:00", "19:00"); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := create("HIIT-2", "18:30", "19:30"); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for double-booking, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/manager/instructors/%d/schedule", profile.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Schedule model.InstructorSchedule `json:"schedule"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Schedule.Courses) != 1 || len(resp.Schedule.Conflicts) != 0 {
+		t.Fatalf("expected one course and no conflicts, got %s", w.Body.String())
+	}
+}
+
+func TestManagerCreateClass_WithWeeklySlots(t *testing.T) {
+	setupManagerTestDBWithSessions(t)
+	seedRole(t, 3, "Manager")
+
+	r := routes.SetupRouter()
+	token := makeToken(t, 999, 3) // manager
+
+	create := func(slots []map[string]any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{
+			"name":        "HIIT",
+			"course_code": fmt.Sprintf("HIIT-%d", time.Now().UnixNano()),
+			"capacity":    12,
+			"slots":       slots,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/classes", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create([]map[string]any{
+		{"weekday": "Monday", "start_time": "07:00", "end_time": "07:45"},
+		{"weekday": "Wednesday", "start_time": "07:00", "end_time": "07:45"},
+		{"weekday": "Friday", "start_time": "07:00", "end_time": "07:45"},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Class model.Course `json:"class"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Class.Slots) != 3 || resp.Class.Weekday != "Monday" {
+		t.Fatalf("expected three slots mirrored from Monday, got %s", w.Body.String())
+	}
+
+	w = create([]map[string]any{
+		{"weekday": "Monday", "start_time": "07:00", "end_time": "07:45"},
+		{"weekday": "mon", "start_time": "18:00", "end_time": "18:45"},
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a repeated weekday, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestManagerMemberStrikes_ViewAndClear(t *testing.T) {
+	setupManagerTestDBWithSessions(t)
+	seedRole(t, 3, "Manager")
+	seedRole(t, 1, "Student")
+
+	student := model.User{Name: "Struck", Email: fmt.Sprintf("struck-%d@example.com", time.Now().UnixNano()), Password: "pass", RoleID: 1}
+	if err := db.DB.Create(&student).Error; err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		strike := model.MemberStrike{UserID: student.ID, CourseID: 1, Reason: model.StrikeReasonNoShow, OccurredAt: time.Now().AddDate(0, 0, -i)}
+		if err := db.DB.Create(&strike).Error; err != nil {
+			t.Fatalf("failed to seed strike: %v", err)
+		}
+	}
+
+	managerToken := makeToken(t, 999, 3)
+	r := routes.SetupRouter()
+	perform := func(method string, path string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := perform(http.MethodGet, fmt.Sprintf("/manager/users/%d/strikes", student.ID), makeToken(t, student.ID, 1))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a student, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = perform(http.MethodGet, "/auth/profile", makeToken(t, student.ID, 1))
+	var profile model.UserProfile
+	if err := json.Unmarshal(w.Body.Bytes(), &profile); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if w.Code != http.StatusOK || profile.Standing == nil || !profile.Standing.Blocked || profile.Standing.ActiveStrikes != 3 {
+		t.Fatalf("expected a suspended standing in the profile, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = perform(http.MethodDelete, fmt.Sprintf("/manager/users/%d/strikes", student.ID), managerToken)
+	var cleared struct {
+		Standing model.MemberStanding `json:"standing"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &cleared); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if w.Code != http.StatusOK || cleared.Standing.Blocked || cleared.Standing.ActiveStrikes != 0 {
+		t.Fatalf("expected strikes cleared, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = perform(http.MethodGet, fmt.Sprintf("/manager/users/%d/strikes", student.ID), managerToken)
+	var listed struct {
+		History []model.MemberStrike `json:"history"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if w.Code != http.StatusOK || len(listed.History) != 3 || listed.History[0].ClearedAt == nil {
+		t.Fatalf("expected the cleared strikes in the history, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestManagerMemberships_GrantAdjustAndView(t *testing.T) {
+	setupManagerTestDBWithSessions(t)
+	seedRole(t, 3, "Manager")
+	seedRole(t, 1, "Student")
+
+	student := model.User{Name: "Member", Email: fmt.Sprintf("member-%d@example.com", time.Now().UnixNano()), Password: "pass", RoleID: 1}
+	if err := db.DB.Create(&student).Error; err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+
+	managerToken := makeToken(t, 999, 3)
+	studentToken := makeToken(t, student.ID, 1)
+	r := routes.SetupRouter()
+
+	w := performJSONRequest(t, r, http.MethodPost, "/memberships/plans", studentToken,
+		map[string]any{"name": "Ten pack", "kind": "class_pack", "credits": 10})
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a student, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performJSONRequest(t, r, http.MethodPost, "/memberships/plans", managerToken,
+		map[string]any{"name": "Ten pack", "kind": "class_pack", "credits": 10, "price_cents": 15000})
+	var created struct {
+		Plan model.MembershipPlan `json:"plan"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if w.Code != http.StatusCreated || !created.Plan.Active {
+		t.Fatalf("expected plan created, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performJSONRequest(t, r, http.MethodPost, fmt.Sprintf("/manager/users/%d/subscriptions", student.ID), managerToken,
+		map[string]any{"plan_id": created.Plan.ID, "note": "paid at front desk"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected subscription granted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performJSONRequest(t, r, http.MethodPost, fmt.Sprintf("/manager/users/%d/credits", student.ID), managerToken,
+		map[string]any{"delta": -11, "note": "refund outside the app"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an overdraw, got %d: %s", w.Code, w.Body.String())
+	}
+	w = performJSONRequest(t, r, http.MethodPost, fmt.Sprintf("/manager/users/%d/credits", student.ID), managerToken,
+		map[string]any{"delta": -2, "note": "refund outside the app"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected adjustment recorded, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = performJSONRequest(t, r, http.MethodGet, "/memberships/me", studentToken, nil)
+	var mine struct {
+		Balance model.MemberBalance `json:"balance"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &mine); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if w.Code != http.StatusOK || mine.Balance.Credits != 8 || len(mine.Balance.Ledger) != 2 || len(mine.Balance.Subscriptions) != 1 {
+		t.Fatalf("expected 8 credits with two ledger entries, got %d: %s", w.Code, w.Body.String())
+	}
+	if mine.Balance.Ledger[1].Reason != model.CreditReasonGrant || mine.Balance.Ledger[1].Note != "paid at front desk" {
+		t.Fatalf("expected the grant to be audited, got %+v", mine.Balance.Ledger[1])
+	}
+
+	w = performJSONRequest(t, r, http.MethodGet, fmt.Sprintf("/manager/users/%d/balance", student.ID), managerToken, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected balance for a manager, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+	"my-course-backend/payments"
+	"my-course-backend/routes"
+	"my-course-backend/service"
+)
+
+func TestPaymentEndpoints_CheckoutWebhookAndRefund(t *testing.T) {
+	setupRouteTestDB(t)
+	provider := payments.NewFakeProvider("route-secret")
+	service.SetPaymentProvider(provider, "")
+	t.Cleanup(func() { service.SetPaymentProvider(nil, "") })
+
+	seedRouteRole(t, 1, "Student")
+	seedRouteRole(t, 3, "Manager")
+	student := seedRouteUser(t, 1, "secret123")
+	manager := seedRouteUser(t, 3, "secret123")
+	plan := model.MembershipPlan{Name: "Ten pack", Kind: model.PlanKindClassPack, Credits: 10, PriceCents: 12000, Active: true}
+	if err := db.DB.Create(&plan).Error; err != nil {
+		t.Fatalf("failed to seed plan: %v", err)
+	}
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	managerToken := issueRouteToken(t, manager.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodPost, "/payments/checkout", studentToken, map[string]uint{"plan_id": plan.ID})
+	var checkout struct {
+		Order model.PaymentOrder `json:"order"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &checkout); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if recorder.Code != http.StatusCreated || checkout.Order.Status != model.PaymentStatusPending {
+		t.Fatalf("expected a pending order, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	payload, header, err := provider.Pay(checkout.Order.ProviderIntentID)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	send := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/payments/webhooks/fake", bytes.NewReader(payload))
+		req.Header.Set(payments.SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := send("deadbeef"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad signature, got %d", code)
+	}
+	if code := send(header.Get(payments.SignatureHeader)); code != http.StatusOK {
+		t.Fatalf("expected 200 for a signed webhook, got %d", code)
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/payments/orders/%d", checkout.Order.ID), studentToken, nil)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &checkout); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if recorder.Code != http.StatusOK || checkout.Order.Status != model.PaymentStatusPaid {
+		t.Fatalf("expected the order paid, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	path := fmt.Sprintf("/payments/orders/%d/refund", checkout.Order.ID)
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, studentToken, nil); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a student refund, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, managerToken, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the manager refund to succeed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, managerToken, nil); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 refunding twice, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestFakePaymentRoute_OnlyInFakeModeAndForTheOwner(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	student := seedRouteUser(t, 1, "secret123")
+	other := seedRouteUser(t, 1, "secret123")
+	plan := model.MembershipPlan{Name: "Ten pack", Kind: model.PlanKindClassPack, Credits: 10, PriceCents: 12000, Active: true}
+	if err := db.DB.Create(&plan).Error; err != nil {
+		t.Fatalf("failed to seed plan: %v", err)
+	}
+	studentToken := issueRouteToken(t, student.Email, "secret123")
+	otherToken := issueRouteToken(t, other.Email, "secret123")
+
+	disabled := routes.SetupRouter()
+	recorder := performJSONRequest(t, disabled, http.MethodPost, "/payments/checkout", studentToken, map[string]uint{"plan_id": plan.ID})
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a payment provider, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = performJSONRequest(t, disabled, http.MethodPost, "/payments/fake/intents/fake_pi_1/pay", studentToken, nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected the simulate route to be absent, got %d", recorder.Code)
+	}
+
+	service.SetPaymentProvider(payments.NewFakeProvider("route-secret"), "")
+	t.Cleanup(func() { service.SetPaymentProvider(nil, "") })
+	router := routes.SetupRouter()
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/payments/checkout", studentToken, map[string]uint{"plan_id": plan.ID})
+	var checkout struct {
+		Order model.PaymentOrder `json:"order"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &checkout); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	path := checkout.Order.CheckoutURL
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, "", nil); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", recorder.Code)
+	}
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, otherToken, nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 paying someone else's order, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder = performJSONRequest(t, router, http.MethodPost, path, studentToken, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the owner to pay, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -3,6 +3,8 @@ package routes
 import (
 	// Import your API layer (Ensure module name matches go.mod)
 	"my-course-backend/api"
+	"my-course-backend/model"
+	"my-course-backend/service"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -18,41 +20,60 @@ func SetupRouter() *gin.Engine {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true // Warning: Allow all origins for development; restrict in production.
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", api.KioskKeyHeader}
 
 	// Apply CORS middleware globally
 	r.Use(cors.New(config))
 
-	// 2. Register Route Groups
+	// 2. Access levels. Every route is registered on exactly one of them; handlers read the
+	// caller from the context and no longer check tokens themselves.
+	authenticated := api.Authenticate()
+	members := api.RequireRoles(model.RoleStudent, model.RoleSuperManager, model.RoleManager)
+	managers := api.RequireRoles(model.RoleSuperManager, model.RoleManager)
+	superManagers := api.RequireRoles(model.RoleSuperManager)
+	instructors := api.RequireRoles(model.RoleInstructor)
+
+	// 3. Register Route Groups
 
 	// Auth Route Group
 	// Prefix: /auth
 	authRoutes := r.Group("/auth")
 	{
 		authRoutes.POST("/register", api.Register)
-		// CHANGED: SuperManager creates manager invite codes
-		authRoutes.POST("/manager/invite-codes", api.CreateManagerInviteCode)
 		authRoutes.POST("/manager/register", api.ManagerRegister)
 		authRoutes.POST("/login", api.Login)
+		authRoutes.POST("/refresh", api.RefreshToken)
+		authRoutes.POST("/logout", api.Logout)
+		authRoutes.POST("/password/forgot", api.ForgotPassword)
+		authRoutes.POST("/password/reset", api.ResetPassword)
+		authRoutes.POST("/email/verify", api.VerifyEmail)
 
 		// New Profile Endpoints
-		authRoutes.GET("/profile", api.GetProfile)
-		authRoutes.PUT("/profile", api.UpdateProfile)
+		profile := authRoutes.Group("", authenticated)
+		profile.GET("/profile", api.GetProfile)
+		profile.PUT("/profile", api.UpdateProfile)
+		profile.PUT("/password", api.ChangePassword)
+		profile.POST("/email/verification", api.ResendEmailVerification)
 
-		authRoutes.POST("/roles/assign", api.AssignUserRole)
+		// CHANGED: SuperManager creates manager invite codes
+		admin := authRoutes.Group("", superManagers)
+		admin.POST("/manager/invite-codes", api.CreateManagerInviteCode)
+		admin.POST("/roles/assign", api.AssignUserRole)
 	}
 	// User Route Group
 	// Prefix: /users
 	userRoutes := r.Group("/users")
 	{
 		// DELETE /users/:id (e.g., /users/1)
-		userRoutes.DELETE("/:id", api.DeleteUser)
+		userRoutes.DELETE("/:id", superManagers, api.DeleteUser)
+
+		self := userRoutes.Group("", authenticated)
 		// GET /users/:id/enrollments (authenticated user can only get their own enrolled courses)
-		userRoutes.GET("/:id/enrollments", api.GetUserEnrolledClasses)
+		self.GET("/:id/enrollments", api.GetUserEnrolledClasses)
 		// GET /users/:id/enrollment-summary?days=30 (authenticated user can only get their own summary)
 		//userRoutes.GET("/:id/enrollment-summary", api.GetUserEnrollmentSummary)
 		// GET /users/:id/enrollment-summary/{7days|1mon|3mon}
-		userRoutes.GET("/:id/analytics", api.GetUserAnalytics)
+		self.GET("/:id/analytics", api.GetUserAnalytics)
 	}
 
 	// Class Route Group
@@ -63,37 +84,174 @@ func SetupRouter() *gin.Engine {
 		classRoutes.GET("", api.ListClasses)
 		classRoutes.GET("/categories", api.ListCategories)
 		classRoutes.GET("/:id", api.GetClass)
-		// manager-only
-		classRoutes.GET("/:id/enrollments", api.ListClassEnrollments)
+		classRoutes.GET("/:id/sessions", api.ListClassSessions)
+		classRoutes.GET("/:id/instructors", api.ListCourseInstructors)
+
+		// any signed-in user
+		account := classRoutes.Group("", authenticated)
+		account.GET("/notifications", api.ListMyNotifications)
+		account.GET("/sessions/:session_id/check-in-code", api.GetMyCheckInCode)
 
 		// enrollment actions
-		classRoutes.POST("/register", api.RegisterClass)
-		classRoutes.POST("/drop", api.DropClass)
+		booking := classRoutes.Group("", members)
+		booking.POST("/register", api.RegisterClass)
+		booking.POST("/drop", api.DropClass)
+		booking.POST("/sessions/:session_id/register", api.RegisterSession)
+		booking.POST("/sessions/:session_id/drop", api.DropSession)
+
+		// waitlist actions (drop also leaves the waitlist)
+		booking.GET("/waitlist", api.ListMyWaitlist)
+		booking.POST("/waitlist", api.JoinWaitlist)
+
+		// recurring series bookings
+		booking.GET("/series", api.ListMyBookingSeries)
+		booking.POST("/series", api.CreateBookingSeries)
+		booking.POST("/series/:series_id/cancel", api.CancelBookingSeries)
 
 		// manager-only
-		classRoutes.POST("", api.ManagerCreateClass)
-		classRoutes.PUT("/:id", api.ManagerUpdateClass)
-		classRoutes.DELETE("/:id", api.ManagerDeleteClass)
+		manage := classRoutes.Group("", managers)
+		manage.POST("", api.ManagerCreateClass)
+		manage.PUT("/:id", api.ManagerUpdateClass)
+		manage.DELETE("/:id", api.ManagerDeleteClass)
+		manage.GET("/:id/enrollments", api.ListClassEnrollments)
+		manage.GET("/:id/waitlist", api.ListClassWaitlist)
+
+		// manager-only session changes
+		manage.POST("/sessions/:session_id/cancel", api.ManagerCancelSession)
+		manage.POST("/sessions/:session_id/reschedule", api.ManagerRescheduleSession)
+		manage.GET("/sessions/:session_id/changes", api.ListSessionChanges)
+
+		// manager-only instructor assignments
+		manage.PUT("/:id/instructors", api.ManagerSetCourseInstructor)
+		manage.DELETE("/:id/instructors/:instructor_id", api.ManagerRemoveCourseInstructor)
+		manage.PUT("/sessions/:session_id/substitute", api.ManagerSetSessionSubstitute)
+		manage.DELETE("/sessions/:session_id/substitute", api.ManagerClearSessionSubstitute)
+		manage.PUT("/sessions/:session_id/room", api.ManagerSetSessionRoom)
+	}
+
+	// Rooms: public listing, manager-only changes
+	roomRoutes := r.Group("/rooms")
+	{
+		roomRoutes.GET("", api.ListRooms)
+		roomRoutes.GET("/:id", api.GetRoom)
+
+		manage := roomRoutes.Group("", managers)
+		manage.POST("", api.ManagerCreateRoom)
+		manage.PUT("/:id", api.ManagerUpdateRoom)
+		manage.DELETE("/:id", api.ManagerDeleteRoom)
+	}
+
+	// Blackout calendar: public listing, manager-only changes
+	blackoutRoutes := r.Group("/blackouts")
+	{
+		blackoutRoutes.GET("", api.ListBlackoutDates)
+
+		manage := blackoutRoutes.Group("", managers)
+		manage.POST("", api.ManagerCreateBlackoutDate)
+		manage.PUT("/:id", api.ManagerUpdateBlackoutDate)
+		manage.DELETE("/:id", api.ManagerDeleteBlackoutDate)
+	}
+
+	// Booking policies: public listing, manager-only changes
+	policyRoutes := r.Group("/booking-policies")
+	{
+		policyRoutes.GET("", api.ListBookingPolicies)
+		policyRoutes.GET("/:id", api.GetBookingPolicy)
+
+		manage := policyRoutes.Group("", managers)
+		manage.POST("", api.ManagerCreateBookingPolicy)
+		manage.PUT("/:id", api.ManagerUpdateBookingPolicy)
+		manage.DELETE("/:id", api.ManagerDeleteBookingPolicy)
+	}
+
+	// Memberships: public plan listing, manager-only plan changes
+	membershipRoutes := r.Group("/memberships")
+	{
+		membershipRoutes.GET("/plans", api.ListMembershipPlans)
+		membershipRoutes.GET("/me", authenticated, api.GetMyMembership)
+
+		manage := membershipRoutes.Group("", managers)
+		manage.POST("/plans", api.ManagerCreateMembershipPlan)
+		manage.PUT("/plans/:id", api.ManagerUpdateMembershipPlan)
 	}
 
-	instructorRoutes := r.Group("/instructor")
+	// Payments: member checkout, manager refunds, provider webhooks
+	paymentRoutes := r.Group("/payments")
 	{
+		// verified by the provider's signature, not a token
+		paymentRoutes.POST("/webhooks/:provider", api.PaymentWebhook)
+
+		account := paymentRoutes.Group("", authenticated)
+		account.POST("/checkout", api.CreateCheckout)
+		account.GET("/orders", api.ListMyPaymentOrders)
+		account.GET("/orders/:id", api.GetMyPaymentOrder)
+		if service.FakePaymentsEnabled() {
+			// stands in for the fake provider's checkout page; dev only
+			account.POST("/fake/intents/:intent_id/pay", api.SimulateFakePayment)
+		}
+
+		paymentRoutes.POST("/orders/:id/refund", managers, api.ManagerRefundPaymentOrder)
+	}
+
+	// Public keys for verifying FitFlow access tokens
+	r.GET("/.well-known/jwks.json", api.GetJWKS)
+
+	// Check-in: kiosks (X-Kiosk-Key), instructors and managers scan member codes
+	r.POST("/check-in", api.RequireKioskOrRoles(model.RoleSuperManager, model.RoleManager, model.RoleInstructor), api.CheckIn)
+
+	// Public instructor directory
+	instructorDirectory := r.Group("/instructors")
+	{
+		instructorDirectory.GET("", api.ListInstructors)
+		instructorDirectory.GET("/:id", api.GetInstructor)
+	}
+
+	instructorRoutes := r.Group("/instructor", instructors)
+	{
+		instructorRoutes.GET("/profile", api.GetMyInstructorProfile)
+		instructorRoutes.PATCH("/profile", api.UpdateMyInstructorProfile)
+		instructorRoutes.GET("/availability", api.GetMyAvailability)
+		instructorRoutes.PUT("/availability", api.SetMyAvailability)
 		instructorRoutes.GET("/courses", api.InstructorListCourses)
 		instructorRoutes.GET("/courses/:id/enrollments", api.InstructorListCourseEnrollments)
 		instructorRoutes.POST("/courses/:id/enrollments", api.InstructorAddEnrollment)
 		instructorRoutes.PATCH("/courses/:id/enrollments", api.InstructorUpdateEnrollmentStatus)
+		instructorRoutes.GET("/courses/:id/waitlist", api.InstructorListCourseWaitlist)
+		instructorRoutes.GET("/courses/:id/sessions", api.InstructorListCourseSessions)
+		instructorRoutes.POST("/sessions/:session_id/cancel", api.InstructorCancelSession)
+		instructorRoutes.POST("/sessions/:session_id/reschedule", api.InstructorRescheduleSession)
+		instructorRoutes.PUT("/sessions/:session_id/attendance", api.InstructorMarkSessionAttendance)
+		instructorRoutes.GET("/sessions/:session_id/roster", api.InstructorGetSessionRoster)
 	}
 
-		// ✅ Manager Route Group
+	// ✅ Manager Route Group
 	// Prefix: /manager
-	managerRoutes := r.Group("/manager")
+	managerRoutes := r.Group("/manager", managers)
 	{
 		managerRoutes.GET("/users", api.ManagerListUsers)
 		managerRoutes.GET("/users/:id/enrollments", api.ManagerListUserEnrollments)
 		managerRoutes.POST("/users/:id/enrollments", api.ManagerAddUserEnrollment)
 		managerRoutes.DELETE("/users/:id/enrollments/:course_id", api.ManagerDeleteUserEnrollment)
+		managerRoutes.GET("/users/:id/strikes", api.ManagerListMemberStrikes)
+		managerRoutes.DELETE("/users/:id/strikes", api.ManagerClearMemberStrikes)
+		managerRoutes.DELETE("/users/:id/strikes/:strike_id", api.ManagerClearMemberStrike)
+		managerRoutes.GET("/users/:id/balance", api.ManagerGetMemberBalance)
+		managerRoutes.POST("/users/:id/subscriptions", api.ManagerGrantSubscription)
+		managerRoutes.DELETE("/users/:id/subscriptions/:subscription_id", api.ManagerCancelSubscription)
+		managerRoutes.POST("/users/:id/credits", api.ManagerAdjustCredits)
+		managerRoutes.GET("/instructors/:id/schedule", api.ManagerGetInstructorSchedule)
+		managerRoutes.PUT("/instructors/:id/availability", api.ManagerSetInstructorAvailability)
 	}
 
+	// Admin Route Group (SuperManager only)
+	// Prefix: /admin
+	adminRoutes := r.Group("/admin", superManagers)
+	{
+		adminRoutes.GET("/scheduler", api.GetSchedulerStatus)
+		adminRoutes.POST("/scheduler/start", api.StartScheduler)
+		adminRoutes.POST("/scheduler/stop", api.StopScheduler)
+		adminRoutes.POST("/scheduler/run", api.RunScheduler)
+	}
 
 	return r
 }
@@ -12,6 +12,7 @@ import (
 	"my-course-backend/db"
 	"my-course-backend/model"
 	"my-course-backend/routes"
+	"my-course-backend/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
@@ -38,14 +39,20 @@ func setupInviteRouteTestDB(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 		&model.ManagerInviteCode{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
 	db.DB = testDB
+	service.SetMembershipRequired(false)
 }
 
 func seedSuperManagerUser(t *testing.T) model.User {
@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"my-course-backend/model"
+	"my-course-backend/routes"
+)
+
+func TestJoinWaitlistEndpoint_CreatedWithPosition(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	seated := seedRouteUser(t, 1, "secret123")
+	user := seedRouteUser(t, 1, "secret123")
+	course := seedRouteCourse(t, "Yoga", 1, "Wellness")
+	seedRouteEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+	token := issueRouteToken(t, user.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodPost, "/classes/register", token, map[string]uint{"course_id": course.ID})
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/classes/waitlist", token, map[string]uint{"course_id": course.ID})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		WaitlistPosition int `json:"waitlist_position"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.WaitlistPosition != 1 {
+		t.Fatalf("expected waitlist position 1, got %d", response.WaitlistPosition)
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, "/classes/waitlist", token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var listResponse struct {
+		Waitlist []model.Enrollment `json:"waitlist"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &listResponse); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(listResponse.Waitlist) != 1 || listResponse.Waitlist[0].CourseID != course.ID {
+		t.Fatalf("expected 1 waitlist entry for course %d, got %+v", course.ID, listResponse.Waitlist)
+	}
+}
+
+func TestJoinWaitlistEndpoint_ConflictWhenNotFull(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	user := seedRouteUser(t, 1, "secret123")
+	course := seedRouteCourse(t, "Spin", 3, "Cardio")
+	token := issueRouteToken(t, user.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodPost, "/classes/waitlist", token, map[string]uint{"course_id": course.ID})
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestJoinWaitlistEndpoint_BySession(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	user := seedRouteUser(t, 1, "secret123")
+	token := issueRouteToken(t, user.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodPost, "/classes/waitlist", token, map[string]uint{})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without a class or session, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodPost, "/classes/waitlist", token, map[string]uint{"session_id": 9999})
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for an unknown session, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestListClassWaitlistEndpoint_ManagerOnly(t *testing.T) {
+	setupRouteTestDB(t)
+	seedRouteRole(t, 1, "Student")
+	seated := seedRouteUser(t, 1, "secret123")
+	user := seedRouteUser(t, 1, "secret123")
+	course := seedRouteCourse(t, "HIIT", 1, "Cardio")
+	seedRouteEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+	studentToken := issueRouteToken(t, user.Email, "secret123")
+	router := routes.SetupRouter()
+
+	recorder := performJSONRequest(t, router, http.MethodPost, "/classes/waitlist", studentToken, map[string]uint{"course_id": course.ID})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	path := fmt.Sprintf("/classes/%d/waitlist", course.ID)
+	recorder = performJSONRequest(t, router, http.MethodGet, path, studentToken, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = performJSONRequest(t, router, http.MethodGet, path, makeToken(t, 999, 3), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d with body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		Waitlist []model.Enrollment `json:"waitlist"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Waitlist) != 1 || response.Waitlist[0].UserID != user.ID {
+		t.Fatalf("expected waitlist with user %d, got %+v", user.ID, response.Waitlist)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/mailer"
+	"my-course-backend/model"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountSettings configures password reset and email verification.
+type AccountSettings struct {
+	// AppURL is the frontend address the links in account emails point to.
+	AppURL string
+	// PasswordResetTTL and EmailVerificationTTL are how long a mailed token can be used.
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	// RequireVerifiedEmail refuses logins until the email address is verified.
+	RequireVerifiedEmail bool
+}
+
+// DefaultAccountSettings link to the dev frontend; reset links last an hour and verification links two days.
+var DefaultAccountSettings = AccountSettings{
+	AppURL:               "http://localhost:5173",
+	PasswordResetTTL:     time.Hour,
+	EmailVerificationTTL: 48 * time.Hour,
+}
+
+var (
+	accountSettings               = DefaultAccountSettings
+	accountMailer   mailer.Mailer = &mailer.LogMailer{}
+)
+
+// SetAccountSettings replaces the password reset and email verification settings.
+func SetAccountSettings(settings AccountSettings) {
+	accountSettings = settings
+}
+
+// SetMailer replaces the mailer account emails are sent with.
+func SetMailer(m mailer.Mailer) {
+	accountMailer = m
+}
+
+// RequestPasswordReset mails a reset link to the account with this email. Unknown addresses and mail
+// failures are only logged, so the response never reveals whether an account exists.
+func RequestPasswordReset(email string) error {
+	user, err := dao.GetUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := issueAccountToken(user.ID, model.AccountTokenPasswordReset, accountSettings.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your FitFlow password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your FitFlow account. "+
+			"Open this link within %s to choose a new one:\n\n%s\n\nIf it wasn't you, you can ignore this email.\n",
+			user.Name, accountSettings.PasswordResetTTL, accountLink("/reset-password", token)),
+	}
+	if err := accountMailer.Send(msg); err != nil {
+		log.Printf("failed to send password reset email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and ends every session of the user.
+func ResetPassword(token string, newPassword string) error {
+	now := time.Now()
+	accountToken, err := usableAccountToken(model.AccountTokenPasswordReset, token, now)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	reset, err := dao.ResetPasswordWithToken(accountToken.ID, accountToken.UserID, string(hash), now)
+	if err != nil {
+		return err
+	}
+	if !reset {
+		return errors.New("invalid or expired token")
+	}
+	return RevokeUserSessions(accountToken.UserID, model.RefreshRevokedPassword)
+}
+
+// ChangePassword replaces the user's password after checking the current one. Every session of the user
+// ends, and the caller gets a new session so they stay signed in on this device.
+func ChangePassword(userID uint, currentPassword string, newPassword string) (*model.AuthTokens, error) {
+	user, err := dao.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return nil, errors.New("current password is incorrect")
+	}
+	if currentPassword == newPassword {
+		return nil, errors.New("new password must be different")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	if err := dao.UpdateUserPassword(userID, string(hash)); err != nil {
+		return nil, err
+	}
+	if err := RevokeUserSessions(userID, model.RefreshRevokedPassword); err != nil {
+		return nil, err
+	}
+	return StartSession(user)
+}
+
+// SendEmailVerification mails a verification link to the user's address.
+func SendEmailVerification(userID uint) error {
+	user, err := dao.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email already verified")
+	}
+
+	token, err := issueAccountToken(user.ID, model.AccountTokenEmailVerification, accountSettings.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your FitFlow email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm this is your email address by opening this link within %s:\n\n%s\n",
+			user.Name, accountSettings.EmailVerificationTTL, accountLink("/verify-email", token)),
+	}
+	if err := accountMailer.Send(msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// VerifyEmail marks the address of a verification token's user as verified.
+func VerifyEmail(token string) error {
+	now := time.Now()
+	accountToken, err := usableAccountToken(model.AccountTokenEmailVerification, token, now)
+	if err != nil {
+		return err
+	}
+
+	verified, err := dao.VerifyEmailWithToken(accountToken.ID, accountToken.UserID, now)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errors.New("invalid or expired token")
+	}
+	return nil
+}
+
+// issueAccountToken stores a new token of a purpose for the user and returns its value.
+func issueAccountToken(userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if err := dao.CreateAccountToken(&model.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// usableAccountToken looks up an unused, unexpired token of a purpose. Every failure reads the same.
+func usableAccountToken(purpose string, token string, now time.Time) (*model.AccountToken, error) {
+	accountToken, err := dao.GetAccountTokenByHash(purpose, hashOpaqueToken(token))
+	if err != nil || accountToken.UsedAt != nil || !now.Before(accountToken.ExpiresAt) {
+		return nil, errors.New("invalid or expired token")
+	}
+	return accountToken, nil
+}
+
+func accountLink(path string, token string) string {
+	return strings.TrimRight(accountSettings.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/mailer"
+	"my-course-backend/model"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type recordingMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func useRecordingMailer(t *testing.T) *recordingMailer {
+	t.Helper()
+	recorder := &recordingMailer{}
+	SetMailer(recorder)
+	t.Cleanup(func() { SetMailer(&mailer.LogMailer{}) })
+	return recorder
+}
+
+// mailedToken returns the token in the link of the last message sent.
+func mailedToken(t *testing.T, recorder *recordingMailer) string {
+	t.Helper()
+	if len(recorder.sent) == 0 {
+		t.Fatalf("expected an email to be sent")
+	}
+	body := recorder.sent[len(recorder.sent)-1].Body
+	_, rest, found := strings.Cut(body, "?token=")
+	if !found {
+		t.Fatalf("expected a token link in %q", body)
+	}
+	return strings.Fields(rest)[0]
+}
+
+func seedUserWithPassword(t *testing.T, password string) model.User {
+	t.Helper()
+
+	user := seedRoleAndUser(t, model.RoleStudent)
+	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err := db.DB.Model(&user).Update("password", string(hash)).Error; err != nil {
+		t.Fatalf("failed to set password: %v", err)
+	}
+	user.Password = string(hash)
+	return user
+}
+
+func TestPasswordReset_IsSingleUseAndEndsSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+	recorder := useRecordingMailer(t)
+	user := seedUserWithPassword(t, "old-password")
+	session, _ := StartSession(&user)
+
+	if err := RequestPasswordReset("nobody@example.com"); err != nil || len(recorder.sent) != 0 {
+		t.Fatalf("expected an unknown email to succeed silently, got %v and %d emails", err, len(recorder.sent))
+	}
+
+	if err := RequestPasswordReset(user.Email); err != nil {
+		t.Fatalf("failed to request reset: %v", err)
+	}
+	stale := mailedToken(t, recorder)
+	if err := RequestPasswordReset(user.Email); err != nil {
+		t.Fatalf("failed to request reset: %v", err)
+	}
+	token := mailedToken(t, recorder)
+	if recorder.sent[1].To != user.Email {
+		t.Fatalf("expected the email to go to %s, got %s", user.Email, recorder.sent[1].To)
+	}
+
+	if err := ResetPassword(stale, "new-password"); err == nil {
+		t.Fatalf("expected an earlier reset token to be invalidated by a newer one")
+	}
+	if err := ResetPassword(token, "new-password"); err != nil {
+		t.Fatalf("failed to reset password: %v", err)
+	}
+	if err := ResetPassword(token, "another-password"); err == nil || err.Error() != "invalid or expired token" {
+		t.Fatalf("expected the token to be single-use, got %v", err)
+	}
+
+	if _, _, err := LoginUserWithRole(model.LoginInput{Email: user.Email, Password: "new-password"}); err != nil {
+		t.Fatalf("expected the new password to work, got %v", err)
+	}
+	if _, err := RefreshSession(session.RefreshToken); err == nil {
+		t.Fatalf("expected the reset to end existing sessions")
+	}
+
+	var stored model.AccountToken
+	db.DB.Where("user_id = ?", user.ID).Last(&stored)
+	if stored.TokenHash == token || stored.UsedAt == nil {
+		t.Fatalf("expected a used, hashed token, got %+v", stored)
+	}
+}
+
+func TestPasswordReset_RejectsExpiredTokens(t *testing.T) {
+	setupClassServiceTestDB(t)
+	recorder := useRecordingMailer(t)
+	user := seedUserWithPassword(t, "old-password")
+
+	RequestPasswordReset(user.Email)
+	db.DB.Model(&model.AccountToken{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	if err := ResetPassword(mailedToken(t, recorder), "new-password"); err == nil {
+		t.Fatalf("expected an expired token to be rejected")
+	}
+	// A reset token is not a verification token.
+	RequestPasswordReset(user.Email)
+	if err := VerifyEmail(mailedToken(t, recorder)); err == nil {
+		t.Fatalf("expected a reset token to be rejected for email verification")
+	}
+}
+
+func TestEmailVerification_OnRegistration(t *testing.T) {
+	setupClassServiceTestDB(t)
+	recorder := useRecordingMailer(t)
+	db.DB.Create(&model.Role{ID: model.RoleStudent, RoleName: "Student"})
+
+	input := model.RegisterInput{Name: "New Member", Email: "new.member@example.com", Password: "secret123"}
+	if err := RegisterUser(input); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	token := mailedToken(t, recorder)
+
+	SetAccountSettings(AccountSettings{RequireVerifiedEmail: true, EmailVerificationTTL: time.Hour})
+	t.Cleanup(func() { SetAccountSettings(DefaultAccountSettings) })
+	login := model.LoginInput{Email: input.Email, Password: input.Password}
+	if _, _, err := LoginUserWithRole(login); err == nil || err.Error() != "email not verified" {
+		t.Fatalf("expected an unverified login to be refused, got %v", err)
+	}
+
+	if err := VerifyEmail(token); err != nil {
+		t.Fatalf("failed to verify email: %v", err)
+	}
+	if _, _, err := LoginUserWithRole(login); err != nil {
+		t.Fatalf("expected the verified login to succeed, got %v", err)
+	}
+
+	var registered model.User
+	db.DB.Where("email = ?", input.Email).First(&registered)
+	if profile, err := GetUserProfile(registered.ID); err != nil || profile.EmailVerifiedAt == nil {
+		t.Fatalf("expected the profile to show the verified address, got %+v (err %v)", profile, err)
+	}
+	if err := SendEmailVerification(registered.ID); err == nil || err.Error() != "email already verified" {
+		t.Fatalf("expected a verified address not to be mailed again, got %v", err)
+	}
+
+	// Registration succeeds even when the verification email cannot be sent.
+	recorder.err = errors.New("smtp down")
+	if err := RegisterUser(model.RegisterInput{Name: "Other", Email: "other@example.com", Password: "secret123"}); err != nil {
+		t.Fatalf("expected registration to survive a mail failure, got %v", err)
+	}
+}
+
+func TestChangePassword_ChecksCurrentPassword(t *testing.T) {
+	setupClassServiceTestDB(t)
+	user := seedUserWithPassword(t, "old-password")
+	other, _ := StartSession(&user)
+
+	if _, err := ChangePassword(user.ID, "wrong-password", "new-password"); err == nil || err.Error() != "current password is incorrect" {
+		t.Fatalf("expected a wrong current password to be rejected, got %v", err)
+	}
+	if _, err := ChangePassword(user.ID, "old-password", "old-password"); err == nil {
+		t.Fatalf("expected reusing the current password to be rejected")
+	}
+
+	tokens, err := ChangePassword(user.ID, "old-password", "new-password")
+	if err != nil {
+		t.Fatalf("failed to change password: %v", err)
+	}
+	if _, err := RefreshSession(other.RefreshToken); err == nil {
+		t.Fatalf("expected other sessions to end")
+	}
+	if _, err := RefreshSession(tokens.RefreshToken); err != nil {
+		t.Fatalf("expected the new session to work, got %v", err)
+	}
+	if _, _, err := LoginUserWithRole(model.LoginInput{Email: user.Email, Password: "new-password"}); err != nil {
+		t.Fatalf("expected the new password to work, got %v", err)
+	}
+}
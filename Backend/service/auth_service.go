@@ -2,16 +2,14 @@ package service
 
 import (
 	"errors"
+	"log"
 	"my-course-backend/dao"
 	"my-course-backend/model"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte("my_super_secret_key_2026")
-
 func RegisterUser(input model.RegisterInput) error {
 	if dao.CheckEmailExist(input.Email) {
 		return errors.New("email already exists")
@@ -34,7 +32,15 @@ func RegisterUser(input model.RegisterInput) error {
 		RoleID:   roleID,
 	}
 
-	return dao.CreateUser(&user)
+	if err := dao.CreateUser(&user); err != nil {
+		return err
+	}
+
+	// A lost verification email doesn't fail the registration; the member can ask for another one.
+	if err := SendEmailVerification(user.ID); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", user.ID, err)
+	}
+	return nil
 }
 
 func LoginUser(input model.LoginInput) (string, error) {
@@ -48,80 +54,29 @@ func LoginUser(input model.LoginInput) (string, error) {
 		return "", errors.New("invalid password")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":      user.ID,
-		"email":   user.Email,
-		"role_id": user.RoleID,
-		"exp":     time.Now().Add(time.Hour * 48).Unix(),
-	})
-
-	tokenString, err := token.SignedString(jwtSecret)
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
+	return IssueAccessToken(user.ID, user.Email, user.RoleID)
 }
 
-func ExtractUserIDFromToken(tokenString string) (uint, error) {
-	claims := jwt.MapClaims{}
-	parsed, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-	if err != nil || !parsed.Valid {
-		return 0, errors.New("invalid token")
-	}
-
-	idValue, ok := claims["id"]
-	if !ok {
-		return 0, errors.New("invalid token")
-	}
-
-	switch typed := idValue.(type) {
-	case float64:
-		return uint(typed), nil
-	case int:
-		return uint(typed), nil
-	case uint:
-		return typed, nil
-	default:
-		return 0, errors.New("invalid token")
+func RemoveUser(id uint) error {
+	// The rows cascade with the user; revoking first also covers databases without the foreign key.
+	if err := RevokeUserSessions(id, model.RefreshRevokedUserDeleted); err != nil {
+		return err
 	}
+	return dao.DeleteUserByID(id)
 }
 
-
-func GetRoleIDFromToken(tokenString string) (uint, error) {
-	claims := jwt.MapClaims{}
-	parsed, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-	if err != nil || !parsed.Valid {
-		return 0, errors.New("invalid token")
-	}
-
-	roleValue, ok := claims["role_id"]
-	if !ok {
-		return 0, errors.New("invalid token")
+func GetUserProfile(id uint) (*model.UserProfile, error) {
+	profile, err := dao.GetUserProfileByID(id)
+	if err != nil {
+		return nil, err
 	}
 
-	switch typed := roleValue.(type) {
-	case float64:
-		return uint(typed), nil
-	case int:
-		return uint(typed), nil
-	case uint:
-		return typed, nil
-	default:
-		return 0, errors.New("invalid token")
+	standing, err := memberStanding(id, time.Now())
+	if err != nil {
+		return nil, err
 	}
-}
-
-func RemoveUser(id uint) error {
-	return dao.DeleteUserByID(id)
-}
-
-func GetUserProfile(id uint) (*model.UserProfile, error) {
-	return dao.GetUserProfileByID(id)
+	profile.Standing = standing
+	return profile, nil
 }
 
 // New: Patch update with undefined vs null distinction.
@@ -129,30 +84,28 @@ func UpdateUserProfilePatch(id uint, patch model.UserProfilePatch) error {
 	return dao.UpdateUserProfilePatch(id, patch)
 }
 
-func LoginUserWithRole(input model.LoginInput) (string, uint, error) {
+// LoginUserWithRole checks the credentials and starts a new session.
+func LoginUserWithRole(input model.LoginInput) (*model.AuthTokens, uint, error) {
 	user, err := dao.GetUserByEmail(input.Email)
 	if err != nil {
-		return "", 0, errors.New("user not found")
+		return nil, 0, errors.New("user not found")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
 	if err != nil {
-		return "", 0, errors.New("invalid password")
+		return nil, 0, errors.New("invalid password")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":      user.ID,
-		"email":   user.Email,
-		"role_id": user.RoleID,
-		"exp":     time.Now().Add(time.Hour * 70).Unix(),
-	})
+	if accountSettings.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return nil, 0, errors.New("email not verified")
+	}
 
-	tokenString, err := token.SignedString(jwtSecret)
+	tokens, err := StartSession(user)
 	if err != nil {
-		return "", 0, err
+		return nil, 0, err
 	}
 
-	return tokenString, user.RoleID, nil
+	return tokens, user.RoleID, nil
 }
 
 func AssignUserRole(userID uint, roleName string) error {
@@ -161,9 +114,25 @@ func AssignUserRole(userID uint, roleName string) error {
 		return errors.New("role not found")
 	}
 
-	if _, err := dao.GetUserByID(userID); err != nil {
+	user, err := dao.GetUserByID(userID)
+	if err != nil {
 		return errors.New("user not found")
 	}
 
-	return dao.UpdateUserRoleByID(userID, roleID)
+	if err := dao.UpdateUserRoleByID(userID, roleID); err != nil {
+		return err
+	}
+
+	// Refresh tokens would keep minting access tokens with the old role.
+	if roleID != user.RoleID {
+		if err := RevokeUserSessions(userID, model.RefreshRevokedRoleChanged); err != nil {
+			return err
+		}
+	}
+
+	// Instructors need a profile before courses can be assigned to them.
+	if roleID == model.RoleInstructor {
+		return dao.EnsureInstructorProfile(userID, user.Name)
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// ScheduleConflictError rejects an instructor assignment that double-books the instructor
+// or falls outside their availability.
+type ScheduleConflictError struct {
+	Conflicts []model.InstructorScheduleConflict
+}
+
+func (e *ScheduleConflictError) Error() string { return "instructor schedule conflict" }
+
+// GetInstructorAvailability returns the signed-in instructor's availability windows.
+func GetInstructorAvailability(instructorID uint) ([]model.InstructorAvailability, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	return dao.ListInstructorAvailability(profileID)
+}
+
+// SetInstructorAvailability replaces the signed-in instructor's availability windows.
+func SetInstructorAvailability(instructorID uint, input model.InstructorAvailabilityInput) ([]model.InstructorAvailability, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	return replaceAvailability(profileID, input)
+}
+
+// ManagerSetInstructorAvailability replaces the availability windows of an instructor profile.
+func ManagerSetInstructorAvailability(profileID uint, input model.InstructorAvailabilityInput) ([]model.InstructorAvailability, error) {
+	if _, err := dao.GetInstructorByID(profileID); err != nil {
+		return nil, errors.New("instructor not found")
+	}
+	return replaceAvailability(profileID, input)
+}
+
+// GetInstructorSchedule returns an instructor's availability, courses, upcoming sessions and current conflicts.
+func GetInstructorSchedule(profileID uint) (*model.InstructorSchedule, error) {
+	instructor, err := dao.GetInstructorByID(profileID)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	windows, err := dao.ListInstructorAvailability(profileID)
+	if err != nil {
+		return nil, err
+	}
+	courses, err := dao.ListCoursesByInstructorID(profileID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := dao.ListUpcomingInstructorSessions(profileID, time.Now().Add(InstructorDirectoryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare each course only with the ones after it so every overlap is reported once.
+	conflicts := []model.InstructorScheduleConflict{}
+	for i := range courses {
+		conflicts = append(conflicts, slotConflicts(instructor, &courses[i], courses[i+1:], windows)...)
+	}
+
+	return &model.InstructorSchedule{
+		Instructor:   *instructor,
+		Availability: windows,
+		Courses:      courses,
+		Sessions:     sessions,
+		Conflicts:    conflicts,
+	}, nil
+}
+
+func replaceAvailability(profileID uint, input model.InstructorAvailabilityInput) ([]model.InstructorAvailability, error) {
+	windows := make([]model.InstructorAvailability, 0, len(input.Windows))
+	for _, w := range input.Windows {
+		if _, ok := validWeekdays[normalizeWeekdayForGeneration(w.Weekday)]; !ok {
+			return nil, errors.New("invalid weekday")
+		}
+		start, err := model.ParseTimeOnly(w.StartTime)
+		if err != nil {
+			return nil, errors.New("invalid start_time, expected HH:MM or HH:MM:SS")
+		}
+		end, err := model.ParseTimeOnly(w.EndTime)
+		if err != nil {
+			return nil, errors.New("invalid end_time, expected HH:MM or HH:MM:SS")
+		}
+		if toMinutes(end) <= toMinutes(start) {
+			return nil, errors.New("end_time must be after start_time")
+		}
+		windows = append(windows, model.InstructorAvailability{
+			Weekday:   w.Weekday,
+			StartTime: start,
+			EndTime:   end,
+		})
+	}
+
+	if err := dao.ReplaceInstructorAvailability(profileID, windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+var validWeekdays = map[string]struct{}{
+	"mon": {}, "tue": {}, "wed": {}, "thu": {}, "fri": {}, "sat": {}, "sun": {},
+}
+
+// checkInstructorConflicts validates the course's weekly slots against each instructor's other courses and availability.
+// Conflicts are returned as a ScheduleConflictError unless allowed, in which case they become course warnings.
+func checkInstructorConflicts(course *model.Course, instructorIDs []uint, allow bool) error {
+	var conflicts []model.InstructorScheduleConflict
+	for _, id := range instructorIDs {
+		found, err := findInstructorConflicts(id, course)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, found...)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if !allow {
+		return &ScheduleConflictError{Conflicts: conflicts}
+	}
+	course.ScheduleWarnings = conflicts
+	return nil
+}
+
+// findInstructorConflicts returns the conflicts of teaching the course's weekly slots for one instructor.
+func findInstructorConflicts(instructorID uint, course *model.Course) ([]model.InstructorScheduleConflict, error) {
+	if !hasCourseSchedule(course) {
+		return nil, nil
+	}
+
+	instructor, err := dao.GetInstructorByID(instructorID)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+	courses, err := dao.ListCoursesByInstructorID(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := dao.ListInstructorAvailability(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	return slotConflicts(instructor, course, courses, windows), nil
+}
+
+// slotConflicts compares each weekly slot of a course with other courses of the instructor and their availability windows.
+// Courses without a weekly slot never produce sessions and are ignored.
+func slotConflicts(instructor *model.Instructor, course *model.Course, courses []model.Course, windows []model.InstructorAvailability) []model.InstructorScheduleConflict {
+	var conflicts []model.InstructorScheduleConflict
+	for _, slot := range courseSlots(course) {
+		for i := range courses {
+			other := &courses[i]
+			if other.ID == course.ID {
+				continue
+			}
+			if _, overlaps := overlappingSlot([]model.CourseSlot{slot}, courseSlots(other)); overlaps {
+				conflicts = append(conflicts, model.InstructorScheduleConflict{
+					InstructorID:   instructor.ID,
+					InstructorName: instructor.Name,
+					Reason:         model.ScheduleConflictOverlap,
+					CourseID:       other.ID,
+					CourseName:     other.CourseName,
+					Weekday:        slot.Weekday,
+					StartTime:      slot.StartTime,
+					EndTime:        slot.EndTime,
+				})
+			}
+		}
+
+		if len(windows) > 0 && !withinAvailability(slot, windows) {
+			conflicts = append(conflicts, model.InstructorScheduleConflict{
+				InstructorID:   instructor.ID,
+				InstructorName: instructor.Name,
+				Reason:         model.ScheduleConflictUnavailable,
+				Weekday:        slot.Weekday,
+				StartTime:      slot.StartTime,
+				EndTime:        slot.EndTime,
+			})
+		}
+	}
+	return conflicts
+}
+
+// withinAvailability reports whether the slot fits entirely inside one availability window.
+func withinAvailability(slot model.CourseSlot, windows []model.InstructorAvailability) bool {
+	for _, w := range windows {
+		if !isSameWeekday(w.Weekday, slot.Weekday) {
+			continue
+		}
+		if toMinutes(w.StartTime) <= toMinutes(slot.StartTime) && toMinutes(slot.EndTime) <= toMinutes(w.EndTime) {
+			return true
+		}
+	}
+	return false
+}
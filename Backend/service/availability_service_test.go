@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"my-course-backend/model"
+)
+
+func TestManagerCreateCourse_RejectsInstructorDoubleBooking(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	instructor := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, instructor)
+
+	input := CourseUpsertInput{
+		CourseName:   "Yoga",
+		CourseCode:   "YOGA-1",
+		StartTime:    "08:00",
+		EndTime:      "09:00",
+		Capacity:     10,
+		Weekday:      "Monday",
+		InstructorID: &profile.ID,
+	}
+	first, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("failed to create first course: %v", err)
+	}
+
+	input.CourseName, input.CourseCode = "Spin", "SPIN-1"
+	input.StartTime, input.EndTime = "08:30", "09:30"
+	input.Weekday = "mon"
+	_, err = ManagerCreateCourse(input)
+	var conflict *ScheduleConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected schedule conflict, got: %v", err)
+	}
+	if len(conflict.Conflicts) != 1 || conflict.Conflicts[0].CourseID != first.ID || conflict.Conflicts[0].Reason != model.ScheduleConflictOverlap {
+		t.Fatalf("unexpected conflicts: %+v", conflict.Conflicts)
+	}
+
+	input.AllowScheduleConflicts = true
+	second, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("expected course saved with warnings, got: %v", err)
+	}
+	if len(second.ScheduleWarnings) != 1 {
+		t.Fatalf("expected one schedule warning, got %+v", second.ScheduleWarnings)
+	}
+
+	schedule, err := GetInstructorSchedule(profile.ID)
+	if err != nil {
+		t.Fatalf("failed to load schedule: %v", err)
+	}
+	if len(schedule.Courses) != 2 || len(schedule.Conflicts) != 1 {
+		t.Fatalf("expected 2 courses and 1 conflict, got %+v", schedule)
+	}
+
+	// Moving the second course away from the first clears the overlap.
+	input.AllowScheduleConflicts = false
+	input.StartTime, input.EndTime = "09:00", "10:00"
+	if _, err := ManagerUpdateCourse(second.ID, input); err != nil {
+		t.Fatalf("expected non-overlapping update to succeed, got: %v", err)
+	}
+}
+
+func TestManagerCreateCourse_RespectsAvailability(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	instructor := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, instructor)
+
+	if _, err := SetInstructorAvailability(instructor.ID, model.InstructorAvailabilityInput{
+		Windows: []model.AvailabilityWindowInput{{Weekday: "Tuesday", StartTime: "07:00", EndTime: "12:00"}},
+	}); err != nil {
+		t.Fatalf("failed to set availability: %v", err)
+	}
+	if _, err := SetInstructorAvailability(instructor.ID, model.InstructorAvailabilityInput{
+		Windows: []model.AvailabilityWindowInput{{Weekday: "Someday", StartTime: "07:00", EndTime: "12:00"}},
+	}); err == nil || err.Error() != "invalid weekday" {
+		t.Fatalf("expected invalid weekday, got: %v", err)
+	}
+
+	input := CourseUpsertInput{
+		CourseName:   "Boxing",
+		CourseCode:   "BOX-1",
+		StartTime:    "11:30",
+		EndTime:      "12:30",
+		Capacity:     10,
+		Weekday:      "Tuesday",
+		InstructorID: &profile.ID,
+	}
+	_, err := ManagerCreateCourse(input)
+	var conflict *ScheduleConflictError
+	if !errors.As(err, &conflict) || conflict.Conflicts[0].Reason != model.ScheduleConflictUnavailable {
+		t.Fatalf("expected unavailable conflict, got: %v", err)
+	}
+
+	input.StartTime, input.EndTime = "10:00", "11:00"
+	if _, err := ManagerCreateCourse(input); err != nil {
+		t.Fatalf("expected course inside availability to succeed, got: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// ListBlackoutDates returns blackout dates on or after fromDate (YYYY-MM-DD), defaulting to today.
+func ListBlackoutDates(fromDate string) ([]model.BlackoutDate, error) {
+	fromDate = strings.TrimSpace(fromDate)
+	if fromDate == "" {
+		fromDate = model.FacilityToday()
+	} else if _, err := time.Parse("2006-01-02", fromDate); err != nil {
+		return nil, errors.New("invalid date, expected YYYY-MM-DD")
+	}
+	return dao.ListBlackoutDates(fromDate)
+}
+
+// ManagerCreateBlackoutDate adds a blackout date and cancels the sessions already scheduled on it.
+// It returns the number of canceled sessions.
+func ManagerCreateBlackoutDate(managerID uint, input model.BlackoutDateInput) (*model.BlackoutDate, int, error) {
+	blackout := &model.BlackoutDate{}
+	if err := applyBlackoutInput(blackout, input); err != nil {
+		return nil, 0, err
+	}
+	if err := dao.CreateBlackoutDate(blackout); err != nil {
+		return nil, 0, err
+	}
+
+	canceled, err := cancelBlackoutSessions(managerID, blackout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return blackout, canceled, nil
+}
+
+// ManagerUpdateBlackoutDate changes a blackout date or its reason and cancels the sessions scheduled on the new date.
+// Sessions already canceled for the previous date stay canceled.
+func ManagerUpdateBlackoutDate(managerID uint, id uint, input model.BlackoutDateInput) (*model.BlackoutDate, int, error) {
+	blackout, err := dao.GetBlackoutDateByID(id)
+	if err != nil {
+		return nil, 0, errors.New("blackout date not found")
+	}
+	if err := applyBlackoutInput(blackout, input); err != nil {
+		return nil, 0, err
+	}
+	if err := dao.UpdateBlackoutDate(blackout); err != nil {
+		return nil, 0, err
+	}
+
+	canceled, err := cancelBlackoutSessions(managerID, blackout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return blackout, canceled, nil
+}
+
+// ManagerDeleteBlackoutDate removes a blackout date. Sessions canceled because of it stay canceled;
+// sessions that were never generated for the date are created on the next generation run.
+func ManagerDeleteBlackoutDate(id uint) error {
+	if _, err := dao.GetBlackoutDateByID(id); err != nil {
+		return errors.New("blackout date not found")
+	}
+	return dao.DeleteBlackoutDateByID(id)
+}
+
+// applyBlackoutInput validates the input and copies it onto the blackout date.
+func applyBlackoutInput(blackout *model.BlackoutDate, input model.BlackoutDateInput) error {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(input.Date))
+	if err != nil {
+		return errors.New("invalid date, expected YYYY-MM-DD")
+	}
+	day := date.Format("2006-01-02")
+	if day < model.FacilityToday() {
+		return errors.New("blackout date is in the past")
+	}
+
+	taken, err := dao.CheckBlackoutDateTaken(day, blackout.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("blackout date already exists")
+	}
+
+	blackout.Date = day
+	blackout.Reason = strings.TrimSpace(input.Reason)
+	return nil
+}
+
+// cancelBlackoutSessions cancels every scheduled session on the blackout date, notifying booked members.
+func cancelBlackoutSessions(actorID uint, blackout *model.BlackoutDate) (int, error) {
+	sessions, err := dao.ListScheduledSessionsOnDate(blackout.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	reason := blackout.Reason
+	if reason == "" {
+		reason = "The gym is closed on this day."
+	}
+	for i := range sessions {
+		if _, err := cancelSession(actorID, &sessions[i], reason); err != nil {
+			return i, err
+		}
+	}
+	return len(sessions), nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func TestManagerCreateBlackoutDate_CancelsScheduledSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	manager := seedRoleAndUser(t, 3)
+	member := seedUserWithRole(t, 3)
+	course := seedCourse(t, "Yoga", 10, "Wellness")
+	session := seedSessionAt(t, course, time.Now().AddDate(0, 0, 5), 10)
+	seedEnrollmentForSession(t, member.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	blackout, canceled, err := ManagerCreateBlackoutDate(manager.ID, model.BlackoutDateInput{Date: session.SessionDate, Reason: "Public holiday"})
+	if err != nil {
+		t.Fatalf("failed to create blackout: %v", err)
+	}
+	if canceled != 1 {
+		t.Fatalf("expected 1 canceled session, got %d", canceled)
+	}
+
+	var stored model.ClassSession
+	db.DB.First(&stored, session.ID)
+	if stored.Status != "canceled" {
+		t.Fatalf("expected the session to be canceled, got %s", stored.Status)
+	}
+	if enrollment := loadEnrollment(t, member.ID, course.ID); enrollment.Status != model.EnrollmentStatusCanceled {
+		t.Fatalf("expected the enrollment to be canceled, got %s", enrollment.Status)
+	}
+	notifications, err := ListUserSessionNotifications(member.ID)
+	if err != nil || len(notifications) != 1 {
+		t.Fatalf("expected one notification, got %d (err %v)", len(notifications), err)
+	}
+
+	if _, _, err := ManagerCreateBlackoutDate(manager.ID, model.BlackoutDateInput{Date: blackout.Date}); err == nil || err.Error() != "blackout date already exists" {
+		t.Fatalf("expected duplicate blackout error, got: %v", err)
+	}
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if _, _, err := ManagerCreateBlackoutDate(manager.ID, model.BlackoutDateInput{Date: yesterday}); err == nil || err.Error() != "blackout date is in the past" {
+		t.Fatalf("expected past date error, got: %v", err)
+	}
+}
+
+func TestGenerateClassSessions_RespectsTermAndBlackouts(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	today := time.Now().UTC()
+	first := today.AddDate(0, 0, 3)
+	day := func(weeks int) string { return first.AddDate(0, 0, weeks*7).Format("2006-01-02") }
+
+	manager := seedRoleAndUser(t, 3)
+	if _, _, err := ManagerCreateBlackoutDate(manager.ID, model.BlackoutDateInput{Date: day(1)}); err != nil {
+		t.Fatalf("failed to create blackout: %v", err)
+	}
+
+	endsOn := day(3)
+	input := CourseUpsertInput{
+		CourseName: "Spin",
+		CourseCode: "SPIN-TERM",
+		StartTime:  "18:00",
+		EndTime:    "19:00",
+		Capacity:   10,
+		Weekday:    first.Weekday().String(),
+		EndsOn:     &endsOn,
+	}
+	course, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+
+	scheduledDates := func() []string {
+		var dates []string
+		db.DB.Model(&model.ClassSession{}).Distinct("session_date").Where("course_id = ? AND status = 'scheduled'", course.ID).
+			Order("session_date ASC").Pluck("session_date", &dates)
+		return dates
+	}
+	if got := scheduledDates(); len(got) != 3 || got[0] != day(0) || got[1] != day(2) || got[2] != day(3) {
+		t.Fatalf("expected sessions on %s, %s and %s only, got %v", day(0), day(2), day(3), got)
+	}
+
+	startsOn := day(2)
+	input.StartsOn = &startsOn
+	if _, err := ManagerUpdateCourse(course.ID, input); err != nil {
+		t.Fatalf("failed to update course: %v", err)
+	}
+	if got := scheduledDates(); len(got) != 2 || got[0] != day(2) {
+		t.Fatalf("expected the session before the new term to be canceled, got %v", got)
+	}
+
+	bad := "2020-01-01"
+	input.EndsOn = &bad
+	if _, err := ManagerUpdateCourse(course.ID, input); err == nil || err.Error() != "ends_on must not be before starts_on" {
+		t.Fatalf("expected term order error, got: %v", err)
+	}
+}
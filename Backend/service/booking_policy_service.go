@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// Booking policy error codes, returned to clients next to the error message.
+const (
+	BookingCodeNotOpen      = "booking_not_open"
+	BookingCodeClosed       = "booking_closed"
+	BookingCodeCancelCutoff = "cancel_cutoff_passed"
+	BookingCodeActiveLimit  = "active_booking_limit"
+	BookingCodeDailyLimit   = "daily_booking_limit"
+	BookingCodeSuspended    = "booking_suspended"
+)
+
+// DefaultBookingPolicy applies to courses without a policy of their own or of their category:
+// booking opens 25 hours before a session and closes when it starts, with no cutoff or limits.
+var DefaultBookingPolicy = model.BookingPolicy{Name: "default", OpensHoursBefore: 25}
+
+// BookingPolicyError is a booking or cancellation refused by a booking policy.
+type BookingPolicyError struct {
+	Code    string
+	Message string
+}
+
+func (e *BookingPolicyError) Error() string { return e.Message }
+
+// ListBookingPolicies returns all booking policies.
+func ListBookingPolicies() ([]model.BookingPolicy, error) {
+	return dao.ListBookingPolicies()
+}
+
+// GetBookingPolicy returns one booking policy.
+func GetBookingPolicy(id uint) (*model.BookingPolicy, error) {
+	policy, err := dao.GetBookingPolicyByID(id)
+	if err != nil {
+		return nil, errors.New("booking policy not found")
+	}
+	return policy, nil
+}
+
+// ManagerCreateBookingPolicy creates a booking policy with a unique name and category.
+func ManagerCreateBookingPolicy(input model.BookingPolicyInput) (*model.BookingPolicy, error) {
+	policy := &model.BookingPolicy{}
+	if err := applyBookingPolicyInput(policy, input); err != nil {
+		return nil, err
+	}
+	if err := dao.CreateBookingPolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ManagerUpdateBookingPolicy updates a booking policy.
+func ManagerUpdateBookingPolicy(id uint, input model.BookingPolicyInput) (*model.BookingPolicy, error) {
+	policy, err := GetBookingPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBookingPolicyInput(policy, input); err != nil {
+		return nil, err
+	}
+	if err := dao.UpdateBookingPolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ManagerDeleteBookingPolicy deletes a booking policy. Its courses fall back to their category's policy or the default.
+func ManagerDeleteBookingPolicy(id uint) error {
+	if _, err := GetBookingPolicy(id); err != nil {
+		return err
+	}
+	return dao.DeleteBookingPolicyByID(id)
+}
+
+// applyBookingPolicyInput validates the input and copies it onto the policy.
+func applyBookingPolicyInput(policy *model.BookingPolicy, input model.BookingPolicyInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("booking policy name is required")
+	}
+	taken, err := dao.CheckBookingPolicyNameTaken(name, policy.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("booking policy name already exists")
+	}
+
+	policy.Category = nil
+	if category := strings.TrimSpace(input.Category); category != "" {
+		taken, err := dao.CheckBookingPolicyCategoryTaken(category, policy.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("category already has a booking policy")
+		}
+		policy.Category = &category
+	}
+
+	if input.OpensHoursBefore > 0 && input.ClosesMinutesBefore >= input.OpensHoursBefore*60 {
+		return errors.New("booking must open before it closes")
+	}
+
+	policy.Name = name
+	policy.OpensHoursBefore = input.OpensHoursBefore
+	policy.ClosesMinutesBefore = input.ClosesMinutesBefore
+	policy.CancelCutoffMinutes = input.CancelCutoffMinutes
+	policy.MaxActiveBookings = input.MaxActiveBookings
+	policy.MaxBookingsPerDay = input.MaxBookingsPerDay
+	return nil
+}
+
+// assignCourseBookingPolicy links a course to an existing policy; 0 detaches it.
+func assignCourseBookingPolicy(course *model.Course, policyID uint) error {
+	if policyID == 0 {
+		course.BookingPolicyID = nil
+		return nil
+	}
+	policy, err := dao.GetBookingPolicyByID(policyID)
+	if err != nil {
+		return errors.New("booking policy not found")
+	}
+	course.BookingPolicyID = &policy.ID
+	return nil
+}
+
+// courseBookingPolicy resolves the policy of a course: its own, then its category's, then the default.
+func courseBookingPolicy(course *model.Course) *model.BookingPolicy {
+	if course.BookingPolicyID != nil {
+		if policy, err := dao.GetBookingPolicyByID(*course.BookingPolicyID); err == nil {
+			return policy
+		}
+	}
+	if category := strings.TrimSpace(course.Category); category != "" {
+		if policy, err := dao.GetBookingPolicyByCategory(category); err == nil {
+			return policy
+		}
+	}
+	policy := DefaultBookingPolicy
+	return &policy
+}
+
+// checkBookingWindow enforces when booking opens and closes for a session starting at start.
+func checkBookingWindow(policy *model.BookingPolicy, start time.Time, now time.Time) error {
+	if policy.ClosesMinutesBefore > 0 {
+		if !now.Before(start.Add(-time.Duration(policy.ClosesMinutesBefore) * time.Minute)) {
+			return &BookingPolicyError{
+				Code:    BookingCodeClosed,
+				Message: fmt.Sprintf("registration closed %d minutes before class start", policy.ClosesMinutesBefore),
+			}
+		}
+	} else if now.After(start) {
+		return &BookingPolicyError{Code: BookingCodeClosed, Message: "registration closed: class has already started"}
+	}
+
+	if policy.OpensHoursBefore > 0 && now.Before(start.Add(-time.Duration(policy.OpensHoursBefore)*time.Hour)) {
+		return &BookingPolicyError{
+			Code:    BookingCodeNotOpen,
+			Message: fmt.Sprintf("enrollment opens %d hours before class start.", policy.OpensHoursBefore),
+		}
+	}
+	return nil
+}
+
+// checkBookingLimits enforces the per-member booking limits for a new booking in a session.
+func checkBookingLimits(userID uint, policy *model.BookingPolicy, session *model.ClassSession, now time.Time) error {
+	if policy.MaxActiveBookings > 0 {
+		active, err := dao.CountActiveBookingsByUser(userID, now)
+		if err != nil {
+			return err
+		}
+		if int(active) >= policy.MaxActiveBookings {
+			return &BookingPolicyError{
+				Code:    BookingCodeActiveLimit,
+				Message: fmt.Sprintf("active booking limit of %d reached", policy.MaxActiveBookings),
+			}
+		}
+	}
+
+	if policy.MaxBookingsPerDay > 0 {
+		booked, err := dao.CountBookingsByUserOnDate(userID, session.SessionDate)
+		if err != nil {
+			return err
+		}
+		if int(booked) >= policy.MaxBookingsPerDay {
+			return &BookingPolicyError{
+				Code:    BookingCodeDailyLimit,
+				Message: fmt.Sprintf("daily booking limit of %d reached", policy.MaxBookingsPerDay),
+			}
+		}
+	}
+	return nil
+}
+
+// checkCancelCutoff refuses to drop a seated enrollment once its session is inside the late-cancel cutoff.
+// Waitlist entries hold no seat and can always be dropped.
+func checkCancelCutoff(enrollment *model.Enrollment, now time.Time) error {
+	if enrollment.Status != model.EnrollmentStatusEnrolled || enrollment.Session == nil {
+		return nil
+	}
+
+	course, err := dao.GetCourseByID(enrollment.CourseID)
+	if err != nil {
+		return errors.New("class not found")
+	}
+	policy := courseBookingPolicy(course)
+	if policy.CancelCutoffMinutes <= 0 {
+		return nil
+	}
+
+	cutoff := enrollment.Session.StartAt.Add(-time.Duration(policy.CancelCutoffMinutes) * time.Minute)
+	if !now.Before(cutoff) {
+		return &BookingPolicyError{
+			Code:    BookingCodeCancelCutoff,
+			Message: fmt.Sprintf("cancellation closed %d minutes before class start", policy.CancelCutoffMinutes),
+		}
+	}
+	return nil
+}
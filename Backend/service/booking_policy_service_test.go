@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"my-course-backend/model"
+)
+
+func expectPolicyCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var refusal *BookingPolicyError
+	if !errors.As(err, &refusal) || refusal.Code != code {
+		t.Fatalf("expected booking policy error %q, got: %v", code, err)
+	}
+}
+
+func TestRegisterSession_UsesCategoryAndCoursePolicies(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Pilates", 5, "Core")
+	inTwoDays := seedSessionAt(t, course, time.Now().Add(40*time.Hour), 5)
+
+	if err := RegisterSession(user.ID, inTwoDays.ID); err == nil {
+		t.Fatal("expected the default 25 hour window to refuse the booking")
+	} else {
+		expectPolicyCode(t, err, BookingCodeNotOpen)
+	}
+
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Core classes", Category: "core", OpensHoursBefore: 72}); err != nil {
+		t.Fatalf("failed to create category policy: %v", err)
+	}
+	if err := RegisterSession(user.ID, inTwoDays.ID); err != nil {
+		t.Fatalf("expected the category policy to open booking, got: %v", err)
+	}
+
+	strict, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Strict", OpensHoursBefore: 24, ClosesMinutesBefore: 180})
+	if err != nil {
+		t.Fatalf("failed to create course policy: %v", err)
+	}
+	policyID := strict.ID
+	if _, err := ManagerUpdateCourse(course.ID, CourseUpsertInput{
+		CourseName: course.CourseName, CourseCode: course.CourseCode, Capacity: course.Capacity, Category: course.Category,
+		Weekday: course.Weekday, StartTime: course.StartTime.Format("15:04"), EndTime: course.EndTime.Format("15:04"),
+		BookingPolicyID: &policyID,
+	}); err != nil {
+		t.Fatalf("failed to attach policy: %v", err)
+	}
+
+	soon := seedSessionAt(t, course, time.Now().Add(2*time.Hour), 5)
+	err = RegisterSession(user.ID, soon.ID)
+	expectPolicyCode(t, err, BookingCodeClosed)
+	if err.Error() != "registration closed 180 minutes before class start" {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestRegisterSession_EnforcesBookingLimits(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Cardio", Category: "Cardio", MaxBookingsPerDay: 1, MaxActiveBookings: 2}); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	now := model.FacilityNow()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 10, 0, 0, 0, now.Location())
+	spin := seedCourse(t, "Spin", 5, "Cardio")
+	row := seedCourse(t, "Row", 5, "Cardio")
+	first := seedSessionAt(t, spin, tomorrow, 5)
+	sameDay := seedSessionAt(t, row, tomorrow.Add(2*time.Hour), 5)
+
+	if err := RegisterSession(user.ID, first.ID); err != nil {
+		t.Fatalf("expected first booking to succeed, got: %v", err)
+	}
+	expectPolicyCode(t, RegisterSession(user.ID, sameDay.ID), BookingCodeDailyLimit)
+
+	nextDay := seedSessionAt(t, row, tomorrow.AddDate(0, 0, 1), 5)
+	if err := RegisterSession(user.ID, nextDay.ID); err != nil {
+		t.Fatalf("expected next day booking to succeed, got: %v", err)
+	}
+	third := seedSessionAt(t, spin, tomorrow.AddDate(0, 0, 1).Add(4*time.Hour), 5)
+	expectPolicyCode(t, RegisterSession(user.ID, third.ID), BookingCodeActiveLimit)
+}
+
+func TestDropSession_EnforcesCancelCutoff(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Late cancel", Category: "Strength", OpensHoursBefore: 25, CancelCutoffMinutes: 240}); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	course := seedCourse(t, "Lift", 1, "Strength")
+	soon := seedSessionAt(t, course, time.Now().Add(2*time.Hour), 1)
+	later := seedSessionAt(t, course, time.Now().Add(6*time.Hour), 1)
+	seedEnrollmentForSession(t, user.ID, course.ID, soon.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, user.ID, course.ID, later.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	err := DropSession(user.ID, soon.ID)
+	expectPolicyCode(t, err, BookingCodeCancelCutoff)
+	if err.Error() != "cancellation closed 240 minutes before class start" {
+		t.Fatalf("unexpected message: %v", err)
+	}
+	if err := DropSession(user.ID, later.ID); err != nil {
+		t.Fatalf("expected drop before the cutoff to succeed, got: %v", err)
+	}
+
+	// Waitlist entries hold no seat and can be dropped inside the cutoff.
+	seedEnrollmentForSession(t, waiting.ID, course.ID, soon.ID, model.EnrollmentStatusWaitlisted, time.Now())
+	if err := DropSession(waiting.ID, soon.ID); err != nil {
+		t.Fatalf("expected waitlist drop to succeed, got: %v", err)
+	}
+}
+
+func TestManagerCreateBookingPolicy_Validation(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Yoga", Category: "Wellness"}); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "yoga"}); err == nil || err.Error() != "booking policy name already exists" {
+		t.Fatalf("expected duplicate name error, got: %v", err)
+	}
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Other", Category: "WELLNESS"}); err == nil || err.Error() != "category already has a booking policy" {
+		t.Fatalf("expected duplicate category error, got: %v", err)
+	}
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Backwards", OpensHoursBefore: 1, ClosesMinutesBefore: 90}); err == nil || err.Error() != "booking must open before it closes" {
+		t.Fatalf("expected window error, got: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// CheckInSettings configures check-in codes and what the attendance sync does with members who never checked in.
+type CheckInSettings struct {
+	// Policy is model.AttendancePolicyCheckIn or model.AttendancePolicyAssumeAttended.
+	Policy string
+	// CodeTTL is how long an issued check-in code stays valid; codes never outlive their session.
+	CodeTTL time.Duration
+	// OpensBefore is how long before a session starts members can check in.
+	OpensBefore time.Duration
+}
+
+// DefaultCheckInSettings marks no-shows missed; codes last 10 minutes and check-in opens 30 minutes before the start.
+var DefaultCheckInSettings = CheckInSettings{
+	Policy:      model.AttendancePolicyCheckIn,
+	CodeTTL:     10 * time.Minute,
+	OpensBefore: 30 * time.Minute,
+}
+
+var (
+	checkInSettings = DefaultCheckInSettings
+	checkInSecret   = randomCheckInSecret()
+	kioskKey        string
+)
+
+// SetCheckInSettings replaces the check-in settings.
+func SetCheckInSettings(settings CheckInSettings) {
+	checkInSettings = settings
+}
+
+// SetCheckInSecret sets the key check-in codes are signed with. Without one a random key is used,
+// so codes do not survive a restart or work across instances.
+func SetCheckInSecret(secret string) {
+	if secret == "" {
+		checkInSecret = randomCheckInSecret()
+		return
+	}
+	checkInSecret = []byte(secret)
+}
+
+// SetKioskKey sets the shared key kiosks present to check members in; empty disables kiosk check-in.
+func SetKioskKey(key string) {
+	kioskKey = key
+}
+
+// ValidKioskKey reports whether key is the configured kiosk key.
+func ValidKioskKey(key string) bool {
+	return kioskKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(kioskKey)) == 1
+}
+
+func randomCheckInSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// IssueCheckInCode returns a signed check-in code for the member's seat in a session.
+func IssueCheckInCode(userID uint, sessionID uint) (*model.CheckInCode, error) {
+	enrollment, err := dao.GetActiveSessionEnrollment(userID, sessionID)
+	if err != nil || enrollment.Status != model.EnrollmentStatusEnrolled || enrollment.Session == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	now := time.Now()
+	if err := checkCheckInWindow(enrollment.Session, now); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(checkInSettings.CodeTTL)
+	if enrollment.Session.EndAt.Before(expiresAt) {
+		expiresAt = enrollment.Session.EndAt
+	}
+	return &model.CheckInCode{
+		Token:        signCheckInCode(enrollment.ID, expiresAt),
+		EnrollmentID: enrollment.ID,
+		SessionID:    sessionID,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// CheckIn validates a scanned code and records the member as attended. staffID is the instructor or
+// manager scanning it, nil at a kiosk; instructors may only check in members of sessions they teach.
+func CheckIn(token string, staffID *uint, instructor bool) (*model.Enrollment, error) {
+	enrollmentID, err := verifyCheckInCode(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := dao.GetEnrollmentByID(enrollmentID)
+	if err != nil || enrollment.Session == nil {
+		return nil, errors.New("invalid check-in code")
+	}
+
+	if instructor && staffID != nil {
+		_, access, err := resolveCourseAccess(*staffID, enrollment.CourseID)
+		if err != nil {
+			return nil, err
+		}
+		if !access.covers(*enrollment.SessionID) {
+			return nil, errors.New("forbidden")
+		}
+	}
+
+	if enrollment.CheckedInAt != nil {
+		return nil, errors.New("already checked in")
+	}
+	now := time.Now()
+	if err := checkCheckInWindow(enrollment.Session, now); err != nil {
+		return nil, err
+	}
+
+	checkedIn, err := dao.MarkEnrollmentCheckedIn(enrollment.ID, now, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if !checkedIn {
+		return nil, errors.New("enrollment is not seated")
+	}
+	enrollment.Status = model.EnrollmentStatusAttended
+	enrollment.CheckedInAt = &now
+	enrollment.CheckedInBy = staffID
+	return enrollment, nil
+}
+
+func checkCheckInWindow(session *model.ClassSession, now time.Time) error {
+	if session.Status != "scheduled" {
+		return errors.New("session is not open for check-in")
+	}
+	if now.Before(session.StartAt.Add(-checkInSettings.OpensBefore)) {
+		return errors.New("check-in is not open yet")
+	}
+	if !now.Before(session.EndAt) {
+		return errors.New("check-in has closed")
+	}
+	return nil
+}
+
+// Check-in codes are "<enrollment id>.<expiry unix>.<truncated HMAC>", short enough for a small QR code.
+func signCheckInCode(enrollmentID uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", enrollmentID, expiresAt.Unix())
+	return payload + "." + checkInSignature(payload)
+}
+
+func checkInSignature(payload string) string {
+	mac := hmac.New(sha256.New, checkInSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func verifyCheckInCode(token string, now time.Time) (uint, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return 0, errors.New("invalid check-in code")
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(checkInSignature(payload))) {
+		return 0, errors.New("invalid check-in code")
+	}
+
+	enrollmentID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid check-in code")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid check-in code")
+	}
+	if now.Unix() >= expiresAt {
+		return 0, errors.New("check-in code has expired")
+	}
+	return uint(enrollmentID), nil
+}
+
+// syncEndedEnrollments settles seated enrollments of ended sessions under the attendance policy.
+// Under the check-in policy members who never checked in are marked missed and get a no-show strike.
+func syncEndedEnrollments() error {
+	if checkInSettings.Policy == model.AttendancePolicyAssumeAttended {
+		return dao.SyncEndedEnrollmentsToAttended()
+	}
+
+	missed, err := dao.MarkEndedEnrollmentsMissed()
+	if err != nil {
+		return err
+	}
+	for i := range missed {
+		if err := addNoShowStrike(&missed[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
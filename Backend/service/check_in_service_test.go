@@ -0,0 +1,118 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func useCheckInSettings(t *testing.T, settings CheckInSettings) {
+	t.Helper()
+	SetCheckInSettings(settings)
+	t.Cleanup(func() { SetCheckInSettings(DefaultCheckInSettings) })
+}
+
+func TestCheckIn_ValidatesCodeAndRecordsArrival(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	student := seedRoleAndUser(t, 1)
+	teacher := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, teacher)
+	course := seedCourse(t, "Boxing", 5, "Combat")
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Update("instructor_id", profile.ID)
+
+	later := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 5)
+	seedEnrollmentForSession(t, student.ID, course.ID, later.ID, model.EnrollmentStatusEnrolled, time.Now())
+	if _, err := IssueCheckInCode(student.ID, later.ID); err == nil || err.Error() != "check-in is not open yet" {
+		t.Fatalf("expected check-in to be closed three hours out, got %v", err)
+	}
+
+	soon := seedSessionAt(t, course, time.Now().Add(10*time.Minute), 5)
+	enrollment := seedEnrollmentForSession(t, student.ID, course.ID, soon.ID, model.EnrollmentStatusEnrolled, time.Now())
+	code, err := IssueCheckInCode(student.ID, soon.ID)
+	if err != nil {
+		t.Fatalf("failed to issue code: %v", err)
+	}
+	if code.EnrollmentID != enrollment.ID || !code.ExpiresAt.After(time.Now()) {
+		t.Fatalf("unexpected code %+v", code)
+	}
+
+	tampered := code.Token[:len(code.Token)-1] + "A"
+	if tampered == code.Token {
+		tampered = code.Token[:len(code.Token)-1] + "B"
+	}
+	if _, err := CheckIn(tampered, nil, false); err == nil || err.Error() != "invalid check-in code" {
+		t.Fatalf("expected a tampered code to be rejected, got %v", err)
+	}
+	expired := signCheckInCode(enrollment.ID, time.Now().Add(-time.Second))
+	if _, err := CheckIn(expired, nil, false); err == nil || err.Error() != "check-in code has expired" {
+		t.Fatalf("expected an expired code to be rejected, got %v", err)
+	}
+
+	checkedIn, err := CheckIn(code.Token, &teacher.ID, true)
+	if err != nil {
+		t.Fatalf("expected check-in to succeed, got %v", err)
+	}
+	if checkedIn.Status != model.EnrollmentStatusAttended || checkedIn.CheckedInAt == nil || checkedIn.CheckedInBy == nil || *checkedIn.CheckedInBy != teacher.ID {
+		t.Fatalf("expected the arrival recorded, got %+v", checkedIn)
+	}
+	if _, err := CheckIn(code.Token, nil, false); err == nil || err.Error() != "already checked in" {
+		t.Fatalf("expected a second scan to be refused, got %v", err)
+	}
+
+	other := seedUserWithRole(t, 4)
+	seedInstructorProfile(t, other)
+	otherStudent := seedUserWithRole(t, 1)
+	seedEnrollmentForSession(t, otherStudent.ID, course.ID, soon.ID, model.EnrollmentStatusEnrolled, time.Now())
+	otherCode, err := IssueCheckInCode(otherStudent.ID, soon.ID)
+	if err != nil {
+		t.Fatalf("failed to issue code: %v", err)
+	}
+	if _, err := CheckIn(otherCode.Token, &other.ID, true); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected an instructor of another class to be refused, got %v", err)
+	}
+}
+
+func TestSyncEndedEnrollments_MarksNoShowsMissed(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	present := seedRoleAndUser(t, 1)
+	absent := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 5, "Cardio")
+	past := seedPastSession(t, course, 1)
+	arrived := seedEnrollmentForSession(t, present.ID, course.ID, past.ID, model.EnrollmentStatusEnrolled, time.Now().AddDate(0, 0, -2))
+	skipped := seedEnrollmentForSession(t, absent.ID, course.ID, past.ID, model.EnrollmentStatusEnrolled, time.Now().AddDate(0, 0, -2))
+	if _, err := dao.MarkEnrollmentCheckedIn(arrived.ID, past.StartAt, nil); err != nil {
+		t.Fatalf("failed to check in: %v", err)
+	}
+
+	if err := syncEndedEnrollments(); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if err := syncEndedEnrollments(); err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+
+	if got := loadEnrollmentByID(t, arrived.ID); got.Status != model.EnrollmentStatusAttended {
+		t.Fatalf("expected the checked-in member attended, got %s", got.Status)
+	}
+	if got := loadEnrollmentByID(t, skipped.ID); got.Status != model.EnrollmentStatusMissed {
+		t.Fatalf("expected the no-show missed, got %s", got.Status)
+	}
+	strikes, _ := dao.ListStrikesByUser(absent.ID)
+	if len(strikes) != 1 || strikes[0].Reason != model.StrikeReasonNoShow {
+		t.Fatalf("expected one no-show strike, got %+v", strikes)
+	}
+}
+
+func loadEnrollmentByID(t *testing.T, id uint) model.Enrollment {
+	t.Helper()
+	var enrollment model.Enrollment
+	if err := db.DB.First(&enrollment, id).Error; err != nil {
+		t.Fatalf("failed to load enrollment: %v", err)
+	}
+	return enrollment
+}
@@ -9,7 +9,7 @@ import (
 	"time"
 )
 
-// RegisterClass enrolls a user in a course.
+// RegisterClass enrolls a user in a course, spending a class credit unless an unlimited plan covers it.
 func RegisterClass(userID uint, courseID uint) error {
 	if _, err := dao.GetUserByID(userID); err != nil {
 		return errors.New("user not found")
@@ -20,7 +20,13 @@ func RegisterClass(userID uint, courseID uint) error {
 		return errors.New("class not found")
 	}
 
-	if err := validateEnrollmentWindow(class, time.Now()); err != nil {
+	now := time.Now()
+	if err := checkMemberStanding(userID, now); err != nil {
+		return err
+	}
+
+	policy := courseBookingPolicy(class)
+	if err := validateEnrollmentWindow(class, policy, now); err != nil {
 		return err
 	}
 
@@ -54,6 +60,13 @@ func RegisterClass(userID uint, courseID uint) error {
 		return errors.New("no upcoming session found for this class")
 	}
 
+	if err := checkBookingLimits(userID, policy, session, now); err != nil {
+		return err
+	}
+	if err := checkEntitlement(userID); err != nil {
+		return err
+	}
+
 	enrollment := model.Enrollment{
 		UserID:    userID,
 		CourseID:  courseID,
@@ -63,6 +76,9 @@ func RegisterClass(userID uint, courseID uint) error {
 	if err := dao.CreateEnrollment(&enrollment); err != nil {
 		return err
 	}
+	if err := chargeBooking(&enrollment); err != nil {
+		return err
+	}
 
 	return dao.BackfillUserDailyActivityFromEnrollments(userID)
 }
@@ -79,11 +95,7 @@ func hasScheduleOverlap(userID uint, targetClass *model.Course) (bool, error) {
 			continue
 		}
 
-		if !isSameWeekday(existing.Weekday, targetClass.Weekday) {
-			continue
-		}
-
-		if timeRangesOverlap(existing.StartTime, existing.EndTime, targetClass.StartTime, targetClass.EndTime) {
+		if _, overlaps := overlappingSlot(courseSlots(existing), courseSlots(targetClass)); overlaps {
 			return true, nil
 		}
 	}
@@ -110,48 +122,21 @@ func normalizeWeekday(value string) string {
 	return trimmed[:3]
 }
 
-// validateEnrollmentWindow enforces that enrollment opens 25 hours before the next class start.
-func validateEnrollmentWindow(class *model.Course, now time.Time) error {
-	if class == nil || class.StartTime.Time.IsZero() {
+// validateEnrollmentWindow enforces the booking policy's window against the next class start
+// across all of the course's weekly slots.
+func validateEnrollmentWindow(class *model.Course, policy *model.BookingPolicy, now time.Time) error {
+	if class == nil {
 		return errors.New("invalid class schedule")
 	}
 
-	weekdayMap := map[string]time.Weekday{
-		"sun": time.Sunday,
-		"mon": time.Monday,
-		"tue": time.Tuesday,
-		"wed": time.Wednesday,
-		"thu": time.Thursday,
-		"fri": time.Friday,
-		"sat": time.Saturday,
-	}
-
-	targetWeekday, ok := weekdayMap[normalizeWeekday(class.Weekday)]
+	// Slot times are facility wall-clock times.
+	now = now.In(model.FacilityLocation())
+	nextStart, ok := nextSlotStart(courseSlots(class), now)
 	if !ok {
 		return errors.New("invalid class schedule")
 	}
 
-	loc := now.Location()
-	nextStart := time.Date(now.Year(), now.Month(), now.Day(), class.StartTime.Hour(), class.StartTime.Minute(), 0, 0, loc)
-
-	daysAhead := (int(targetWeekday) - int(now.Weekday()) + 7) % 7
-	nextStart = nextStart.AddDate(0, 0, daysAhead)
-
-	// If today's class already started, use next week's occurrence.
-	if daysAhead == 0 && now.After(nextStart) {
-		nextStart = nextStart.AddDate(0, 0, 7)
-	}
-
-	if now.After(nextStart) {
-		return errors.New("registration closed: class has already started")
-	}
-
-	enrollmentOpen := nextStart.Add(-25 * time.Hour)
-	if now.Before(enrollmentOpen) {
-		return errors.New("enrollment opens 25 hours before class start.")
-	}
-
-	return nil
+	return checkBookingWindow(policy, nextStart, now)
 }
 
 func timeRangesOverlap(startA, endA, startB, endB model.TimeOnly) bool {
@@ -171,13 +156,35 @@ func toMinutes(value model.TimeOnly) int {
 	return value.Hour()*60 + value.Minute()
 }
 
-// DropClass removes a user's enrollment from a course.
+// DropClass removes a user's enrollment (or waitlist entry) from a course.
+// Seated enrollments cannot be dropped inside the booking policy's late-cancel cutoff.
+// The booking's credit is refunded unless the drop is a late cancel.
+// A freed seat is handed to the first person on the session's waitlist.
 func DropClass(userID uint, courseID uint) error {
-	if err := dao.DeleteEnrollment(userID, courseID); err != nil {
+	enrollment, err := dao.GetActiveEnrollment(userID, courseID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := checkCancelCutoff(enrollment, now); err != nil {
 		return err
 	}
 
-	return nil
+	removed, err := dao.DeleteEnrollment(userID, courseID)
+	if err != nil {
+		return err
+	}
+	if err := recordLateCancel(enrollment, now); err != nil {
+		return err
+	}
+	if err := refundDrop(enrollment, now); err != nil {
+		return err
+	}
+	if err := skipSeriesSession(removed); err != nil {
+		return err
+	}
+
+	return releaseSeat(removed)
 }
 
 // ListClassEnrollments returns all enrollments for a course.
@@ -185,23 +192,54 @@ func ListClassEnrollments(courseID uint) ([]model.Enrollment, error) {
 	if _, err := dao.GetCourseByID(courseID); err != nil {
 		return nil, errors.New("class not found")
 	}
-	if err := dao.SyncEndedEnrollmentsToAttended(); err != nil {
+	if err := syncEndedEnrollments(); err != nil {
 		return nil, err
 	}
 	return dao.ListEnrollmentsByClass(courseID)
 }
 
 func fillCourseSpot(class *model.Course) error {
-	count, err := dao.CountEnrollmentsByClass(class.ID)
+	seats, err := dao.ListNextSessionSeats([]uint{class.ID})
 	if err != nil {
 		return err
 	}
-	spot := class.Capacity - int(count)
+	class.Spot = courseSpot(class, seats)
+	return nil
+}
+
+// fillCourseSpots populates Spot for many courses with a single aggregated count query.
+func fillCourseSpots(classes []model.Course) error {
+	ids := make([]uint, len(classes))
+	for i := range classes {
+		ids[i] = classes[i].ID
+	}
+
+	seats, err := dao.ListNextSessionSeats(ids)
+	if err != nil {
+		return err
+	}
+	for i := range classes {
+		classes[i].Spot = courseSpot(&classes[i], seats)
+	}
+	return nil
+}
+
+// courseSpot returns the open seats of a course's next session, capped like bookings by the session's own
+// capacity. A course without a scheduled session shows its full capacity.
+func courseSpot(class *model.Course, seats map[uint]model.SessionSeats) int {
+	next, ok := seats[class.ID]
+	if !ok {
+		return openSpots(class.Capacity, 0)
+	}
+	return openSpots(next.Capacity, next.Seated)
+}
+
+func openSpots(capacity int, seated int64) int {
+	spot := capacity - int(seated)
 	if spot < 0 {
 		spot = 0
 	}
-	class.Spot = spot
-	return nil
+	return spot
 }
 
 // ListCategories returns all distinct course categories.
@@ -209,21 +247,62 @@ func ListCategories() ([]string, error) {
 	return dao.ListCategories()
 }
 
-// ListClasses returns all courses with spot populated.
-func ListClasses() ([]model.Course, error) {
-	classes, err := dao.ListClasses()
+// MaxClassPageSize caps the page size of the class catalog.
+const MaxClassPageSize = 100
+
+// ListClasses returns one page of courses matching the filter with spot and room populated.
+// Returns the classes, total matches, page, limit and total pages; a limit of 0 returns every match on one page.
+func ListClasses(filter model.ClassFilter) ([]model.Course, int64, int, int, int, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit < 0 {
+		filter.Limit = 0
+	}
+	if filter.Limit > MaxClassPageSize {
+		filter.Limit = MaxClassPageSize
+	}
+	if filter.Sort != "" && !validClassSort(filter.Sort) {
+		return nil, 0, 0, 0, 0, errors.New("invalid sort")
+	}
+	if filter.Weekday != "" {
+		filter.Weekday = normalizeWeekday(filter.Weekday)
+		if _, ok := validWeekdays[filter.Weekday]; !ok {
+			return nil, 0, 0, 0, 0, errors.New("invalid weekday")
+		}
+	}
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	classes, total, err := dao.ListClasses(filter)
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, 0, 0, err
 	}
-	for i := range classes {
-		if err := fillCourseSpot(&classes[i]); err != nil {
-			return nil, err
+	if err := fillCourseSpots(classes); err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	totalPages := 0
+	if total > 0 {
+		totalPages = 1
+	}
+	if filter.Limit > 0 {
+		totalPages = int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+	}
+	return classes, total, filter.Page, filter.Limit, totalPages, nil
+}
+
+func validClassSort(sort string) bool {
+	field := strings.TrimPrefix(sort, "-")
+	for _, allowed := range model.ClassSortFields {
+		if field == allowed {
+			return true
 		}
 	}
-	return classes, nil
+	return false
 }
 
-// GetClass returns a single class by ID with spot populated.
+// GetClass returns a single class by ID with spot and room populated.
 func GetClass(courseID uint) (*model.Course, error) {
 	class, err := dao.GetCourseByID(courseID)
 	if err != nil {
@@ -232,6 +311,9 @@ func GetClass(courseID uint) (*model.Course, error) {
 	if err := fillCourseSpot(class); err != nil {
 		return nil, err
 	}
+	if err := fillCourseRoom(class); err != nil {
+		return nil, err
+	}
 	return class, nil
 }
 
@@ -240,7 +322,7 @@ func GetUserEnrolledClasses(userID uint) ([]model.Course, error) {
 	if _, err := dao.GetUserByID(userID); err != nil {
 		return nil, errors.New("user not found")
 	}
-	if err := dao.SyncEndedEnrollmentsToAttended(); err != nil {
+	if err := syncEndedEnrollments(); err != nil {
 		return nil, err
 	}
 
@@ -249,10 +331,8 @@ func GetUserEnrolledClasses(userID uint) ([]model.Course, error) {
 		return nil, err
 	}
 
-	for i := range courses {
-		if err := fillCourseSpot(&courses[i]); err != nil {
-			return nil, err
-		}
+	if err := fillCourseSpots(courses); err != nil {
+		return nil, err
 	}
 
 	return courses, nil
@@ -263,7 +343,7 @@ func GetUserAnalytics(userID uint, rangeKey string) (*model.UserAnalyticsRespons
 	if _, err := dao.GetUserByID(userID); err != nil {
 		return nil, errors.New("user not found")
 	}
-	if err := dao.SyncEndedEnrollmentsToAttended(); err != nil {
+	if err := syncEndedEnrollments(); err != nil {
 		return nil, err
 	}
 
@@ -272,7 +352,8 @@ func GetUserAnalytics(userID uint, rangeKey string) (*model.UserAnalyticsRespons
 	}
 
 	// Cap toDate to today so analytics only include past/current-day classes, not future sessions.
-	now := time.Now()
+	// Days are bucketed by the facility calendar.
+	now := model.FacilityNow()
 	toDate := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 	fromDate := resolveRangeStart(rangeKey, now)
 
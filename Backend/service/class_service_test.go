@@ -33,13 +33,19 @@ func setupClassServiceTestDB(t *testing.T) {
 		&model.User{},
 		&model.UserInfo{},
 		&model.Course{},
+		&model.Instructor{}, &model.CourseInstructor{}, &model.InstructorAvailability{}, &model.Room{}, &model.CourseSlot{}, &model.BlackoutDate{}, &model.BookingPolicy{}, &model.MemberStrike{}, &model.MembershipPlan{}, &model.MemberSubscription{}, &model.CreditLedgerEntry{}, &model.PaymentOrder{}, &model.PaymentEvent{}, &model.RefreshToken{}, &model.AccountToken{},
 		&model.Enrollment{},
 		&model.UserDailyActivity{},
+		&model.BookingSeries{},
+		&model.BookingSeriesItem{},
+		&model.SessionChangeLog{},
+		&model.SessionNotification{},
 	); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
 	db.DB = testDB
+	SetMembershipRequired(false)
 }
 
 func seedRoleAndUser(t *testing.T, roleID uint) model.User {
@@ -274,6 +280,19 @@ func TestRegisterClass_ClassFull(t *testing.T) {
 	}
 }
 
+func TestRegisterClass_IgnoresCanceledEnrollments(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user1 := seedRoleAndUser(t, 1)
+	user2 := seedRoleAndUser(t, 2)
+	course := seedCourse(t, "Boxing", 1, "Combat")
+	seedEnrollmentAt(t, user1.ID, course.ID, model.EnrollmentStatusCanceled, time.Now())
+
+	if err := RegisterClass(user2.ID, course.ID); err != nil {
+		t.Fatalf("expected the canceled enrollment to leave the seat free, got: %v", err)
+	}
+}
+
 func TestRegisterClass_ScheduleOverlap(t *testing.T) {
 	setupClassServiceTestDB(t)
 
@@ -345,8 +364,9 @@ func TestListClasses_ReturnsSpot(t *testing.T) {
 
 	seedEnrollmentAt(t, user1.ID, courseA.ID, model.EnrollmentStatusEnrolled, time.Now())
 	seedEnrollmentAt(t, user2.ID, courseA.ID, model.EnrollmentStatusAttended, time.Now())
+	seedEnrollmentAt(t, user1.ID, courseB.ID, model.EnrollmentStatusCanceled, time.Now())
 
-	classes, err := ListClasses()
+	classes, _, _, _, _, err := ListClasses(model.ClassFilter{})
 	if err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
@@ -367,8 +387,95 @@ func TestListClasses_ReturnsSpot(t *testing.T) {
 	}
 }
 
+func TestListClasses_CountsSpotsAgainstTheNextSessionRoom(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user1 := seedRoleAndUser(t, 1)
+	user2 := seedUserWithRole(t, 1)
+	capped := seedCourse(t, "Capped Spin", 10, "Cardio")
+	roomy := seedCourse(t, "Roomy Spin", 5, "Cardio")
+
+	room, err := ManagerCreateRoom(model.RoomInput{Name: "Studio B", Capacity: 2})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	var session model.ClassSession
+	if err := db.DB.Where("course_id = ?", capped.ID).First(&session).Error; err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if _, err := ManagerSetSessionRoom(session.ID, model.SessionRoomInput{RoomID: &room.ID}); err != nil {
+		t.Fatalf("failed to move session: %v", err)
+	}
+	seedEnrollmentAt(t, user1.ID, capped.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentAt(t, user2.ID, capped.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	classes, total, _, _, _, err := ListClasses(model.ClassFilter{OnlyOpen: true})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if total != 1 || len(classes) != 1 || classes[0].ID != roomy.ID {
+		t.Fatalf("expected only the class with open seats, got %+v", classes)
+	}
+
+	classes, _, _, _, _, err = ListClasses(model.ClassFilter{Sort: "spots"})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if len(classes) != 2 || classes[0].ID != capped.ID || classes[0].Spot != 0 || classes[1].Spot != 5 {
+		t.Fatalf("expected the capped class first with no spots left, got %+v", classes)
+	}
+}
+
+func TestListClasses_FiltersSortsAndPages(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	full := seedCourse(t, "Morning Spin", 1, "Cardio")
+	yoga := seedCourse(t, "Yoga Flow", 5, "Wellness")
+	boxing := seedCourse(t, "Boxing Basics", 8, "Cardio")
+	db.DB.Model(&model.Course{}).Where("id = ?", yoga.ID).Update("description", "Gentle stretching")
+	seedEnrollmentAt(t, user.ID, full.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	classes, total, _, _, _, err := ListClasses(model.ClassFilter{Category: "cardio", OnlyOpen: true})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if total != 1 || len(classes) != 1 || classes[0].ID != boxing.ID {
+		t.Fatalf("expected only the open cardio class, got %+v", classes)
+	}
+
+	classes, _, _, _, _, err = ListClasses(model.ClassFilter{Search: "STRETCH"})
+	if err != nil || len(classes) != 1 || classes[0].ID != yoga.ID {
+		t.Fatalf("expected description search to match yoga, got %+v (err %v)", classes, err)
+	}
+
+	classes, total, page, limit, totalPages, err := ListClasses(model.ClassFilter{Sort: "-spots", Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if total != 3 || page != 2 || limit != 2 || totalPages != 2 {
+		t.Fatalf("unexpected paging: total=%d page=%d limit=%d pages=%d", total, page, limit, totalPages)
+	}
+	if len(classes) != 1 || classes[0].ID != full.ID || classes[0].Spot != 0 {
+		t.Fatalf("expected the full class last when sorted by spots, got %+v", classes)
+	}
+
+	coach := seedInstructorProfile(t, seedUserWithRole(t, 1))
+	db.DB.Model(&model.Course{}).Where("id = ?", full.ID).Update("instructor_id", coach.ID)
+	db.DB.Create(&model.CourseInstructor{CourseID: yoga.ID, InstructorID: coach.ID, Role: model.CourseInstructorRoleAssistant})
+	classes, _, _, _, _, err = ListClasses(model.ClassFilter{InstructorID: &coach.ID, Category: "Cardio"})
+	if err != nil || len(classes) != 1 || classes[0].ID != full.ID {
+		t.Fatalf("expected the instructor's cardio class only, got %+v (err %v)", classes, err)
+	}
+
+	if _, _, _, _, _, err := ListClasses(model.ClassFilter{Sort: "price"}); err == nil || err.Error() != "invalid sort" {
+		t.Fatalf("expected invalid sort, got: %v", err)
+	}
+}
+
 func TestListClassEnrollments_AutoMarksEndedEnrollmentsAsAttended(t *testing.T) {
 	setupClassServiceTestDB(t)
+	useCheckInSettings(t, CheckInSettings{Policy: model.AttendancePolicyAssumeAttended})
 
 	user := seedRoleAndUser(t, 1)
 	course := seedCourse(t, "Course A", 3, "Cardio")
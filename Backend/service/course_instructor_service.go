@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// ListCourseInstructors returns the lead and assistant instructors of a course.
+func ListCourseInstructors(courseID uint) ([]model.CourseInstructor, error) {
+	if _, err := dao.GetCourseByID(courseID); err != nil {
+		return nil, errors.New("class not found")
+	}
+	return dao.ListCourseInstructors(courseID)
+}
+
+// ManagerSetCourseInstructor assigns an instructor to a course as lead or assistant.
+// Re-assigning an instructor changes their role; a new lead replaces the previous one.
+// Newly assigned instructors are checked for double-booking and availability.
+func ManagerSetCourseInstructor(courseID uint, input model.CourseInstructorInput) (*model.CourseInstructor, error) {
+	course, err := dao.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+
+	instructor, err := dao.GetInstructorByID(input.InstructorID)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	teaches, err := courseBelongsToInstructor(course, instructor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !teaches {
+		if err := checkInstructorConflicts(course, []uint{instructor.ID}, input.AllowScheduleConflicts); err != nil {
+			return nil, err
+		}
+	}
+	return dao.SetCourseInstructor(courseID, instructor, input.Role)
+}
+
+// ManagerRemoveCourseInstructor unassigns an instructor from a course.
+func ManagerRemoveCourseInstructor(courseID uint, instructorID uint) error {
+	if _, err := dao.GetCourseByID(courseID); err != nil {
+		return errors.New("class not found")
+	}
+
+	removed, err := dao.RemoveCourseInstructor(courseID, instructorID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("instructor is not assigned to this class")
+	}
+	return nil
+}
+
+// ManagerSetSessionSubstitute assigns a substitute instructor to a scheduled session.
+func ManagerSetSessionSubstitute(sessionID uint, input model.SessionSubstituteInput) (*model.ClassSession, error) {
+	session, err := loadChangeableSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	instructor, err := dao.GetInstructorByID(input.InstructorID)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	teaches, err := courseBelongsToInstructor(&session.Course, instructor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if teaches {
+		return nil, errors.New("instructor already teaches this class")
+	}
+
+	if err := dao.SetSessionSubstitute(session.ID, &instructor.ID); err != nil {
+		return nil, err
+	}
+	session.SubstituteInstructorID = &instructor.ID
+	return session, nil
+}
+
+// ManagerClearSessionSubstitute removes the substitute of a session.
+func ManagerClearSessionSubstitute(sessionID uint) error {
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return errors.New("session not found")
+	}
+	if session.SubstituteInstructorID == nil {
+		return errors.New("session has no substitute")
+	}
+	return dao.SetSessionSubstitute(session.ID, nil)
+}
+
+// syncCourseLead records the course's lead instructor in the CourseInstructor relation.
+func syncCourseLead(tx *gorm.DB, course *model.Course) error {
+	if course.InstructorID == nil {
+		return nil
+	}
+	instructor := model.Instructor{ID: *course.InstructorID, Name: course.Instructor}
+	_, err := dao.SetCourseInstructorTx(tx, course.ID, &instructor, model.CourseInstructorRoleLead)
+	return err
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// buildCourseSlots validates the weekly meeting times of a course request.
+// Requests without slots describe a single meeting time through weekday/start_time/end_time;
+// a blank weekday means the course has no recurring schedule yet.
+func buildCourseSlots(input CourseUpsertInput) ([]model.CourseSlot, model.TimeOnly, model.TimeOnly, error) {
+	slotInputs := input.Slots
+	if len(slotInputs) == 0 {
+		if strings.TrimSpace(input.StartTime) == "" || strings.TrimSpace(input.EndTime) == "" {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("start_time and end_time are required")
+		}
+		start, err := model.ParseTimeOnly(input.StartTime)
+		if err != nil {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("invalid start_time, expected HH:MM or HH:MM:SS")
+		}
+		end, err := model.ParseTimeOnly(input.EndTime)
+		if err != nil {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("invalid end_time, expected HH:MM or HH:MM:SS")
+		}
+		if strings.TrimSpace(input.Weekday) == "" {
+			return nil, start, end, nil
+		}
+		slotInputs = []model.CourseSlotInput{{Weekday: input.Weekday, StartTime: input.StartTime, EndTime: input.EndTime}}
+	}
+
+	slots := make([]model.CourseSlot, 0, len(slotInputs))
+	seen := make(map[string]bool, len(slotInputs))
+	for _, in := range slotInputs {
+		day := normalizeWeekdayForGeneration(in.Weekday)
+		if _, ok := validWeekdays[day]; !ok {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("invalid weekday")
+		}
+		// ClassSession allows one session per course and date.
+		if seen[day] {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("each weekday may only have one slot")
+		}
+		seen[day] = true
+
+		start, err := model.ParseTimeOnly(in.StartTime)
+		if err != nil {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("invalid start_time, expected HH:MM or HH:MM:SS")
+		}
+		end, err := model.ParseTimeOnly(in.EndTime)
+		if err != nil {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("invalid end_time, expected HH:MM or HH:MM:SS")
+		}
+		if toMinutes(end) <= toMinutes(start) {
+			return nil, model.TimeOnly{}, model.TimeOnly{}, errors.New("end_time must be after start_time")
+		}
+
+		slots = append(slots, model.CourseSlot{
+			Weekday:   strings.TrimSpace(in.Weekday),
+			StartTime: start,
+			EndTime:   end,
+		})
+	}
+	return slots, slots[0].StartTime, slots[0].EndTime, nil
+}
+
+// applyCourseSlots sets the course's slots and mirrors the first one into Weekday/StartTime/EndTime.
+func applyCourseSlots(course *model.Course, slots []model.CourseSlot, start model.TimeOnly, end model.TimeOnly) {
+	course.Slots = slots
+	course.StartTime = start
+	course.EndTime = end
+	course.Weekday = ""
+	if len(slots) > 0 {
+		course.Weekday = slots[0].Weekday
+	}
+}
+
+// courseSlots returns the weekly slots of a course. Courses stored before CourseSlot existed
+// fall back to their single weekday/start/end slot.
+func courseSlots(course *model.Course) []model.CourseSlot {
+	if len(course.Slots) > 0 {
+		return course.Slots
+	}
+	if _, ok := validWeekdays[normalizeWeekdayForGeneration(course.Weekday)]; !ok {
+		return nil
+	}
+	return []model.CourseSlot{{
+		CourseID:  course.ID,
+		Weekday:   course.Weekday,
+		StartTime: course.StartTime,
+		EndTime:   course.EndTime,
+	}}
+}
+
+// hasCourseSchedule reports whether the course has at least one weekly slot to generate sessions from.
+func hasCourseSchedule(course *model.Course) bool {
+	return len(courseSlots(course)) > 0
+}
+
+// overlappingSlot returns the first slot of a that overlaps a slot of b on the same weekday.
+func overlappingSlot(a []model.CourseSlot, b []model.CourseSlot) (*model.CourseSlot, bool) {
+	for i := range a {
+		for j := range b {
+			if isSameWeekday(a[i].Weekday, b[j].Weekday) && timeRangesOverlap(a[i].StartTime, a[i].EndTime, b[j].StartTime, b[j].EndTime) {
+				return &a[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sameCourseSlots reports whether two schedules contain the same weekly slots, ignoring order.
+func sameCourseSlots(a []model.CourseSlot, b []model.CourseSlot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	type slotKey struct {
+		weekday    string
+		start, end int
+	}
+	key := func(s model.CourseSlot) slotKey {
+		return slotKey{normalizeWeekday(s.Weekday), toMinutes(s.StartTime), toMinutes(s.EndTime)}
+	}
+	counts := make(map[slotKey]int, len(a))
+	for _, s := range a {
+		counts[key(s)]++
+	}
+	for _, s := range b {
+		counts[key(s)]--
+		if counts[key(s)] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// nextSlotStart returns the earliest start of any slot at or after now, in now's location.
+func nextSlotStart(slots []model.CourseSlot, now time.Time) (time.Time, bool) {
+	weekdayMap := map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+
+	var next time.Time
+	found := false
+	for _, slot := range slots {
+		targetWeekday, ok := weekdayMap[normalizeWeekday(slot.Weekday)]
+		if !ok || slot.StartTime.Time.IsZero() {
+			continue
+		}
+
+		start := time.Date(now.Year(), now.Month(), now.Day(), slot.StartTime.Hour(), slot.StartTime.Minute(), 0, 0, now.Location())
+		daysAhead := (int(targetWeekday) - int(now.Weekday()) + 7) % 7
+		start = start.AddDate(0, 0, daysAhead)
+
+		// If today's slot already started, use next week's occurrence.
+		if daysAhead == 0 && now.After(start) {
+			start = start.AddDate(0, 0, 7)
+		}
+		if !found || start.Before(next) {
+			next, found = start, true
+		}
+	}
+	return next, found
+}
+
+// applyCourseTerm validates and sets the course term. Omitted bounds are kept and empty ones cleared.
+func applyCourseTerm(course *model.Course, input CourseUpsertInput) error {
+	if input.StartsOn != nil {
+		startsOn, err := parseTermDate(*input.StartsOn)
+		if err != nil {
+			return errors.New("invalid starts_on, expected YYYY-MM-DD")
+		}
+		course.StartsOn = startsOn
+	}
+	if input.EndsOn != nil {
+		endsOn, err := parseTermDate(*input.EndsOn)
+		if err != nil {
+			return errors.New("invalid ends_on, expected YYYY-MM-DD")
+		}
+		course.EndsOn = endsOn
+	}
+	if course.StartsOn != nil && course.EndsOn != nil && *course.EndsOn < *course.StartsOn {
+		return errors.New("ends_on must not be before starts_on")
+	}
+	return nil
+}
+
+// parseTermDate normalizes a YYYY-MM-DD term bound; a blank value clears the bound.
+func parseTermDate(value string) (*string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	day := date.Format("2006-01-02")
+	return &day, nil
+}
+
+// courseTermBounds returns the course term as YYYY-MM-DD strings; open ends are empty.
+func courseTermBounds(course *model.Course) (string, string) {
+	var startsOn, endsOn string
+	if course.StartsOn != nil {
+		startsOn = *course.StartsOn
+	}
+	if course.EndsOn != nil {
+		endsOn = *course.EndsOn
+	}
+	return startsOn, endsOn
+}
+
+// sessionsOutsideTerm returns the upcoming scheduled sessions that fall outside the course term.
+func sessionsOutsideTerm(course *model.Course) ([]model.ClassSession, error) {
+	startsOn, endsOn := courseTermBounds(course)
+	return dao.ListScheduledSessionsOutsideTerm(course.ID, startsOn, endsOn)
+}
+
+// cancelSessionsOutsideTerm cancels sessions found by sessionsOutsideTerm within tx.
+// These cancellations are made by the system and logged with actor ID 0.
+func cancelSessionsOutsideTerm(tx *gorm.DB, sessions []model.ClassSession) error {
+	for i := range sessions {
+		if _, err := cancelSessionTx(tx, 0, &sessions[i], "The session is outside the course term."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func TestManagerCreateCourse_MultipleWeeklySlots(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	input := CourseUpsertInput{
+		CourseName: "HIIT",
+		CourseCode: "HIIT-MWF",
+		Capacity:   10,
+		Slots: []model.CourseSlotInput{
+			{Weekday: "Monday", StartTime: "07:00", EndTime: "07:45"},
+			{Weekday: "Wednesday", StartTime: "07:00", EndTime: "07:45"},
+			{Weekday: "Friday", StartTime: "18:00", EndTime: "18:45"},
+		},
+	}
+
+	course, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if course.Weekday != "Monday" || course.StartTime.Format("15:04") != "07:00" {
+		t.Fatalf("expected the first slot mirrored on the course, got %s %s", course.Weekday, course.StartTime.Format("15:04"))
+	}
+
+	var sessions []model.ClassSession
+	db.DB.Where("course_id = ?", course.ID).Find(&sessions)
+	if len(sessions) != 3*DefaultSessionHorizonWeeks {
+		t.Fatalf("expected %d generated sessions, got %d", 3*DefaultSessionHorizonWeeks, len(sessions))
+	}
+	for _, s := range sessions {
+		switch s.StartAt.Weekday() {
+		case time.Monday, time.Wednesday:
+			if s.StartAt.Format("15:04") != "07:00" {
+				t.Fatalf("expected 07:00 session on %s, got %s", s.SessionDate, s.StartAt.Format("15:04"))
+			}
+		case time.Friday:
+			if s.StartAt.Format("15:04") != "18:00" {
+				t.Fatalf("expected 18:00 session on %s, got %s", s.SessionDate, s.StartAt.Format("15:04"))
+			}
+		default:
+			t.Fatalf("unexpected session on %s", s.SessionDate)
+		}
+	}
+
+	input.CourseCode = "HIIT-DUP"
+	input.Slots = append(input.Slots, model.CourseSlotInput{Weekday: "mon", StartTime: "19:00", EndTime: "20:00"})
+	if _, err := ManagerCreateCourse(input); err == nil || err.Error() != "each weekday may only have one slot" {
+		t.Fatalf("expected duplicate weekday error, got: %v", err)
+	}
+	input.Slots = []model.CourseSlotInput{{Weekday: "Tuesday", StartTime: "09:00", EndTime: "08:00"}}
+	if _, err := ManagerCreateCourse(input); err == nil || err.Error() != "end_time must be after start_time" {
+		t.Fatalf("expected slot time error, got: %v", err)
+	}
+
+	update := CourseUpsertInput{
+		CourseName: "HIIT",
+		CourseCode: "HIIT-MWF",
+		Capacity:   10,
+		Slots: []model.CourseSlotInput{
+			{Weekday: "Tuesday", StartTime: "07:00", EndTime: "07:45"},
+			{Weekday: "Thursday", StartTime: "07:00", EndTime: "07:45"},
+		},
+	}
+	if _, err := ManagerUpdateCourse(course.ID, update); err != nil {
+		t.Fatalf("failed to update course: %v", err)
+	}
+	stored, err := dao.GetCourseByID(course.ID)
+	if err != nil {
+		t.Fatalf("failed to load course: %v", err)
+	}
+	if len(stored.Slots) != 2 || stored.Slots[0].Weekday != "Tuesday" || stored.Weekday != "Tuesday" {
+		t.Fatalf("expected the two replacement slots, got %+v (weekday %q)", stored.Slots, stored.Weekday)
+	}
+}
+
+func TestValidateEnrollmentWindow_UsesNearestSlot(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC) // Monday
+	mustTime := func(v string) model.TimeOnly {
+		parsed, err := model.ParseTimeOnly(v)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", v, err)
+		}
+		return parsed
+	}
+
+	class := &model.Course{Slots: []model.CourseSlot{
+		{Weekday: "Friday", StartTime: mustTime("09:00"), EndTime: mustTime("10:00")},
+		{Weekday: "Tuesday", StartTime: mustTime("08:00"), EndTime: mustTime("09:00")},
+	}}
+	if err := validateEnrollmentWindow(class, &DefaultBookingPolicy, now); err != nil {
+		t.Fatalf("expected Tuesday's slot to be open for enrollment, got: %v", err)
+	}
+
+	class.Slots = class.Slots[:1]
+	if err := validateEnrollmentWindow(class, &DefaultBookingPolicy, now); err == nil || err.Error() != "enrollment opens 25 hours before class start." {
+		t.Fatalf("expected enrollment window error, got: %v", err)
+	}
+}
+
+func TestHasScheduleOverlap_ComparesEverySlot(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	existing := seedCourse(t, "Morning Yoga", 5, "Wellness")
+	seedEnrollmentAt(t, user.ID, existing.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	otherDay := time.Now().Add(2*time.Hour).AddDate(0, 0, 3).Weekday().String()
+	target := &model.Course{ID: existing.ID + 100, Slots: []model.CourseSlot{
+		{Weekday: otherDay, StartTime: existing.StartTime, EndTime: existing.EndTime},
+	}}
+	overlap, err := hasScheduleOverlap(user.ID, target)
+	if err != nil || overlap {
+		t.Fatalf("expected no overlap on another weekday, got %v (err %v)", overlap, err)
+	}
+
+	target.Slots = append(target.Slots, model.CourseSlot{Weekday: existing.Weekday, StartTime: existing.StartTime, EndTime: existing.EndTime})
+	overlap, err = hasScheduleOverlap(user.ID, target)
+	if err != nil || !overlap {
+		t.Fatalf("expected the second slot to overlap, got %v (err %v)", overlap, err)
+	}
+}
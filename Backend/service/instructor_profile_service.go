@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// InstructorDirectoryWindow is how far ahead the public directory lists an instructor's sessions.
+const InstructorDirectoryWindow = 28 * 24 * time.Hour
+
+// maxTagListLength caps the number of specialties, certifications or equipment items.
+const maxTagListLength = 20
+
+// GetMyInstructorProfile returns the profile of the signed-in instructor.
+func GetMyInstructorProfile(instructorID uint) (*model.Instructor, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	instructor, err := dao.GetInstructorByID(profileID)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+	return instructor, nil
+}
+
+// UpdateMyInstructorProfile updates the bio, photo, specialties and certifications of the signed-in instructor.
+func UpdateMyInstructorProfile(instructorID uint, input model.InstructorProfileInput) (*model.Instructor, error) {
+	instructor, err := GetMyInstructorProfile(instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Bio != nil {
+		instructor.Bio = strings.TrimSpace(*input.Bio)
+	}
+	if input.PhotoURL != nil {
+		instructor.PhotoURL = strings.TrimSpace(*input.PhotoURL)
+	}
+	if input.Specialties != nil {
+		specialties, err := normalizeTagList(*input.Specialties)
+		if err != nil {
+			return nil, errors.New("too many specialties")
+		}
+		instructor.Specialties = specialties
+	}
+	if input.Certifications != nil {
+		certifications, err := normalizeTagList(*input.Certifications)
+		if err != nil {
+			return nil, errors.New("too many certifications")
+		}
+		instructor.Certifications = certifications
+	}
+
+	if err := dao.UpdateInstructorProfile(instructor); err != nil {
+		return nil, err
+	}
+	return instructor, nil
+}
+
+// ListInstructorDirectory returns every active instructor with their upcoming courses and sessions.
+func ListInstructorDirectory() ([]model.InstructorDirectoryEntry, error) {
+	instructors, err := dao.ListActiveInstructors()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.InstructorDirectoryEntry, 0, len(instructors))
+	for i := range instructors {
+		entry, err := buildInstructorDirectoryEntry(&instructors[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// GetInstructorDirectoryEntry returns one instructor's public profile with their upcoming courses and sessions.
+func GetInstructorDirectoryEntry(id uint) (*model.InstructorDirectoryEntry, error) {
+	instructor, err := dao.GetInstructorByID(id)
+	if err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	user, err := dao.GetUserByID(instructor.UserID)
+	if err != nil || user.RoleID != 4 {
+		return nil, errors.New("instructor not found")
+	}
+	return buildInstructorDirectoryEntry(instructor)
+}
+
+func buildInstructorDirectoryEntry(instructor *model.Instructor) (*model.InstructorDirectoryEntry, error) {
+	sessions, err := dao.ListUpcomingInstructorSessions(instructor.ID, time.Now().Add(InstructorDirectoryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := dao.ListCoursesByInstructorID(instructor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillCourseSpots(courses); err != nil {
+		return nil, err
+	}
+
+	entry := &model.InstructorDirectoryEntry{
+		ID:             instructor.ID,
+		Name:           instructor.Name,
+		Bio:            instructor.Bio,
+		PhotoURL:       instructor.PhotoURL,
+		Specialties:    instructor.Specialties,
+		Certifications: instructor.Certifications,
+		Courses:        courses,
+		Sessions:       sessions,
+	}
+	if entry.Specialties == nil {
+		entry.Specialties = []string{}
+	}
+	if entry.Certifications == nil {
+		entry.Certifications = []string{}
+	}
+	return entry, nil
+}
+
+// normalizeTagList trims, drops empty and de-duplicates (case-insensitively) a list of free-form tags.
+func normalizeTagList(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTagListLength {
+		return nil, errors.New("too many tags")
+	}
+	return normalized, nil
+}
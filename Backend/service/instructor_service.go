@@ -4,38 +4,98 @@ import (
 	"errors"
 	"my-course-backend/dao"
 	"my-course-backend/model"
-	"strings"
 )
 
-// resolveInstructorName returns the instructor user's display name for authorization checks.
-func resolveInstructorName(instructorID uint) (string, error) {
+// resolveInstructorProfileID returns the Instructor profile ID of an instructor user for authorization checks.
+// Instructor users that predate their profile get one created on first use.
+func resolveInstructorProfileID(instructorID uint) (uint, error) {
 	user, err := dao.GetUserByID(instructorID)
 	if err != nil {
-		return "", errors.New("instructor not found")
+		return 0, errors.New("instructor not found")
 	}
-	return strings.TrimSpace(user.Name), nil
+	if err := dao.EnsureInstructorProfile(user.ID, user.Name); err != nil {
+		return 0, err
+	}
+
+	instructor, err := dao.GetInstructorByUserID(user.ID)
+	if err != nil {
+		return 0, errors.New("instructor not found")
+	}
+	return instructor.ID, nil
+}
+
+// courseBelongsToInstructor reports whether the instructor profile leads or assists the course.
+func courseBelongsToInstructor(course *model.Course, profileID uint) (bool, error) {
+	if course.InstructorID != nil && *course.InstructorID == profileID {
+		return true, nil
+	}
+	return dao.IsCourseInstructor(course.ID, profileID)
+}
+
+// courseAccess describes which sessions of a course an instructor may manage.
+// Course instructors see every session; substitutes only the sessions they cover.
+type courseAccess struct {
+	full       bool
+	sessionIDs []uint
 }
 
-// courseBelongsToInstructor compares the course's instructor name to the authenticated instructor's name.
-func courseBelongsToInstructor(course *model.Course, instructorName string) bool {
-	return strings.TrimSpace(course.Instructor) != "" &&
-		strings.EqualFold(strings.TrimSpace(course.Instructor), instructorName)
+func (a courseAccess) covers(sessionID uint) bool {
+	if a.full {
+		return true
+	}
+	for _, id := range a.sessionIDs {
+		if id == sessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCourseAccess loads a course and the instructor's access to it.
+// Returns "forbidden" when the instructor neither teaches the course nor covers any of its sessions.
+func resolveCourseAccess(instructorID uint, courseID uint) (*model.Course, courseAccess, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
+	if err != nil {
+		return nil, courseAccess{}, err
+	}
+
+	course, err := dao.GetCourseByID(courseID)
+	if err != nil {
+		return nil, courseAccess{}, errors.New("class not found")
+	}
+
+	owns, err := courseBelongsToInstructor(course, profileID)
+	if err != nil {
+		return nil, courseAccess{}, err
+	}
+	if owns {
+		return course, courseAccess{full: true}, nil
+	}
+
+	sessionIDs, err := dao.ListSubstituteSessionIDs(courseID, profileID)
+	if err != nil {
+		return nil, courseAccess{}, err
+	}
+	if len(sessionIDs) == 0 {
+		return nil, courseAccess{}, errors.New("forbidden")
+	}
+	return course, courseAccess{sessionIDs: sessionIDs}, nil
 }
 
 // InstructorAddEnrollment enrolls a user into a course taught by the instructor.
 // Instructors bypass the 25-hour enrollment window but still check ownership, duplicates, and capacity.
+// Substitutes may only enroll into the next session when they cover it.
 func InstructorAddEnrollment(instructorID, userID, courseID uint) error {
-	instructorName, err := resolveInstructorName(instructorID)
+	course, access, err := resolveCourseAccess(instructorID, courseID)
 	if err != nil {
 		return err
 	}
 
-	course, err := dao.GetCourseByID(courseID)
-	if err != nil {
-		return errors.New("class not found")
-	}
-	if !courseBelongsToInstructor(course, instructorName) {
-		return errors.New("forbidden")
+	if !access.full {
+		next, err := dao.GetNextScheduledSession(courseID)
+		if err != nil || !access.covers(next.ID) {
+			return errors.New("forbidden")
+		}
 	}
 
 	if _, err := dao.GetUserByID(userID); err != nil {
@@ -72,63 +132,190 @@ func InstructorAddEnrollment(instructorID, userID, courseID uint) error {
 	return dao.CreateEnrollment(&enrollment)
 }
 
-func ListInstructorCourses(instructorID uint) ([]model.Course, error) {
-	instructorName, err := resolveInstructorName(instructorID)
+// InstructorAddSessionEnrollment enrolls a user into a specific session of a course taught or covered by the instructor.
+func InstructorAddSessionEnrollment(instructorID, userID, courseID, sessionID uint) error {
+	_, access, err := resolveCourseAccess(instructorID, courseID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !access.covers(sessionID) {
+		return errors.New("forbidden")
 	}
-	return dao.ListCoursesByInstructorName(instructorName)
+
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	session, err := loadBookableSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.CourseID != courseID {
+		return errors.New("session not found")
+	}
+	_, err = bookSession(userID, session)
+	return err
 }
 
-func ListInstructorCourseEnrollments(instructorID uint, courseID uint) ([]model.Enrollment, error) {
-	instructorName, err := resolveInstructorName(instructorID)
+// ListInstructorCourses returns the courses the instructor leads or assists
+// and the upcoming sessions they cover as a substitute.
+func ListInstructorCourses(instructorID uint) ([]model.Course, []model.ClassSession, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if err := dao.SyncEndedEnrollmentsToAttended(); err != nil {
+
+	courses, err := dao.ListCoursesByInstructorID(profileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sessions, err := dao.ListUpcomingSubstituteSessions(profileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return courses, sessions, nil
+}
+
+// ListInstructorCourseEnrollments returns a course's enrollments; substitutes only see the sessions they cover.
+func ListInstructorCourseEnrollments(instructorID uint, courseID uint) ([]model.Enrollment, error) {
+	if err := syncEndedEnrollments(); err != nil {
 		return nil, err
 	}
 
-	course, err := dao.GetCourseByID(courseID)
+	_, access, err := resolveCourseAccess(instructorID, courseID)
 	if err != nil {
-		return nil, errors.New("class not found")
+		return nil, err
 	}
-	if !courseBelongsToInstructor(course, instructorName) {
-		return nil, errors.New("forbidden")
+	if !access.full {
+		return dao.ListEnrollmentsBySessions(access.sessionIDs)
 	}
 	return dao.ListEnrollmentsByInstructorCourse(courseID)
 }
 
-func UpdateEnrollmentStatusByInstructor(instructorID, courseID, userID uint, status string) error {
+// UpdateEnrollmentStatusByInstructor sets a user's enrollment status in a course.
+// When sessionID is set only that session is updated; substitutes are limited to the sessions they cover.
+func UpdateEnrollmentStatusByInstructor(instructorID, courseID, userID uint, status string, sessionID *uint) error {
 	if status != "attended" && status != "missed" && status != "enrolled" {
 		return errors.New("invalid status")
 	}
 
-	instructorName, err := resolveInstructorName(instructorID)
+	_, access, err := resolveCourseAccess(instructorID, courseID)
 	if err != nil {
 		return err
 	}
 
-	course, err := dao.GetCourseByID(courseID)
-	if err != nil {
-		return errors.New("class not found")
-	}
-
-	if !courseBelongsToInstructor(course, instructorName) {
-		return errors.New("forbidden")
-	}
-
 	// Verify user enrolled in this course
 	if _, err := dao.GetEnrollment(userID, courseID); err != nil {
 		return errors.New("enrollment not found")
 	}
 
-	ok, err := dao.UpdateEnrollmentStatus(userID, courseID, status)
+	var ok bool
+	var sessionIDs []uint
+	switch {
+	case sessionID != nil:
+		if !access.covers(*sessionID) {
+			return errors.New("forbidden")
+		}
+		sessionIDs = []uint{*sessionID}
+		ok, err = dao.UpdateSessionEnrollmentStatus(userID, sessionIDs, status)
+	case !access.full:
+		sessionIDs = access.sessionIDs
+		ok, err = dao.UpdateSessionEnrollmentStatus(userID, sessionIDs, status)
+	default:
+		ok, err = dao.UpdateEnrollmentStatus(userID, courseID, status)
+	}
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return errors.New("enrollment not found")
 	}
-	return nil
+
+	// Missed enrollments count as no-show strikes; corrected ones lose theirs.
+	return syncNoShowStrikes(userID, courseID, sessionIDs)
+}
+
+// InstructorMarkSessionAttendance records attendance for several members of one session the instructor teaches
+// or covers. Each mark only touches that member's enrollment in this session. Valid marks are applied together
+// in one transaction; marks for unknown members, repeated members or invalid statuses are rejected individually.
+func InstructorMarkSessionAttendance(instructorID uint, sessionID uint, marks []model.AttendanceMark) ([]model.AttendanceMarkResult, error) {
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	_, access, err := resolveCourseAccess(instructorID, session.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.covers(session.ID) {
+		return nil, errors.New("forbidden")
+	}
+	if session.Status == "canceled" {
+		return nil, errors.New("session is canceled")
+	}
+
+	enrollments, err := dao.ListMarkableSessionEnrollments(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint]*model.Enrollment, len(enrollments))
+	for i := range enrollments {
+		byUser[enrollments[i].UserID] = &enrollments[i]
+	}
+
+	results := make([]model.AttendanceMarkResult, len(marks))
+	statuses := make(map[uint]string)
+	var changed []*model.Enrollment
+	seen := make(map[uint]bool, len(marks))
+	for i, mark := range marks {
+		result := &results[i]
+		result.UserID = mark.UserID
+		result.Status = mark.Status
+		result.Result = model.AttendanceResultRejected
+
+		enrollment, ok := byUser[mark.UserID]
+		switch {
+		case mark.Status != model.EnrollmentStatusAttended && mark.Status != model.EnrollmentStatusMissed &&
+			mark.Status != model.EnrollmentStatusEnrolled:
+			result.Error = "invalid status"
+		case seen[mark.UserID]:
+			result.Error = "duplicate user"
+		case !ok:
+			result.Error = "enrollment not found"
+		default:
+			result.EnrollmentID = enrollment.ID
+			if enrollment.Status == mark.Status {
+				result.Result = model.AttendanceResultUnchanged
+			} else {
+				result.Result = model.AttendanceResultUpdated
+				statuses[enrollment.ID] = mark.Status
+				enrollment.Status = mark.Status
+				changed = append(changed, enrollment)
+			}
+		}
+		seen[mark.UserID] = true
+	}
+
+	if len(statuses) > 0 {
+		if err := dao.UpdateSessionAttendance(session.ID, statuses); err != nil {
+			return nil, err
+		}
+	}
+
+	// Missed enrollments count as no-show strikes; corrected ones lose theirs.
+	var corrected []uint
+	for _, enrollment := range changed {
+		if enrollment.Status != model.EnrollmentStatusMissed {
+			corrected = append(corrected, enrollment.ID)
+			continue
+		}
+		if err := addNoShowStrike(enrollment); err != nil {
+			return nil, err
+		}
+	}
+	if err := dao.DeleteNoShowStrikes(corrected); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
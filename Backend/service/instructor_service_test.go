@@ -0,0 +1,295 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func seedInstructorProfile(t *testing.T, user model.User) model.Instructor {
+	t.Helper()
+
+	profile := model.Instructor{UserID: user.ID, Name: user.Name}
+	if err := db.DB.Where("user_id = ?", user.ID).FirstOrCreate(&profile).Error; err != nil {
+		t.Fatalf("failed to seed instructor profile: %v", err)
+	}
+	return profile
+}
+
+func TestManagerCreateCourse_LinksInstructorByID(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	instructor := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, instructor)
+
+	input := CourseUpsertInput{
+		CourseName: "Yoga",
+		CourseCode: "YOGA-1",
+		StartTime:  "08:00",
+		EndTime:    "09:00",
+		Capacity:   10,
+	}
+
+	missing := uint(9999)
+	input.InstructorID = &missing
+	if _, err := ManagerCreateCourse(input); err == nil || err.Error() != "instructor not found" {
+		t.Fatalf("expected instructor not found, got: %v", err)
+	}
+
+	input.InstructorID = &profile.ID
+	course, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if course.InstructorID == nil || *course.InstructorID != profile.ID || course.Instructor != instructor.Name {
+		t.Fatalf("expected course linked to instructor %d, got %+v", profile.ID, course)
+	}
+
+	courses, _, err := ListInstructorCourses(instructor.ID)
+	if err != nil {
+		t.Fatalf("failed to list instructor courses: %v", err)
+	}
+	if len(courses) != 1 || courses[0].ID != course.ID {
+		t.Fatalf("expected instructor to see course %d, got %+v", course.ID, courses)
+	}
+}
+
+func TestInstructorOwnership_UsesIDNotName(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	owner := seedRoleAndUser(t, 4)
+	namesake := seedUserWithRole(t, 4)
+	db.DB.Model(&model.User{}).Where("id = ?", namesake.ID).Update("name", owner.Name)
+	profile := seedInstructorProfile(t, owner)
+
+	course := seedCourse(t, "Spin", 5, "Cardio")
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Updates(map[string]interface{}{
+		"instructor_id": profile.ID,
+		"instructor":    owner.Name,
+	})
+
+	if _, err := ListInstructorCourseEnrollments(namesake.ID, course.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden for an instructor sharing the name, got: %v", err)
+	}
+
+	renamed := "Renamed Coach"
+	if err := UpdateUserProfilePatch(owner.ID, model.UserProfilePatch{
+		Name: model.PatchString{Set: true, Valid: true, Value: renamed},
+	}); err != nil {
+		t.Fatalf("failed to rename instructor: %v", err)
+	}
+
+	if _, err := ListInstructorCourseEnrollments(owner.ID, course.ID); err != nil {
+		t.Fatalf("expected renamed instructor to keep access, got error: %v", err)
+	}
+
+	reloaded := model.Course{}
+	db.DB.First(&reloaded, course.ID)
+	if reloaded.Instructor != renamed {
+		t.Fatalf("expected course display name %q, got %q", renamed, reloaded.Instructor)
+	}
+}
+
+func TestCourseInstructors_AssistantGetsCourseAccess(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	lead := seedRoleAndUser(t, 4)
+	assistant := seedUserWithRole(t, 4)
+	leadProfile := seedInstructorProfile(t, lead)
+	assistantProfile := seedInstructorProfile(t, assistant)
+
+	course, err := ManagerCreateCourse(CourseUpsertInput{
+		CourseName:   "Pilates",
+		CourseCode:   "PIL-1",
+		StartTime:    "08:00",
+		EndTime:      "09:00",
+		Capacity:     10,
+		InstructorID: &leadProfile.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+
+	if _, err := ListInstructorCourseEnrollments(assistant.ID, course.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden before assignment, got: %v", err)
+	}
+
+	if _, err := ManagerSetCourseInstructor(course.ID, model.CourseInstructorInput{
+		InstructorID: assistantProfile.ID,
+		Role:         model.CourseInstructorRoleAssistant,
+	}); err != nil {
+		t.Fatalf("failed to assign assistant: %v", err)
+	}
+
+	assignments, err := ListCourseInstructors(course.ID)
+	if err != nil {
+		t.Fatalf("failed to list course instructors: %v", err)
+	}
+	if len(assignments) != 2 || assignments[0].InstructorID != leadProfile.ID || assignments[0].Role != model.CourseInstructorRoleLead {
+		t.Fatalf("expected lead first then assistant, got %+v", assignments)
+	}
+
+	if _, err := ListInstructorCourseEnrollments(assistant.ID, course.ID); err != nil {
+		t.Fatalf("expected assistant access, got error: %v", err)
+	}
+	courses, _, err := ListInstructorCourses(assistant.ID)
+	if err != nil || len(courses) != 1 || courses[0].ID != course.ID {
+		t.Fatalf("expected assistant to list course %d, got %+v (err %v)", course.ID, courses, err)
+	}
+
+	if err := ManagerRemoveCourseInstructor(course.ID, assistantProfile.ID); err != nil {
+		t.Fatalf("failed to remove assistant: %v", err)
+	}
+	if _, err := ListInstructorCourseEnrollments(assistant.ID, course.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden after removal, got: %v", err)
+	}
+}
+
+func TestSessionSubstitute_SeesOnlyCoveredSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	student := seedRoleAndUser(t, 1)
+	other := seedUserWithRole(t, 1)
+	substitute := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, substitute)
+
+	course := seedCourse(t, "Boxing", 5, "Combat")
+	covered := seedSessionAt(t, course, time.Now().Add(72*time.Hour), 5)
+	uncovered := seedSessionAt(t, course, time.Now().Add(96*time.Hour), 5)
+	seedEnrollmentForSession(t, student.ID, course.ID, covered.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, other.ID, course.ID, uncovered.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if _, err := ManagerSetSessionSubstitute(covered.ID, model.SessionSubstituteInput{InstructorID: profile.ID}); err != nil {
+		t.Fatalf("failed to assign substitute: %v", err)
+	}
+
+	enrollments, err := ListInstructorCourseEnrollments(substitute.ID, course.ID)
+	if err != nil {
+		t.Fatalf("expected substitute access, got error: %v", err)
+	}
+	if len(enrollments) != 1 || enrollments[0].UserID != student.ID {
+		t.Fatalf("expected only the covered session's enrollment, got %+v", enrollments)
+	}
+
+	if err := InstructorAddSessionEnrollment(substitute.ID, other.ID, course.ID, uncovered.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden for an uncovered session, got: %v", err)
+	}
+	if err := UpdateEnrollmentStatusByInstructor(substitute.ID, course.ID, other.ID, "attended", nil); err == nil || err.Error() != "enrollment not found" {
+		t.Fatalf("expected substitute not to reach uncovered enrollments, got: %v", err)
+	}
+
+	courses, sessions, err := ListInstructorCourses(substitute.ID)
+	if err != nil {
+		t.Fatalf("failed to list instructor courses: %v", err)
+	}
+	if len(courses) != 0 || len(sessions) != 1 || sessions[0].ID != covered.ID {
+		t.Fatalf("expected one covered session and no courses, got courses=%+v sessions=%+v", courses, sessions)
+	}
+
+	if err := ManagerClearSessionSubstitute(covered.ID); err != nil {
+		t.Fatalf("failed to clear substitute: %v", err)
+	}
+	if _, err := ListInstructorCourseEnrollments(substitute.ID, course.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden after clearing substitute, got: %v", err)
+	}
+}
+
+func TestAssignUserRole_CreatesInstructorProfile(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	student := seedRoleAndUser(t, 1)
+	db.DB.Create(&model.Role{ID: 4, RoleName: "Instructor"})
+
+	if err := AssignUserRole(student.ID, "Instructor"); err != nil {
+		t.Fatalf("failed to promote user: %v", err)
+	}
+
+	var profile model.Instructor
+	if err := db.DB.Where("user_id = ?", student.ID).First(&profile).Error; err != nil {
+		t.Fatalf("expected instructor profile, got error: %v", err)
+	}
+	if profile.UserID != student.ID || profile.Name != student.Name {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+
+	entries, err := ListInstructorDirectory()
+	if err != nil || len(entries) != 1 || entries[0].ID != profile.ID {
+		t.Fatalf("expected promoted instructor in directory, got %+v (err %v)", entries, err)
+	}
+}
+
+func TestInstructorMarkSessionAttendance_OnlyTouchesThatSession(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	teacher := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, teacher)
+	stranger := seedUserWithRole(t, 4)
+	seedInstructorProfile(t, stranger)
+	alice := seedRoleAndUser(t, 1)
+	bob := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Pilates", 5, "Core")
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Update("instructor_id", profile.ID)
+
+	past := seedPastSession(t, course, 1)
+	next := seedSessionAt(t, course, time.Now().Add(48*time.Hour), 5)
+	alicePast := seedEnrollmentForSession(t, alice.ID, course.ID, past.ID, model.EnrollmentStatusEnrolled, time.Now().AddDate(0, 0, -2))
+	aliceNext := seedEnrollmentForSession(t, alice.ID, course.ID, next.ID, model.EnrollmentStatusEnrolled, time.Now())
+	bobPast := seedEnrollmentForSession(t, bob.ID, course.ID, past.ID, model.EnrollmentStatusAttended, time.Now().AddDate(0, 0, -2))
+
+	marks := []model.AttendanceMark{
+		{UserID: alice.ID, Status: model.EnrollmentStatusMissed},
+		{UserID: bob.ID, Status: model.EnrollmentStatusAttended},
+		{UserID: 9999, Status: model.EnrollmentStatusAttended},
+		{UserID: alice.ID, Status: model.EnrollmentStatusAttended},
+		{UserID: bob.ID, Status: "late"},
+	}
+	if _, err := InstructorMarkSessionAttendance(stranger.ID, past.ID, marks); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden for another instructor, got %v", err)
+	}
+
+	results, err := InstructorMarkSessionAttendance(teacher.ID, past.ID, marks)
+	if err != nil {
+		t.Fatalf("expected bulk marking to succeed, got %v", err)
+	}
+	want := []struct{ result, err string }{
+		{model.AttendanceResultUpdated, ""},
+		{model.AttendanceResultUnchanged, ""},
+		{model.AttendanceResultRejected, "enrollment not found"},
+		{model.AttendanceResultRejected, "duplicate user"},
+		{model.AttendanceResultRejected, "invalid status"},
+	}
+	for i, w := range want {
+		if results[i].Result != w.result || results[i].Error != w.err {
+			t.Fatalf("row %d: expected %s %q, got %+v", i, w.result, w.err, results[i])
+		}
+	}
+	if results[0].EnrollmentID != alicePast.ID {
+		t.Fatalf("expected row 0 to report enrollment %d, got %d", alicePast.ID, results[0].EnrollmentID)
+	}
+
+	if got := loadEnrollmentByID(t, alicePast.ID); got.Status != model.EnrollmentStatusMissed {
+		t.Fatalf("expected the past seat missed, got %s", got.Status)
+	}
+	if got := loadEnrollmentByID(t, aliceNext.ID); got.Status != model.EnrollmentStatusEnrolled {
+		t.Fatalf("expected the other session untouched, got %s", got.Status)
+	}
+	if got := loadEnrollmentByID(t, bobPast.ID); got.Status != model.EnrollmentStatusAttended {
+		t.Fatalf("expected bob still attended, got %s", got.Status)
+	}
+	strikes, _ := dao.ListStrikesByUser(alice.ID)
+	if len(strikes) != 1 {
+		t.Fatalf("expected one no-show strike, got %+v", strikes)
+	}
+
+	if _, err := InstructorMarkSessionAttendance(teacher.ID, past.ID, []model.AttendanceMark{
+		{UserID: alice.ID, Status: model.EnrollmentStatusAttended},
+	}); err != nil {
+		t.Fatalf("expected the correction to succeed, got %v", err)
+	}
+	if strikes, _ := dao.ListStrikesByUser(alice.ID); len(strikes) != 0 {
+		t.Fatalf("expected the corrected no-show strike removed, got %+v", strikes)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"log"
 	"strings"
 	"time"
 
@@ -18,8 +19,9 @@ type CourseUpsertInput struct {
 	CourseCode  string `json:"course_code" binding:"required"`
 	Description string `json:"description"`
 
-	StartTime string `json:"start_time" binding:"required"` // "08:00" or "08:00:00"
-	EndTime   string `json:"end_time" binding:"required"`   // "09:00" or "09:00:00"
+	// StartTime, EndTime and Weekday describe a single weekly meeting time; they are ignored when Slots is set.
+	StartTime string `json:"start_time"` // "08:00" or "08:00:00"
+	EndTime   string `json:"end_time"`   // "09:00" or "09:00:00"
 
 	Capacity int `json:"capacity" binding:"required,min=1"`
 
@@ -27,37 +29,87 @@ type CourseUpsertInput struct {
 	Duration int    `json:"duration" binding:"omitempty,min=0"`
 	Category string `json:"category"`
 	Weekday  string `json:"weekday"`
+
+	// Slots lists the weekly meeting times of the course, at most one per weekday.
+	Slots []model.CourseSlotInput `json:"slots" binding:"omitempty,dive"`
+
+	// StartsOn and EndsOn bound the course term (YYYY-MM-DD). On update, omitting one keeps it and "" clears it.
+	StartsOn *string `json:"starts_on"`
+	EndsOn   *string `json:"ends_on"`
+
+	// InstructorID is the Instructor profile teaching the course. On update, omitting it keeps the current instructor.
+	InstructorID *uint `json:"instructor_id"`
+
+	// RoomID is the studio the course is held in. On update, omitting it keeps the current room.
+	RoomID *uint `json:"room_id"`
+
+	// BookingPolicyID attaches a booking policy to the course. On update, omitting it keeps the current policy and 0 detaches it.
+	BookingPolicyID *uint `json:"booking_policy_id"`
+
+	// AllowScheduleConflicts saves the course even if it double-books an instructor or falls outside
+	// their availability; the conflicts are returned as schedule_warnings instead.
+	AllowScheduleConflicts bool `json:"allow_schedule_conflicts"`
 }
 
 // ManagerCreateCourse creates a course (manager role required at API layer).
 // ManagerCreateCourse creates a course (manager role required at API layer).
 func ManagerCreateCourse(input CourseUpsertInput) (*model.Course, error) {
-	start, err := model.ParseTimeOnly(input.StartTime)
+	slots, start, end, err := buildCourseSlots(input)
 	if err != nil {
-		return nil, errors.New("invalid start_time, expected HH:MM or HH:MM:SS")
-	}
-	end, err := model.ParseTimeOnly(input.EndTime)
-	if err != nil {
-		return nil, errors.New("invalid end_time, expected HH:MM or HH:MM:SS")
+		return nil, err
 	}
 
 	course := &model.Course{
 		CourseName:  input.CourseName,
 		CourseCode:  input.CourseCode,
 		Description: input.Description,
-		StartTime:   start,
-		EndTime:     end,
 		Capacity:    input.Capacity,
 		Duration:    input.Duration,
 		Category:    input.Category,
-		Weekday:     input.Weekday,
+	}
+	applyCourseSlots(course, slots, start, end)
+	if err := applyCourseTerm(course, input); err != nil {
+		return nil, err
+	}
+
+	if input.InstructorID != nil {
+		if err := assignCourseInstructor(course, *input.InstructorID); err != nil {
+			return nil, err
+		}
+		if err := checkInstructorConflicts(course, []uint{*course.InstructorID}, input.AllowScheduleConflicts); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := dao.CreateCourse(course); err != nil {
+	if input.RoomID != nil {
+		if err := assignCourseRoom(course, *input.RoomID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkCourseRoom(course); err != nil {
+		return nil, err
+	}
+
+	if input.BookingPolicyID != nil {
+		if err := assignCourseBookingPolicy(course, *input.BookingPolicyID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Plan the initial ClassSession rows so the course is bookable right away, and write them with
+	// the course in one transaction. Courses without a weekly slot get sessions once one is set.
+	var sessions []model.ClassSession
+	if hasCourseSchedule(course) {
+		if sessions, err = planClassSessions(course, DefaultSessionHorizonWeeks); err != nil {
+			return nil, err
+		}
+	}
+	if err := dao.CreateCourseWithSchedule(course, course.Slots, sessions); err != nil {
 		return nil, err
 	}
 
 	_ = fillCourseSpot(course)
+	_ = fillCourseRoom(course)
 	return course, nil
 }
 
@@ -68,39 +120,164 @@ func ManagerUpdateCourse(id uint, input CourseUpsertInput) (*model.Course, error
 		return nil, errors.New("class not found")
 	}
 
-	start, err := model.ParseTimeOnly(input.StartTime)
+	slots, start, end, err := buildCourseSlots(input)
 	if err != nil {
-		return nil, errors.New("invalid start_time, expected HH:MM or HH:MM:SS")
-	}
-	end, err := model.ParseTimeOnly(input.EndTime)
-	if err != nil {
-		return nil, errors.New("invalid end_time, expected HH:MM or HH:MM:SS")
+		return nil, err
 	}
 
+	previousCapacity := course.Capacity
+	previous := *course
+	previous.Slots = courseSlots(course)
+
 	course.CourseName = input.CourseName
 	course.CourseCode = input.CourseCode
 	course.Description = input.Description
-	course.StartTime = start
-	course.EndTime = end
 	course.Capacity = input.Capacity
 	course.Duration = input.Duration
 	course.Category = input.Category
-	course.Weekday = input.Weekday
+	applyCourseSlots(course, slots, start, end)
+	if err := applyCourseTerm(course, input); err != nil {
+		return nil, err
+	}
+
+	if input.InstructorID != nil {
+		if err := assignCourseInstructor(course, *input.InstructorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.RoomID != nil {
+		if err := assignCourseRoom(course, *input.RoomID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkCourseRoom(course); err != nil {
+		return nil, err
+	}
+
+	if input.BookingPolicyID != nil {
+		if err := assignCourseBookingPolicy(course, *input.BookingPolicyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if courseScheduleChanged(&previous, course) {
+		instructorIDs, err := courseInstructorIDs(course)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkInstructorConflicts(course, instructorIDs, input.AllowScheduleConflicts); err != nil {
+			return nil, err
+		}
+	}
+
+	// A capacity increase opens seats for waitlisted members.
+	var promotions []waitlistPromotion
+	if course.Capacity > previousCapacity {
+		if promotions, err = planCourseWaitlistPromotions(course, previousCapacity); err != nil {
+			return nil, err
+		}
+	}
+
+	// A shortened term cancels the sessions that now fall outside it.
+	outsideTerm, err := sessionsOutsideTerm(course)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := dao.UpdateCourse(course); err != nil {
+	// Regenerate ClassSession rows for the session horizon.
+	var sessions []model.ClassSession
+	if hasCourseSchedule(course) {
+		if sessions, err = planClassSessions(course, DefaultSessionHorizonWeeks); err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the course and everything that follows from it in one transaction, so a failure leaves
+	// the course as it was.
+	err = dao.WithTx(func(tx *gorm.DB) error {
+		if err := dao.UpdateCourseTx(tx, course); err != nil {
+			return err
+		}
+		if err := dao.ReplaceCourseSlotsTx(tx, course.ID, course.Slots); err != nil {
+			return err
+		}
+		if input.InstructorID != nil {
+			if err := syncCourseLead(tx, course); err != nil {
+				return err
+			}
+		}
+		if course.Capacity != previousCapacity {
+			if err := dao.UpdateScheduledSessionCapacityTx(tx, course.ID, previousCapacity, course.Capacity); err != nil {
+				return err
+			}
+		}
+		for _, promotion := range promotions {
+			if _, err := dao.PromoteWaitlistedEnrollmentsTx(tx, promotion.session.ID, sessionCapacity(promotion.session), promotion.passedOver); err != nil {
+				return err
+			}
+		}
+		if err := cancelSessionsOutsideTerm(tx, outsideTerm); err != nil {
+			return err
+		}
+		return dao.CreateClassSessionsTx(tx, sessions)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Regenerate ClassSession rows for the next 12 weeks
-	if err := GenerateClassSessions(course.ID, 12); err != nil {
-		// Log but don't fail the course update
-		_ = errors.New("warning: failed to regenerate class sessions: " + err.Error())
+	// Extend active series bookings onto the regenerated weeks.
+	if err := SyncCourseBookingSeries(course.ID); err != nil {
+		log.Printf("failed to sync booking series for course %d: %v", course.ID, err)
 	}
 
 	_ = fillCourseSpot(course)
+	_ = fillCourseRoom(course)
 	return course, nil
 }
 
+// assignCourseInstructor links a course to an existing Instructor profile and copies its display name.
+func assignCourseInstructor(course *model.Course, instructorID uint) error {
+	instructor, err := dao.GetInstructorByID(instructorID)
+	if err != nil {
+		return errors.New("instructor not found")
+	}
+	course.InstructorID = &instructor.ID
+	course.Instructor = instructor.Name
+	return nil
+}
+
+// courseScheduleChanged reports whether an update changes the course slots or its lead instructor.
+func courseScheduleChanged(before *model.Course, after *model.Course) bool {
+	if !sameCourseSlots(courseSlots(before), courseSlots(after)) {
+		return true
+	}
+	if after.InstructorID == nil {
+		return false
+	}
+	return before.InstructorID == nil || *before.InstructorID != *after.InstructorID
+}
+
+// courseInstructorIDs returns the lead and assistant instructors that will teach the course.
+// A lead being replaced by this update is left out.
+func courseInstructorIDs(course *model.Course) ([]uint, error) {
+	var ids []uint
+	if course.InstructorID != nil {
+		ids = append(ids, *course.InstructorID)
+	}
+
+	assignments, err := dao.ListCourseInstructors(course.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		if a.Role == model.CourseInstructorRoleAssistant && (course.InstructorID == nil || a.InstructorID != *course.InstructorID) {
+			ids = append(ids, a.InstructorID)
+		}
+	}
+	return ids, nil
+}
+
 // ManagerDeleteCourse deletes a course by ID.
 func ManagerDeleteCourse(id uint) error {
 	if _, err := dao.GetCourseByID(id); err != nil {
@@ -246,7 +423,33 @@ func ManagerAddUserEnrollment(userID uint, courseID uint) error {
 	return dao.CreateEnrollment(enrollment)
 }
 
+// ManagerAddUserSessionEnrollment enrolls a user in a specific session.
+// Managers bypass the 25-hour enrollment window but still check duplicates and capacity.
+func ManagerAddUserSessionEnrollment(userID uint, sessionID uint) error {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	session, err := loadBookableSession(sessionID)
+	if err != nil {
+		return err
+	}
+	_, err = bookSession(userID, session)
+	return err
+}
+
 // ✅ Manager: 删除用户课程
-func ManagerDeleteUserEnrollment(userID uint, courseID uint) error {
-	return dao.DeleteEnrollment(userID, courseID)
+// Any credit the booking spent is refunded and a freed seat is handed to the first person on the session's waitlist.
+func ManagerDeleteUserEnrollment(managerID uint, userID uint, courseID uint) error {
+	removed, err := dao.DeleteEnrollment(userID, courseID)
+	if err != nil {
+		return err
+	}
+	if err := refundBooking(removed, managerID, "removed by a manager"); err != nil {
+		return err
+	}
+	if err := skipSeriesSession(removed); err != nil {
+		return err
+	}
+	return releaseSeat(removed)
 }
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// BookingCodeMembershipRequired refuses a booking when the member has neither an active unlimited plan nor a credit.
+const BookingCodeMembershipRequired = "membership_required"
+
+var membershipRequired = true
+
+// SetMembershipRequired turns membership enforcement on or off. While off, bookings neither need
+// nor spend credits; charges already on the ledger are still refunded.
+func SetMembershipRequired(required bool) {
+	membershipRequired = required
+}
+
+// ListMembershipPlans returns membership plans; activeOnly hides retired ones.
+func ListMembershipPlans(activeOnly bool) ([]model.MembershipPlan, error) {
+	return dao.ListMembershipPlans(activeOnly)
+}
+
+// ManagerCreateMembershipPlan creates a membership plan.
+func ManagerCreateMembershipPlan(input model.MembershipPlanInput) (*model.MembershipPlan, error) {
+	plan := &model.MembershipPlan{Active: true}
+	if err := applyMembershipPlanInput(plan, input); err != nil {
+		return nil, err
+	}
+	if err := dao.CreateMembershipPlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ManagerUpdateMembershipPlan replaces a membership plan's settings. Existing subscriptions keep
+// the credits they were granted.
+func ManagerUpdateMembershipPlan(id uint, input model.MembershipPlanInput) (*model.MembershipPlan, error) {
+	plan, err := dao.GetMembershipPlanByID(id)
+	if err != nil {
+		return nil, errors.New("membership plan not found")
+	}
+	if err := applyMembershipPlanInput(plan, input); err != nil {
+		return nil, err
+	}
+	if err := dao.UpdateMembershipPlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func applyMembershipPlanInput(plan *model.MembershipPlan, input model.MembershipPlanInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("membership plan name is required")
+	}
+	switch input.Kind {
+	case model.PlanKindUnlimited:
+		if input.Credits != 0 {
+			return errors.New("unlimited plans do not grant credits")
+		}
+	case model.PlanKindClassPack, model.PlanKindDropIn:
+		if input.Credits <= 0 {
+			return errors.New("class packs and drop-ins must grant credits")
+		}
+	default:
+		return errors.New("invalid plan kind")
+	}
+
+	taken, err := dao.CheckMembershipPlanNameTaken(name, plan.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("membership plan name already exists")
+	}
+
+	plan.Name = name
+	plan.Kind = input.Kind
+	plan.Credits = input.Credits
+	plan.DurationDays = input.DurationDays
+	plan.PriceCents = input.PriceCents
+	if input.Active != nil {
+		plan.Active = *input.Active
+	}
+	return nil
+}
+
+// GetMemberBalance returns the member's credit balance, subscriptions and ledger.
+func GetMemberBalance(userID uint) (*model.MemberBalance, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	credits, err := dao.GetCreditBalance(userID)
+	if err != nil {
+		return nil, err
+	}
+	unlimited, err := dao.HasActiveUnlimitedSubscription(userID, model.FacilityToday())
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := dao.ListMemberSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := dao.ListCreditLedger(userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []model.MemberSubscription{}
+	}
+	if ledger == nil {
+		ledger = []model.CreditLedgerEntry{}
+	}
+
+	return &model.MemberBalance{
+		UserID:        userID,
+		Credits:       credits,
+		Unlimited:     unlimited,
+		Subscriptions: subscriptions,
+		Ledger:        ledger,
+	}, nil
+}
+
+// ManagerGrantSubscription gives a member a plan starting on input.StartsOn (today when empty).
+// Class packs and drop-ins credit the plan's credits to the member's balance.
+func ManagerGrantSubscription(managerID uint, userID uint, input model.SubscriptionGrantInput) (*model.MemberSubscription, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	plan, err := dao.GetMembershipPlanByID(input.PlanID)
+	if err != nil {
+		return nil, errors.New("membership plan not found")
+	}
+	if !plan.Active {
+		return nil, errors.New("membership plan is inactive")
+	}
+
+	startsOn := strings.TrimSpace(input.StartsOn)
+	if startsOn == "" {
+		startsOn = model.FacilityToday()
+	}
+	start, err := time.Parse("2006-01-02", startsOn)
+	if err != nil {
+		return nil, errors.New("invalid starts_on")
+	}
+
+	subscription, grant := newSubscription(userID, plan, start, &managerID, managerID, input.Note)
+	if err := dao.CreateMemberSubscription(subscription, grant); err != nil {
+		return nil, err
+	}
+	subscription.Plan = *plan
+	return subscription, nil
+}
+
+// newSubscription builds a subscription to plan starting on start and, for credit plans, the ledger
+// entry granting its credits. grantedBy is nil for purchases; actorID is recorded on the grant.
+func newSubscription(userID uint, plan *model.MembershipPlan, start time.Time, grantedBy *uint, actorID uint, note string) (*model.MemberSubscription, *model.CreditLedgerEntry) {
+	subscription := &model.MemberSubscription{
+		UserID:    userID,
+		PlanID:    plan.ID,
+		StartsOn:  start.Format("2006-01-02"),
+		Status:    model.SubscriptionStatusActive,
+		GrantedBy: grantedBy,
+	}
+	if plan.DurationDays > 0 {
+		endsOn := start.AddDate(0, 0, plan.DurationDays-1).Format("2006-01-02")
+		subscription.EndsOn = &endsOn
+	}
+
+	var grant *model.CreditLedgerEntry
+	if plan.Credits > 0 {
+		grant = &model.CreditLedgerEntry{
+			UserID:  userID,
+			Delta:   plan.Credits,
+			Reason:  model.CreditReasonGrant,
+			Note:    strings.TrimSpace(note),
+			ActorID: actorID,
+		}
+	}
+	return subscription, grant
+}
+
+// ManagerCancelSubscription ends a member's subscription today. Credits it granted stay on the balance;
+// remove them with an adjustment if needed.
+func ManagerCancelSubscription(userID uint, subscriptionID uint) (*model.MemberSubscription, error) {
+	subscription, err := dao.GetMemberSubscriptionByID(subscriptionID)
+	if err != nil || subscription.UserID != userID {
+		return nil, errors.New("subscription not found")
+	}
+	if subscription.Status == model.SubscriptionStatusCanceled {
+		return nil, errors.New("subscription already canceled")
+	}
+
+	today := model.FacilityToday()
+	if err := dao.CancelMemberSubscription(subscription.ID, today); err != nil {
+		return nil, err
+	}
+	subscription.Status = model.SubscriptionStatusCanceled
+	subscription.EndsOn = &today
+	return subscription, nil
+}
+
+// ManagerAdjustCredits adds or removes credits by hand. The balance cannot go below zero.
+func ManagerAdjustCredits(managerID uint, userID uint, input model.CreditAdjustmentInput) (*model.CreditLedgerEntry, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	if input.Delta == 0 {
+		return nil, errors.New("delta must not be zero")
+	}
+	note := strings.TrimSpace(input.Note)
+	if note == "" {
+		return nil, errors.New("note is required")
+	}
+
+	entry := &model.CreditLedgerEntry{
+		UserID:  userID,
+		Delta:   input.Delta,
+		Reason:  model.CreditReasonAdjustment,
+		Note:    note,
+		ActorID: managerID,
+	}
+	if err := dao.AppendCreditLedgerEntry(entry, false); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// checkEntitlement refuses a booking when membership is required and the member has neither
+// an active unlimited plan nor a credit to spend.
+func checkEntitlement(userID uint) error {
+	if !membershipRequired {
+		return nil
+	}
+	unlimited, err := dao.HasActiveUnlimitedSubscription(userID, model.FacilityToday())
+	if err != nil || unlimited {
+		return err
+	}
+	balance, err := dao.GetCreditBalance(userID)
+	if err != nil {
+		return err
+	}
+	if balance < 1 {
+		return &BookingPolicyError{
+			Code:    BookingCodeMembershipRequired,
+			Message: "an active unlimited membership or a class credit is required to book",
+		}
+	}
+	return nil
+}
+
+// chargeBooking spends one credit on a member's new enrollment unless an unlimited plan covers it.
+// If the charge fails the enrollment is removed again, and its seat offered to the waitlist, so no unpaid
+// booking is left behind. Credits are charged per session, so the enrollment must have one.
+func chargeBooking(enrollment *model.Enrollment) error {
+	if enrollment.SessionID == nil {
+		return errors.New("enrollment has no session")
+	}
+	if !membershipRequired {
+		return nil
+	}
+	unlimited, err := dao.HasActiveUnlimitedSubscription(enrollment.UserID, model.FacilityToday())
+	if err != nil || unlimited {
+		return err
+	}
+
+	err = dao.AppendCreditLedgerEntry(&model.CreditLedgerEntry{
+		UserID:       enrollment.UserID,
+		Delta:        -1,
+		Reason:       model.CreditReasonBooking,
+		EnrollmentID: &enrollment.ID,
+		SessionID:    enrollment.SessionID,
+		ActorID:      enrollment.UserID,
+	}, false)
+	if err == nil {
+		return nil
+	}
+	removed, undoErr := dao.DeleteSessionEnrollment(enrollment.UserID, *enrollment.SessionID)
+	if undoErr != nil {
+		return undoErr
+	}
+	if undoErr := releaseSeat(removed); undoErr != nil {
+		return undoErr
+	}
+	if err.Error() == "insufficient credits" {
+		return checkEntitlement(enrollment.UserID)
+	}
+	return err
+}
+
+// refundBooking returns whatever an enrollment still holds in booking charges.
+func refundBooking(enrollment *model.Enrollment, actorID uint, note string) error {
+	return dao.WithTx(func(tx *gorm.DB) error {
+		return refundBookingTx(tx, enrollment, actorID, note)
+	})
+}
+
+// refundBookingTx is refundBooking within tx.
+func refundBookingTx(tx *gorm.DB, enrollment *model.Enrollment, actorID uint, note string) error {
+	charged, err := dao.GetEnrollmentCreditChargeTx(tx, enrollment.ID)
+	if err != nil || charged <= 0 {
+		return err
+	}
+	return dao.AppendCreditLedgerEntryTx(tx, &model.CreditLedgerEntry{
+		UserID:       enrollment.UserID,
+		Delta:        charged,
+		Reason:       model.CreditReasonRefund,
+		Note:         note,
+		EnrollmentID: &enrollment.ID,
+		SessionID:    enrollment.SessionID,
+		ActorID:      actorID,
+	}, true)
+}
+
+// refundDrop refunds a member's own drop unless it is a late cancel: waitlist entries always get
+// their credit back, seats only when dropped before the late-cancel window.
+func refundDrop(enrollment *model.Enrollment, now time.Time) error {
+	if isLateCancel(enrollment, now) {
+		return nil
+	}
+	return refundBooking(enrollment, enrollment.UserID, "dropped")
+}
+
+// refundEndedWaitlists returns the credits of waitlist entries whose session ended without a seat opening.
+func refundEndedWaitlists(now time.Time) error {
+	enrollments, err := dao.ListChargedEndedWaitlistEnrollments(now)
+	if err != nil {
+		return err
+	}
+	for i := range enrollments {
+		if err := refundBooking(&enrollments[i], enrollments[i].UserID, "waitlist did not clear"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+func requireMembership(t *testing.T) {
+	t.Helper()
+	SetMembershipRequired(true)
+	t.Cleanup(func() { SetMembershipRequired(false) })
+}
+
+func seedMembershipPlan(t *testing.T, name string, kind string, credits int, durationDays int) model.MembershipPlan {
+	t.Helper()
+	plan, err := ManagerCreateMembershipPlan(model.MembershipPlanInput{
+		Name:         name,
+		Kind:         kind,
+		Credits:      credits,
+		DurationDays: durationDays,
+	})
+	if err != nil {
+		t.Fatalf("failed to create plan %s: %v", name, err)
+	}
+	return *plan
+}
+
+func expectCredits(t *testing.T, userID uint, want int) {
+	t.Helper()
+	balance, err := dao.GetCreditBalance(userID)
+	if err != nil {
+		t.Fatalf("failed to read balance: %v", err)
+	}
+	if balance != want {
+		t.Fatalf("expected %d credits, got %d", want, balance)
+	}
+}
+
+func TestRegisterSession_SpendsAndRefundsCredits(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "Pilates", 5, "Core")
+	session := seedSessionAt(t, course, time.Now().Add(20*time.Hour), 5)
+
+	expectPolicyCode(t, RegisterSession(student.ID, session.ID), BookingCodeMembershipRequired)
+
+	pack := seedMembershipPlan(t, "Five pack", model.PlanKindClassPack, 2, 0)
+	if _, err := ManagerGrantSubscription(manager.ID, student.ID, model.SubscriptionGrantInput{PlanID: pack.ID}); err != nil {
+		t.Fatalf("failed to grant plan: %v", err)
+	}
+	expectCredits(t, student.ID, 2)
+
+	if err := RegisterSession(student.ID, session.ID); err != nil {
+		t.Fatalf("expected booking to succeed, got %v", err)
+	}
+	expectCredits(t, student.ID, 1)
+
+	if err := DropSession(student.ID, session.ID); err != nil {
+		t.Fatalf("expected drop to succeed, got %v", err)
+	}
+	expectCredits(t, student.ID, 2)
+
+	balance, err := GetMemberBalance(student.ID)
+	if err != nil {
+		t.Fatalf("failed to load balance: %v", err)
+	}
+	wantReasons := []string{model.CreditReasonRefund, model.CreditReasonBooking, model.CreditReasonGrant}
+	wantAfter := []int{2, 1, 2}
+	if len(balance.Ledger) != len(wantReasons) {
+		t.Fatalf("expected %d ledger entries, got %+v", len(wantReasons), balance.Ledger)
+	}
+	for i, entry := range balance.Ledger {
+		if entry.Reason != wantReasons[i] || entry.BalanceAfter != wantAfter[i] {
+			t.Fatalf("ledger entry %d: expected %s leaving %d, got %+v", i, wantReasons[i], wantAfter[i], entry)
+		}
+	}
+	if balance.Ledger[0].EnrollmentID == nil || *balance.Ledger[0].EnrollmentID != *balance.Ledger[1].EnrollmentID {
+		t.Fatalf("expected the refund to reference the charged enrollment, got %+v", balance.Ledger[:2])
+	}
+}
+
+func TestDropSession_LateCancelKeepsCredit(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+	useStrikeSettings(t, StrikeSettings{Limit: 3, Window: 30 * 24 * time.Hour, LateCancelWindow: 12 * time.Hour})
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "Spin", 5, "Cardio")
+	session := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 5)
+
+	pack := seedMembershipPlan(t, "Drop-in", model.PlanKindDropIn, 1, 0)
+	if _, err := ManagerGrantSubscription(manager.ID, student.ID, model.SubscriptionGrantInput{PlanID: pack.ID}); err != nil {
+		t.Fatalf("failed to grant plan: %v", err)
+	}
+	if err := RegisterSession(student.ID, session.ID); err != nil {
+		t.Fatalf("expected booking to succeed, got %v", err)
+	}
+	if err := DropSession(student.ID, session.ID); err != nil {
+		t.Fatalf("expected drop to succeed, got %v", err)
+	}
+	expectCredits(t, student.ID, 0)
+}
+
+func TestChargeBooking_FailureReleasesTheSeat(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+
+	student := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Barre", 1, "Core")
+	session := seedSessionAt(t, course, time.Now().Add(20*time.Hour), 1)
+	enrollment := seedEnrollmentForSession(t, student.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, waiting.ID, course.ID, session.ID, model.EnrollmentStatusWaitlisted, time.Now())
+
+	expectPolicyCode(t, chargeBooking(&enrollment), BookingCodeMembershipRequired)
+	if exists, _ := dao.CheckSessionEnrollmentExists(student.ID, session.ID); exists {
+		t.Fatalf("expected the unpaid enrollment to be removed")
+	}
+	promoted, err := dao.GetActiveSessionEnrollment(waiting.ID, session.ID)
+	if err != nil || promoted.Status != model.EnrollmentStatusEnrolled {
+		t.Fatalf("expected the waitlisted member to take the seat, got %+v (err %v)", promoted, err)
+	}
+
+	if err := chargeBooking(&model.Enrollment{UserID: student.ID, CourseID: course.ID}); err == nil || err.Error() != "enrollment has no session" {
+		t.Fatalf("expected a course-level enrollment to be refused, got %v", err)
+	}
+}
+
+func TestUnlimitedPlan_BooksWithoutCredits(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "Yoga", 5, "Mind")
+	first := seedSessionAt(t, course, time.Now().Add(20*time.Hour), 5)
+	second := seedSessionAt(t, course, time.Now().Add(22*time.Hour), 5)
+
+	monthly := seedMembershipPlan(t, "Monthly unlimited", model.PlanKindUnlimited, 0, 30)
+	subscription, err := ManagerGrantSubscription(manager.ID, student.ID, model.SubscriptionGrantInput{PlanID: monthly.ID})
+	if err != nil {
+		t.Fatalf("failed to grant plan: %v", err)
+	}
+	if subscription.EndsOn == nil || *subscription.EndsOn != time.Now().In(model.FacilityLocation()).AddDate(0, 0, 29).Format("2006-01-02") {
+		t.Fatalf("expected the plan to last 30 days, got %+v", subscription.EndsOn)
+	}
+
+	if err := RegisterSession(student.ID, first.ID); err != nil {
+		t.Fatalf("expected unlimited booking to succeed, got %v", err)
+	}
+	expectCredits(t, student.ID, 0)
+
+	if _, err := ManagerCancelSubscription(student.ID, subscription.ID); err != nil {
+		t.Fatalf("failed to cancel subscription: %v", err)
+	}
+	expectPolicyCode(t, RegisterSession(student.ID, second.ID), BookingCodeMembershipRequired)
+}
+
+func TestManagerAdjustCredits_CannotOverdraw(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+
+	entry, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: 3, Note: "goodwill"})
+	if err != nil || entry.BalanceAfter != 3 || entry.ActorID != manager.ID {
+		t.Fatalf("expected adjustment to apply, got %+v (err %v)", entry, err)
+	}
+	if _, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: -4, Note: "correction"}); err == nil || err.Error() != "insufficient credits" {
+		t.Fatalf("expected insufficient credits, got %v", err)
+	}
+	if _, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: -1, Note: "  "}); err == nil || err.Error() != "note is required" {
+		t.Fatalf("expected note is required, got %v", err)
+	}
+	expectCredits(t, student.ID, 3)
+}
+
+func TestCancelSession_RefundsCredits(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "HIIT", 5, "Cardio")
+	session := seedSessionAt(t, course, time.Now().Add(2*time.Hour), 5)
+
+	if _, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: 1, Note: "trial"}); err != nil {
+		t.Fatalf("failed to add credit: %v", err)
+	}
+	if err := RegisterSession(student.ID, session.ID); err != nil {
+		t.Fatalf("expected booking to succeed, got %v", err)
+	}
+	expectCredits(t, student.ID, 0)
+
+	if _, err := ManagerCancelSession(manager.ID, session.ID, model.SessionCancelInput{Reason: "Instructor sick"}); err != nil {
+		t.Fatalf("failed to cancel session: %v", err)
+	}
+	expectCredits(t, student.ID, 1)
+}
+
+func TestManagerDeleteUserEnrollment_RefundsAsTheManager(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "Barre", 5, "Core")
+
+	if _, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: 1, Note: "trial"}); err != nil {
+		t.Fatalf("failed to add credit: %v", err)
+	}
+	if err := RegisterClass(student.ID, course.ID); err != nil {
+		t.Fatalf("expected booking to succeed, got %v", err)
+	}
+	if err := ManagerDeleteUserEnrollment(manager.ID, student.ID, course.ID); err != nil {
+		t.Fatalf("failed to remove enrollment: %v", err)
+	}
+	expectCredits(t, student.ID, 1)
+
+	balance, err := GetMemberBalance(student.ID)
+	if err != nil {
+		t.Fatalf("failed to load balance: %v", err)
+	}
+	refund := balance.Ledger[0]
+	if refund.Reason != model.CreditReasonRefund || refund.ActorID != manager.ID {
+		t.Fatalf("expected a refund by manager %d, got %+v", manager.ID, refund)
+	}
+}
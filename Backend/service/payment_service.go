@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+	"my-course-backend/payments"
+)
+
+// paymentProvider is nil until payments are configured; checkouts and webhooks are refused until then.
+var (
+	paymentProvider payments.Provider
+	paymentCurrency = payments.DefaultCurrency
+)
+
+// SetPaymentProvider replaces the provider checkouts go through and the currency they are charged in.
+// A nil provider disables payments.
+func SetPaymentProvider(provider payments.Provider, currency string) {
+	paymentProvider = provider
+	if currency == "" {
+		currency = payments.DefaultCurrency
+	}
+	paymentCurrency = currency
+}
+
+// FakePaymentsEnabled reports whether the fake provider is configured, so its simulated checkout page is served.
+func FakePaymentsEnabled() bool {
+	_, ok := paymentProvider.(*payments.FakeProvider)
+	return ok
+}
+
+// PaymentProviderError is a call to the payment provider that failed.
+type PaymentProviderError struct {
+	Err error
+}
+
+func (e *PaymentProviderError) Error() string { return "payment provider error: " + e.Err.Error() }
+
+func (e *PaymentProviderError) Unwrap() error { return e.Err }
+
+// CreateCheckout starts a member's purchase of a plan and returns the pending order with its checkout URL.
+// The plan is granted when the provider's webhook reports the payment.
+func CreateCheckout(userID uint, input model.CheckoutInput) (*model.PaymentOrder, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	plan, err := dao.GetMembershipPlanByID(input.PlanID)
+	if err != nil {
+		return nil, errors.New("membership plan not found")
+	}
+	if !plan.Active {
+		return nil, errors.New("membership plan is inactive")
+	}
+	if plan.PriceCents <= 0 {
+		return nil, errors.New("membership plan is not for sale")
+	}
+	if paymentProvider == nil {
+		return nil, errors.New("payments are not enabled")
+	}
+
+	intent, err := paymentProvider.CreateCheckout(payments.CheckoutRequest{
+		AmountCents: plan.PriceCents,
+		Currency:    paymentCurrency,
+		Description: plan.Name,
+		Reference:   fmt.Sprintf("user-%d-plan-%d", userID, plan.ID),
+	})
+	if err != nil {
+		return nil, &PaymentProviderError{Err: err}
+	}
+
+	order := &model.PaymentOrder{
+		UserID:           userID,
+		PlanID:           plan.ID,
+		AmountCents:      intent.AmountCents,
+		Currency:         intent.Currency,
+		Provider:         paymentProvider.Name(),
+		ProviderIntentID: intent.ID,
+		CheckoutURL:      intent.CheckoutURL,
+		Status:           model.PaymentStatusPending,
+	}
+	if err := dao.CreatePaymentOrder(order); err != nil {
+		return nil, err
+	}
+	order.Plan = *plan
+	return order, nil
+}
+
+// ListMyPaymentOrders returns the member's orders.
+func ListMyPaymentOrders(userID uint) ([]model.PaymentOrder, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return dao.ListPaymentOrdersByUser(userID)
+}
+
+// GetMyPaymentOrder returns one of the member's orders.
+func GetMyPaymentOrder(userID uint, orderID uint) (*model.PaymentOrder, error) {
+	order, err := dao.GetPaymentOrderByID(orderID)
+	if err != nil || order.UserID != userID {
+		return nil, errors.New("payment order not found")
+	}
+	return order, nil
+}
+
+// ManagerRefundPaymentOrder refunds a paid order in full through the provider, cancels the subscription
+// it bought and takes back the credits it granted that are still unspent.
+func ManagerRefundPaymentOrder(managerID uint, orderID uint) (*model.PaymentOrder, error) {
+	order, err := dao.GetPaymentOrderByID(orderID)
+	if err != nil {
+		return nil, errors.New("payment order not found")
+	}
+	if order.Status != model.PaymentStatusPaid {
+		return nil, errors.New("only paid orders can be refunded")
+	}
+	if paymentProvider == nil || order.Provider != paymentProvider.Name() {
+		return nil, errors.New("payment provider not found")
+	}
+
+	if _, err := paymentProvider.Refund(order.ProviderIntentID, order.AmountCents); err != nil {
+		return nil, &PaymentProviderError{Err: err}
+	}
+	if err := revokePayment(order, managerID, nil); err != nil {
+		return nil, err
+	}
+	return dao.GetPaymentOrderByID(order.ID)
+}
+
+// HandlePaymentWebhook verifies and applies a provider webhook. Each event is applied once: it is stored
+// under its unique ID in the same transaction as the change it makes, so a redelivery changes nothing even
+// when it races the first delivery. Events for unknown intents are acknowledged without changes.
+func HandlePaymentWebhook(providerName string, payload []byte, header http.Header) error {
+	provider := paymentProvider
+	if provider == nil || providerName != provider.Name() {
+		return errors.New("payment provider not found")
+	}
+
+	event, err := provider.ParseWebhook(payload, header)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			return err
+		}
+		return errors.New("invalid webhook payload")
+	}
+
+	record := &model.PaymentEvent{
+		Provider: provider.Name(),
+		EventID:  event.ID,
+		Type:     event.Type,
+		Payload:  string(payload),
+	}
+	order, err := dao.GetPaymentOrderByIntent(provider.Name(), event.IntentID)
+	if err != nil {
+		return dao.RecordPaymentEvent(record)
+	}
+	record.OrderID = &order.ID
+	return applyPaymentEvent(provider, order, event, record)
+}
+
+// applyPaymentEvent moves an order along for one event and stores the event with the change. Every step
+// checks the order's current status, so applying an event twice changes nothing.
+func applyPaymentEvent(provider payments.Provider, order *model.PaymentOrder, event *payments.Event, record *model.PaymentEvent) error {
+	switch event.Type {
+	case payments.EventPaymentAuthorized:
+		// The event is stored once the capture succeeds, so a redelivery retries a capture that failed.
+		if _, err := dao.UpdatePaymentOrderStatus(order.ID, []string{model.PaymentStatusPending}, model.PaymentStatusAuthorized, nil); err != nil {
+			return err
+		}
+		current, err := dao.GetPaymentOrderByID(order.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != model.PaymentStatusAuthorized {
+			return dao.RecordPaymentEvent(record)
+		}
+		intent, err := provider.Capture(order.ProviderIntentID)
+		if err != nil {
+			return &PaymentProviderError{Err: err}
+		}
+		if intent.Status == payments.IntentSucceeded {
+			return completePayment(current, record)
+		}
+	case payments.EventPaymentSucceeded:
+		return completePayment(order, record)
+	case payments.EventPaymentFailed:
+		_, err := dao.UpdatePaymentOrderStatus(order.ID,
+			[]string{model.PaymentStatusPending, model.PaymentStatusAuthorized}, model.PaymentStatusFailed, record)
+		return err
+	case payments.EventPaymentRefunded:
+		return revokePayment(order, order.UserID, record)
+	}
+	return dao.RecordPaymentEvent(record)
+}
+
+// completePayment marks the order paid and grants the plan it bought, starting today.
+func completePayment(order *model.PaymentOrder, record *model.PaymentEvent) error {
+	start, err := time.ParseInLocation("2006-01-02", model.FacilityToday(), model.FacilityLocation())
+	if err != nil {
+		return err
+	}
+	subscription, grant := newSubscription(order.UserID, &order.Plan, start, nil, order.UserID, fmt.Sprintf("order %d", order.ID))
+	_, err = dao.MarkPaymentOrderPaid(order.ID, time.Now(), subscription, grant, record)
+	return err
+}
+
+// revokePayment marks a paid order refunded and withdraws what it granted.
+func revokePayment(order *model.PaymentOrder, actorID uint, record *model.PaymentEvent) error {
+	_, err := dao.MarkPaymentOrderRefunded(order, time.Now(), model.FacilityToday(), order.Plan.Credits, actorID, record)
+	return err
+}
+
+// SimulateFakePayment completes or declines one of the member's checkouts on the fake provider and delivers
+// the resulting webhook, standing in for the member paying on the provider's page. Only available with the
+// fake provider.
+func SimulateFakePayment(userID uint, intentID string, decline bool) error {
+	fake, ok := paymentProvider.(*payments.FakeProvider)
+	if !ok {
+		return errors.New("payment provider not found")
+	}
+	order, err := dao.GetPaymentOrderByIntent(fake.Name(), intentID)
+	if err != nil || order.UserID != userID {
+		return errors.New("payment order not found")
+	}
+
+	simulate := fake.Pay
+	if decline {
+		simulate = fake.Decline
+	}
+	payload, header, err := simulate(intentID)
+	if err != nil {
+		return errors.New("payment intent cannot be completed")
+	}
+	return HandlePaymentWebhook(fake.Name(), payload, header)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+	"my-course-backend/payments"
+)
+
+func useFakePayments(t *testing.T) *payments.FakeProvider {
+	t.Helper()
+	provider := payments.NewFakeProvider("test-secret")
+	SetPaymentProvider(provider, "")
+	t.Cleanup(func() { SetPaymentProvider(nil, "") })
+	return provider
+}
+
+func seedPricedPlan(t *testing.T, name string, credits int, priceCents int) model.MembershipPlan {
+	t.Helper()
+	plan, err := ManagerCreateMembershipPlan(model.MembershipPlanInput{
+		Name:       name,
+		Kind:       model.PlanKindClassPack,
+		Credits:    credits,
+		PriceCents: priceCents,
+	})
+	if err != nil {
+		t.Fatalf("failed to create plan %s: %v", name, err)
+	}
+	return *plan
+}
+
+func loadOrder(t *testing.T, orderID uint) *model.PaymentOrder {
+	t.Helper()
+	order, err := dao.GetPaymentOrderByID(orderID)
+	if err != nil {
+		t.Fatalf("failed to load order: %v", err)
+	}
+	return order
+}
+
+func TestPaymentWebhook_GrantsPlanOnce(t *testing.T) {
+	setupClassServiceTestDB(t)
+	provider := useFakePayments(t)
+
+	student := seedRoleAndUser(t, 1)
+	plan := seedPricedPlan(t, "Ten pack", 10, 12000)
+
+	order, err := CreateCheckout(student.ID, model.CheckoutInput{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("failed to start checkout: %v", err)
+	}
+	if order.Status != model.PaymentStatusPending || order.AmountCents != 12000 || order.CheckoutURL == "" {
+		t.Fatalf("expected a pending order with a checkout URL, got %+v", order)
+	}
+	expectCredits(t, student.ID, 0)
+
+	payload, header, err := provider.Pay(order.ProviderIntentID)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	tampered := header.Clone()
+	tampered.Set(payments.SignatureHeader, "00")
+	if err := HandlePaymentWebhook(payments.FakeProviderName, payload, tampered); !errors.Is(err, payments.ErrInvalidSignature) {
+		t.Fatalf("expected an invalid signature, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := HandlePaymentWebhook(payments.FakeProviderName, payload, header); err != nil {
+			t.Fatalf("delivery %d failed: %v", i+1, err)
+		}
+	}
+	// A second event for the same payment must not grant again either.
+	succeeded, succeededHeader, _ := provider.Webhook(payments.EventPaymentSucceeded, order.ProviderIntentID)
+	if err := HandlePaymentWebhook(payments.FakeProviderName, succeeded, succeededHeader); err != nil {
+		t.Fatalf("succeeded event failed: %v", err)
+	}
+
+	paid := loadOrder(t, order.ID)
+	if paid.Status != model.PaymentStatusPaid || paid.PaidAt == nil || paid.SubscriptionID == nil {
+		t.Fatalf("expected the order paid with a subscription, got %+v", paid)
+	}
+	expectCredits(t, student.ID, 10)
+
+	subscriptions, _ := dao.ListMemberSubscriptions(student.ID)
+	if len(subscriptions) != 1 || subscriptions[0].GrantedBy != nil {
+		t.Fatalf("expected one purchased subscription, got %+v", subscriptions)
+	}
+}
+
+func TestManagerRefundPaymentOrder_RevokesUnspentCredits(t *testing.T) {
+	setupClassServiceTestDB(t)
+	provider := useFakePayments(t)
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	plan := seedPricedPlan(t, "Five pack", 5, 6000)
+
+	order, err := CreateCheckout(student.ID, model.CheckoutInput{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("failed to start checkout: %v", err)
+	}
+	if _, err := ManagerRefundPaymentOrder(manager.ID, order.ID); err == nil || err.Error() != "only paid orders can be refunded" {
+		t.Fatalf("expected an unpaid order to be refused, got %v", err)
+	}
+
+	if err := SimulateFakePayment(student.ID, order.ProviderIntentID, false); err != nil {
+		t.Fatalf("failed to simulate payment: %v", err)
+	}
+	if _, err := ManagerAdjustCredits(manager.ID, student.ID, model.CreditAdjustmentInput{Delta: -3, Note: "spent"}); err != nil {
+		t.Fatalf("failed to spend credits: %v", err)
+	}
+
+	refunded, err := ManagerRefundPaymentOrder(manager.ID, order.ID)
+	if err != nil || refunded.Status != model.PaymentStatusRefunded || refunded.RefundedAt == nil {
+		t.Fatalf("expected the order refunded, got %+v (err %v)", refunded, err)
+	}
+	expectCredits(t, student.ID, 0)
+
+	subscription, _ := dao.GetMemberSubscriptionByID(*refunded.SubscriptionID)
+	if subscription.Status != model.SubscriptionStatusCanceled {
+		t.Fatalf("expected the subscription canceled, got %+v", subscription)
+	}
+
+	// The provider's own refund notification arrives afterwards and changes nothing.
+	payload, header, _ := provider.Webhook(payments.EventPaymentRefunded, order.ProviderIntentID)
+	if err := HandlePaymentWebhook(payments.FakeProviderName, payload, header); err != nil {
+		t.Fatalf("refund webhook failed: %v", err)
+	}
+	ledger, _ := dao.ListCreditLedger(student.ID)
+	if len(ledger) != 3 {
+		t.Fatalf("expected grant, spend and one revocation, got %+v", ledger)
+	}
+}
+
+func TestPaymentWebhook_DeclinedCheckoutFails(t *testing.T) {
+	setupClassServiceTestDB(t)
+	useFakePayments(t)
+
+	student := seedRoleAndUser(t, 1)
+	plan := seedPricedPlan(t, "Drop-in", 1, 2000)
+	free := seedPricedPlan(t, "Comp", 1, 0)
+
+	if _, err := CreateCheckout(student.ID, model.CheckoutInput{PlanID: free.ID}); err == nil || err.Error() != "membership plan is not for sale" {
+		t.Fatalf("expected a free plan to be refused, got %v", err)
+	}
+
+	order, err := CreateCheckout(student.ID, model.CheckoutInput{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("failed to start checkout: %v", err)
+	}
+	if err := SimulateFakePayment(student.ID, order.ProviderIntentID, true); err != nil {
+		t.Fatalf("failed to simulate decline: %v", err)
+	}
+	if failed := loadOrder(t, order.ID); failed.Status != model.PaymentStatusFailed || failed.SubscriptionID != nil {
+		t.Fatalf("expected the order failed without a subscription, got %+v", failed)
+	}
+	expectCredits(t, student.ID, 0)
+}
+
+func TestPaymentWebhook_ConcurrentRedeliveriesGrantOnce(t *testing.T) {
+	setupClassServiceTestDB(t)
+	provider := useFakePayments(t)
+
+	student := seedRoleAndUser(t, 1)
+	plan := seedPricedPlan(t, "Ten pack", 10, 12000)
+	order, err := CreateCheckout(student.ID, model.CheckoutInput{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("failed to start checkout: %v", err)
+	}
+	payload, header, _ := provider.Webhook(payments.EventPaymentSucceeded, order.ProviderIntentID)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- HandlePaymentWebhook(payments.FakeProviderName, payload, header)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("delivery failed: %v", err)
+		}
+	}
+
+	expectCredits(t, student.ID, 10)
+	var events int64
+	db.DB.Model(&model.PaymentEvent{}).Where("order_id = ?", order.ID).Count(&events)
+	if events != 1 {
+		t.Fatalf("expected the event stored once, got %d", events)
+	}
+}
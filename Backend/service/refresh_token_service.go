@@ -0,0 +1,152 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// StartSession signs a user in on a new device: a short-lived access token and a refresh token
+// starting a new session family.
+func StartSession(user *model.User) (*model.AuthTokens, error) {
+	familyID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	refresh, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dao.CreateRefreshToken(&model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(tokenSettings.RefreshTTL),
+	}); err != nil {
+		return nil, err
+	}
+	return authTokens(user, refresh)
+}
+
+// RefreshSession exchanges a refresh token for a new access token and a new refresh token. The presented
+// token is revoked; presenting it again is treated as theft and ends the whole session. The new access
+// token carries the user's current role.
+func RefreshSession(refreshToken string) (*model.AuthTokens, error) {
+	now := time.Now()
+	current, err := activeRefreshToken(refreshToken, now)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := dao.GetUserByID(current.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	refresh, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	next := &model.RefreshToken{
+		UserID:    current.UserID,
+		FamilyID:  current.FamilyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(tokenSettings.RefreshTTL),
+	}
+	if err := dao.RotateRefreshToken(current.ID, now, next); err != nil {
+		if err.Error() == "refresh token already used" {
+			// Another request rotated the token first: the same token was used twice.
+			revokeReusedFamily(current, now)
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	return authTokens(user, refresh)
+}
+
+// Logout ends the session of a refresh token, or every session of its user when allDevices is set.
+// Logging out of a session that already ended succeeds.
+func Logout(refreshToken string, allDevices bool) error {
+	now := time.Now()
+	current, err := activeRefreshToken(refreshToken, now)
+	if err != nil {
+		if allDevices {
+			return err
+		}
+		return nil
+	}
+
+	if allDevices {
+		_, err := dao.RevokeUserRefreshTokens(current.UserID, model.RefreshRevokedLogout, now)
+		return err
+	}
+	return dao.RevokeRefreshTokenFamily(current.FamilyID, model.RefreshRevokedLogout, now)
+}
+
+// RevokeUserSessions ends every session of a user, for example after a role or password change.
+// Access tokens already issued stay valid until they expire.
+func RevokeUserSessions(userID uint, reason string) error {
+	_, err := dao.RevokeUserRefreshTokens(userID, reason, time.Now())
+	return err
+}
+
+// activeRefreshToken looks up a refresh token that is neither revoked nor expired. A rotated token
+// presented again revokes its session.
+func activeRefreshToken(refreshToken string, now time.Time) (*model.RefreshToken, error) {
+	token, err := dao.GetRefreshTokenByHash(hashOpaqueToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if token.RevokedAt != nil {
+		if token.RevokedReason == model.RefreshRevokedRotated {
+			revokeReusedFamily(token, now)
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, errors.New("refresh token has expired")
+	}
+	return token, nil
+}
+
+func revokeReusedFamily(token *model.RefreshToken, now time.Time) {
+	log.Printf("refresh token reuse detected for user %d, revoking session", token.UserID)
+	if err := dao.RevokeRefreshTokenFamily(token.FamilyID, model.RefreshRevokedReuse, now); err != nil {
+		log.Printf("failed to revoke session after refresh token reuse: %v", err)
+	}
+}
+
+func authTokens(user *model.User, refresh string) (*model.AuthTokens, error) {
+	access, err := IssueAccessToken(user.ID, user.Email, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuthTokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int64(tokenSettings.TTL / time.Second),
+	}, nil
+}
+
+// newOpaqueToken returns a random token for a refresh or account token and the hash it is stored under.
+func newOpaqueToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashOpaqueToken(token), nil
+}
+
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
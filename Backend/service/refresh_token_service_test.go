@@ -0,0 +1,143 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func loadRefreshToken(t *testing.T, token string) model.RefreshToken {
+	t.Helper()
+
+	var stored model.RefreshToken
+	if err := db.DB.Where("token_hash = ?", hashOpaqueToken(token)).First(&stored).Error; err != nil {
+		t.Fatalf("failed to load refresh token: %v", err)
+	}
+	return stored
+}
+
+func TestRefreshSession_RotatesAndDetectsReuse(t *testing.T) {
+	setupClassServiceTestDB(t)
+	user := seedRoleAndUser(t, model.RoleStudent)
+
+	first, err := StartSession(&user)
+	if err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+	if first.ExpiresIn != int64(DefaultTokenSettings.TTL/time.Second) {
+		t.Fatalf("expected a %s access token, got expires_in %d", DefaultTokenSettings.TTL, first.ExpiresIn)
+	}
+	if stored := loadRefreshToken(t, first.RefreshToken); stored.TokenHash == first.RefreshToken {
+		t.Fatalf("expected only the hash of the refresh token to be stored")
+	}
+
+	second, err := RefreshSession(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected the refresh to succeed, got %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("expected a new refresh token")
+	}
+	if principal, err := ParseAccessToken(second.AccessToken); err != nil || principal.UserID != user.ID {
+		t.Fatalf("expected a valid access token for user %d, got %+v, %v", user.ID, principal, err)
+	}
+	if rotated := loadRefreshToken(t, first.RefreshToken); rotated.RevokedReason != model.RefreshRevokedRotated {
+		t.Fatalf("expected the first token to be rotated, got %q", rotated.RevokedReason)
+	}
+
+	// Replaying the rotated token ends the session, including the token that replaced it.
+	if _, err := RefreshSession(first.RefreshToken); err == nil || err.Error() != "invalid refresh token" {
+		t.Fatalf("expected the replayed token to be rejected, got %v", err)
+	}
+	if _, err := RefreshSession(second.RefreshToken); err == nil {
+		t.Fatalf("expected the session to be revoked after reuse")
+	}
+	if revoked := loadRefreshToken(t, second.RefreshToken); revoked.RevokedReason != model.RefreshRevokedReuse {
+		t.Fatalf("expected the replacement to be revoked for reuse, got %q", revoked.RevokedReason)
+	}
+}
+
+func TestRefreshSession_RejectsExpiredTokens(t *testing.T) {
+	setupClassServiceTestDB(t)
+	user := seedRoleAndUser(t, model.RoleStudent)
+
+	session, _ := StartSession(&user)
+	if err := db.DB.Model(&model.RefreshToken{}).Where("user_id = ?", user.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("failed to expire token: %v", err)
+	}
+	if _, err := RefreshSession(session.RefreshToken); err == nil || err.Error() != "refresh token has expired" {
+		t.Fatalf("expected an expired token to be rejected, got %v", err)
+	}
+
+	if _, err := RunSchedulerOnce(); err != nil {
+		t.Fatalf("scheduler run failed: %v", err)
+	}
+	var remaining int64
+	db.DB.Model(&model.RefreshToken{}).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("expected the scheduler to delete expired tokens, %d left", remaining)
+	}
+}
+
+func TestLogout_EndsOneOrAllSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+	user := seedRoleAndUser(t, model.RoleStudent)
+
+	phone, _ := StartSession(&user)
+	laptop, _ := StartSession(&user)
+	tablet, _ := StartSession(&user)
+
+	if err := Logout(phone.RefreshToken, false); err != nil {
+		t.Fatalf("failed to log out: %v", err)
+	}
+	if _, err := RefreshSession(phone.RefreshToken); err == nil {
+		t.Fatalf("expected the logged out session to be revoked")
+	}
+	if err := Logout(phone.RefreshToken, false); err != nil {
+		t.Fatalf("expected logging out twice to succeed, got %v", err)
+	}
+
+	laptop, err := RefreshSession(laptop.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected the other sessions to survive, got %v", err)
+	}
+
+	if err := Logout(tablet.RefreshToken, true); err != nil {
+		t.Fatalf("failed to log out everywhere: %v", err)
+	}
+	if _, err := RefreshSession(laptop.RefreshToken); err == nil {
+		t.Fatalf("expected every session to be revoked")
+	}
+	if err := Logout(tablet.RefreshToken, true); err == nil {
+		t.Fatalf("expected a revoked token not to log out other devices")
+	}
+}
+
+func TestUserChanges_RevokeSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+	member := seedRoleAndUser(t, model.RoleStudent)
+	seedRoleAndUser(t, model.RoleInstructor)
+
+	session, _ := StartSession(&member)
+	if err := AssignUserRole(member.ID, "Role-4"); err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+	if _, err := RefreshSession(session.RefreshToken); err == nil {
+		t.Fatalf("expected a role change to revoke the session")
+	}
+	if revoked := loadRefreshToken(t, session.RefreshToken); revoked.RevokedReason != model.RefreshRevokedRoleChanged {
+		t.Fatalf("expected role_changed, got %q", revoked.RevokedReason)
+	}
+
+	leaving := seedUserWithRole(t, model.RoleStudent)
+	session, _ = StartSession(&leaving)
+	if err := RemoveUser(leaving.ID); err != nil {
+		t.Fatalf("failed to remove user: %v", err)
+	}
+	if _, err := RefreshSession(session.RefreshToken); err == nil {
+		t.Fatalf("expected a deleted user's session to be revoked")
+	}
+}
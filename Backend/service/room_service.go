@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// ListRooms returns all rooms.
+func ListRooms() ([]model.Room, error) {
+	return dao.ListRooms()
+}
+
+// GetRoom returns one room.
+func GetRoom(id uint) (*model.Room, error) {
+	room, err := dao.GetRoomByID(id)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+	return room, nil
+}
+
+// ManagerCreateRoom creates a room with a unique name.
+func ManagerCreateRoom(input model.RoomInput) (*model.Room, error) {
+	room := &model.Room{}
+	if err := applyRoomInput(room, input); err != nil {
+		return nil, err
+	}
+	if err := dao.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// ManagerUpdateRoom updates a room. Its capacity cannot drop below a class held in it.
+func ManagerUpdateRoom(id uint, input model.RoomInput) (*model.Room, error) {
+	room, err := GetRoom(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRoomInput(room, input); err != nil {
+		return nil, err
+	}
+
+	largest, err := dao.GetMaxCourseCapacityInRoom(room.ID)
+	if err != nil {
+		return nil, err
+	}
+	if room.Capacity < largest {
+		return nil, errors.New("room capacity is below a class held in it")
+	}
+
+	if err := dao.UpdateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// ManagerDeleteRoom deletes a room that no course or session uses.
+func ManagerDeleteRoom(id uint) error {
+	if _, err := GetRoom(id); err != nil {
+		return err
+	}
+
+	used, err := dao.CountRoomUsage(id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return errors.New("room is in use")
+	}
+	return dao.DeleteRoomByID(id)
+}
+
+// ManagerSetSessionRoom moves a scheduled session to another room, or back to the course's room when RoomID is nil.
+// The session's capacity is capped by the room's capacity.
+func ManagerSetSessionRoom(sessionID uint, input model.SessionRoomInput) (*model.ClassSession, error) {
+	session, err := loadChangeableSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.RoomID = input.RoomID
+	capacity := session.Course.Capacity
+
+	room, err := sessionRoom(session)
+	if err != nil {
+		return nil, err
+	}
+	if room != nil {
+		if err := checkSessionRoomConflict(room.ID, session.ID, session.StartAt, session.EndAt); err != nil {
+			return nil, err
+		}
+		if capacity > room.Capacity {
+			capacity = room.Capacity
+		}
+	}
+
+	counts, err := dao.CountSeatedEnrollmentsBySessions([]uint{session.ID})
+	if err != nil {
+		return nil, err
+	}
+	if int(counts[session.ID]) > capacity {
+		return nil, errors.New("room is too small for the session's bookings")
+	}
+
+	if err := dao.SetSessionRoom(session.ID, input.RoomID, capacity); err != nil {
+		return nil, err
+	}
+	session.Capacity = capacity
+	return session, nil
+}
+
+// assignCourseRoom links a course to an existing room. The class capacity must fit the room.
+func assignCourseRoom(course *model.Course, roomID uint) error {
+	room, err := dao.GetRoomByID(roomID)
+	if err != nil {
+		return errors.New("room not found")
+	}
+	course.RoomID = &room.ID
+	course.Room = room
+	return nil
+}
+
+// checkCourseRoom validates the course's weekly slots against its room's capacity and other courses in it.
+func checkCourseRoom(course *model.Course) error {
+	if course.RoomID == nil {
+		return nil
+	}
+
+	room, err := dao.GetRoomByID(*course.RoomID)
+	if err != nil {
+		return errors.New("room not found")
+	}
+	if course.Capacity > room.Capacity {
+		return errors.New("class capacity exceeds room capacity")
+	}
+
+	slots := courseSlots(course)
+	if len(slots) == 0 {
+		return nil
+	}
+	courses, err := dao.ListCoursesByRoom(room.ID)
+	if err != nil {
+		return err
+	}
+	for i := range courses {
+		other := &courses[i]
+		if other.ID == course.ID {
+			continue
+		}
+		if _, overlaps := overlappingSlot(slots, courseSlots(other)); overlaps {
+			return errors.New("room is already booked at that time")
+		}
+	}
+	return nil
+}
+
+// checkSessionRoomConflict rejects a session time that overlaps another scheduled session in the same room.
+func checkSessionRoomConflict(roomID uint, sessionID uint, startAt time.Time, endAt time.Time) error {
+	// Neighbouring dates are included so sessions crossing midnight or time zones are still compared.
+	from := startAt.AddDate(0, 0, -1).Format("2006-01-02")
+	to := endAt.AddDate(0, 0, 1).Format("2006-01-02")
+
+	sessions, err := dao.ListScheduledSessionsInRoom(roomID, from, to)
+	if err != nil {
+		return err
+	}
+	for _, other := range sessions {
+		if other.ID == sessionID {
+			continue
+		}
+		if other.StartAt.Before(endAt) && startAt.Before(other.EndAt) {
+			return errors.New("room is already booked at that time")
+		}
+	}
+	return nil
+}
+
+// sessionRoom returns the room a session is held in: its own room, else its course's room.
+func sessionRoom(session *model.ClassSession) (*model.Room, error) {
+	roomID := session.RoomID
+	if roomID == nil {
+		roomID = session.Course.RoomID
+	}
+	if roomID == nil {
+		return nil, nil
+	}
+
+	room, err := dao.GetRoomByID(*roomID)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+	return room, nil
+}
+
+// fillCourseRoom loads the course's room for responses.
+func fillCourseRoom(course *model.Course) error {
+	if course.RoomID == nil {
+		course.Room = nil
+		return nil
+	}
+	room, err := dao.GetRoomByID(*course.RoomID)
+	if err != nil {
+		return err
+	}
+	course.Room = room
+	return nil
+}
+
+func applyRoomInput(room *model.Room, input model.RoomInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("room name is required")
+	}
+
+	taken, err := dao.CheckRoomNameTaken(name, room.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("room name already exists")
+	}
+
+	equipment, err := normalizeTagList(input.Equipment)
+	if err != nil {
+		return errors.New("too many equipment items")
+	}
+
+	room.Name = name
+	room.Capacity = input.Capacity
+	room.Equipment = equipment
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func TestManagerCreateCourse_RoomCapacityAndConflicts(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	room, err := ManagerCreateRoom(model.RoomInput{Name: "Studio A", Capacity: 12, Equipment: []string{"Mats", "mats", "Blocks"}})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	if len(room.Equipment) != 2 {
+		t.Fatalf("expected de-duplicated equipment, got %v", room.Equipment)
+	}
+	if _, err := ManagerCreateRoom(model.RoomInput{Name: "studio a", Capacity: 5}); err == nil || err.Error() != "room name already exists" {
+		t.Fatalf("expected duplicate room name error, got: %v", err)
+	}
+
+	input := CourseUpsertInput{
+		CourseName: "Yoga",
+		CourseCode: "YOGA-1",
+		StartTime:  "08:00",
+		EndTime:    "09:00",
+		Capacity:   20,
+		Weekday:    "Friday",
+		RoomID:     &room.ID,
+	}
+	if _, err := ManagerCreateCourse(input); err == nil || err.Error() != "class capacity exceeds room capacity" {
+		t.Fatalf("expected room capacity error, got: %v", err)
+	}
+
+	input.Capacity = 12
+	yoga, err := ManagerCreateCourse(input)
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+	if yoga.Room == nil || yoga.Room.ID != room.ID {
+		t.Fatalf("expected room in response, got %+v", yoga.Room)
+	}
+
+	input.CourseName, input.CourseCode = "Spin", "SPIN-1"
+	input.StartTime, input.EndTime = "08:45", "09:45"
+	if _, err := ManagerCreateCourse(input); err == nil || err.Error() != "room is already booked at that time" {
+		t.Fatalf("expected room conflict, got: %v", err)
+	}
+
+	input.Weekday = "Saturday"
+	if _, err := ManagerCreateCourse(input); err != nil {
+		t.Fatalf("expected course on another day to succeed, got: %v", err)
+	}
+
+	if _, err := ManagerUpdateRoom(room.ID, model.RoomInput{Name: "Studio A", Capacity: 8}); err == nil || err.Error() != "room capacity is below a class held in it" {
+		t.Fatalf("expected shrinking room to be rejected, got: %v", err)
+	}
+	if err := ManagerDeleteRoom(room.ID); err == nil || err.Error() != "room is in use" {
+		t.Fatalf("expected room in use, got: %v", err)
+	}
+
+	seedCourse(t, "Unroomed", 5, "Misc")
+	classes, _, _, _, _, err := ListClasses(model.ClassFilter{RoomID: &room.ID})
+	if err != nil {
+		t.Fatalf("failed to list classes: %v", err)
+	}
+	if len(classes) != 2 || classes[0].Room == nil {
+		t.Fatalf("expected the two classes in the room with room details, got %+v", classes)
+	}
+}
+
+func TestSessionRoom_CapsCapacityAndRejectsOverlap(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	small, _ := ManagerCreateRoom(model.RoomInput{Name: "Small", Capacity: 4})
+	big, _ := ManagerCreateRoom(model.RoomInput{Name: "Big", Capacity: 30})
+
+	yoga := seedCourse(t, "Yoga", 10, "Wellness")
+	spin := seedCourse(t, "Spin", 10, "Cardio")
+	db.DB.Model(&model.Course{}).Where("id = ?", spin.ID).Update("room_id", big.ID)
+
+	startAt := time.Now().Add(72 * time.Hour).Truncate(time.Hour)
+	yogaSession := seedSessionAt(t, yoga, startAt, 10)
+	spinSession := seedSessionAt(t, spin, startAt.Add(30*time.Minute), 10)
+
+	moved, err := ManagerSetSessionRoom(yogaSession.ID, model.SessionRoomInput{RoomID: &small.ID})
+	if err != nil {
+		t.Fatalf("failed to move session: %v", err)
+	}
+	if moved.Capacity != small.Capacity {
+		t.Fatalf("expected capacity capped at %d, got %d", small.Capacity, moved.Capacity)
+	}
+
+	if _, err := ManagerSetSessionRoom(yogaSession.ID, model.SessionRoomInput{RoomID: &big.ID}); err == nil || err.Error() != "room is already booked at that time" {
+		t.Fatalf("expected overlap with the spin session, got: %v", err)
+	}
+
+	capacity := 40
+	if _, err := ManagerRescheduleSession(1, spinSession.ID, model.SessionRescheduleInput{
+		StartAt:  spinSession.StartAt.Add(24 * time.Hour),
+		EndAt:    spinSession.EndAt.Add(24 * time.Hour),
+		Capacity: &capacity,
+	}); err == nil || err.Error() != "capacity exceeds room capacity" {
+		t.Fatalf("expected room capacity error on reschedule, got: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// ListInstructorCourseSessions returns a course's sessions with their headcounts; substitutes only
+// see the sessions they cover.
+func ListInstructorCourseSessions(instructorID uint, courseID uint) ([]model.InstructorSession, error) {
+	if err := syncEndedEnrollments(); err != nil {
+		return nil, err
+	}
+
+	_, access, err := resolveCourseAccess(instructorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := dao.ListSessionsByCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]model.ClassSession, 0, len(sessions))
+	ids := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		if access.covers(session.ID) {
+			visible = append(visible, session)
+			ids = append(ids, session.ID)
+		}
+	}
+	counts, err := dao.CountSessionHeadcounts(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.InstructorSession, len(visible))
+	for i, session := range visible {
+		result[i] = model.InstructorSession{ClassSession: session, SessionHeadcount: counts[session.ID]}
+	}
+	return result, nil
+}
+
+// GetInstructorSessionRoster returns who is booked into a session the instructor teaches or covers, with
+// each member's check-in, their attendance in the course's other sessions and their profile notes.
+func GetInstructorSessionRoster(instructorID uint, sessionID uint) (*model.SessionRoster, error) {
+	if err := syncEndedEnrollments(); err != nil {
+		return nil, err
+	}
+
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	_, access, err := resolveCourseAccess(instructorID, session.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.covers(session.ID) {
+		return nil, errors.New("forbidden")
+	}
+
+	enrollments, err := dao.ListSessionRosterEnrollments(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, len(enrollments))
+	for i := range enrollments {
+		userIDs[i] = enrollments[i].UserID
+	}
+	infos, err := dao.ListUserInfosByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	records, err := dao.ListCourseAttendanceRecords(session.CourseID, userIDs, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	history := attendanceHistoryByUser(records)
+
+	entries := make([]model.RosterEntry, len(enrollments))
+	for i, enrollment := range enrollments {
+		info := infos[enrollment.UserID]
+		entries[i] = model.RosterEntry{
+			EnrollmentID:     enrollment.ID,
+			UserID:           enrollment.UserID,
+			Name:             enrollment.User.Name,
+			Email:            enrollment.User.Email,
+			PhoneNumber:      info.PhoneNumber,
+			Status:           enrollment.Status,
+			WaitlistPosition: enrollment.WaitlistPosition,
+			CheckedIn:        enrollment.CheckedInAt != nil,
+			CheckedInAt:      enrollment.CheckedInAt,
+			Notes:            info.Notes,
+			History:          history[enrollment.UserID],
+		}
+	}
+	sortRosterEntries(entries)
+
+	return &model.SessionRoster{Session: *session, Entries: entries}, nil
+}
+
+func attendanceHistoryByUser(records []model.Enrollment) map[uint]model.AttendanceHistory {
+	history := make(map[uint]model.AttendanceHistory)
+	for _, record := range records {
+		entry := history[record.UserID]
+		if record.Status == model.EnrollmentStatusMissed {
+			entry.Missed++
+		} else {
+			entry.Attended++
+			if record.Session != nil && (entry.LastAttendedAt == nil || record.Session.StartAt.After(*entry.LastAttendedAt)) {
+				startAt := record.Session.StartAt
+				entry.LastAttendedAt = &startAt
+			}
+		}
+		history[record.UserID] = entry
+	}
+	return history
+}
+
+// sortRosterEntries puts seated members first by name, then the waitlist in queue order.
+func sortRosterEntries(entries []model.RosterEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		aWaiting := a.Status == model.EnrollmentStatusWaitlisted
+		bWaiting := b.Status == model.EnrollmentStatusWaitlisted
+		if aWaiting != bWaiting {
+			return bWaiting
+		}
+		if aWaiting {
+			return waitlistRank(a) < waitlistRank(b)
+		}
+		return a.Name < b.Name
+	})
+}
+
+func waitlistRank(entry model.RosterEntry) int {
+	if entry.WaitlistPosition == nil {
+		return int(^uint(0) >> 1)
+	}
+	return *entry.WaitlistPosition
+}
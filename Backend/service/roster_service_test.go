@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func TestGetInstructorSessionRoster_ShowsCheckInHistoryAndNotes(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	teacher := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, teacher)
+	stranger := seedUserWithRole(t, 4)
+	seedInstructorProfile(t, stranger)
+	regular := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	db.DB.Model(&model.User{}).Where("id = ?", regular.ID).Update("name", "Alex Regular")
+	course := seedCourse(t, "Kettlebells", 1, "Strength")
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Update("instructor_id", profile.ID)
+
+	notes := "Recovering knee, no jumping"
+	if err := UpdateUserProfilePatch(regular.ID, model.UserProfilePatch{
+		Notes: model.PatchString{Set: true, Valid: true, Value: notes},
+	}); err != nil {
+		t.Fatalf("failed to save notes: %v", err)
+	}
+
+	lastWeek := seedPastSession(t, course, 7)
+	yesterday := seedPastSession(t, course, 1)
+	seedEnrollmentForSession(t, regular.ID, course.ID, lastWeek.ID, model.EnrollmentStatusAttended, time.Now().AddDate(0, 0, -8))
+	seedEnrollmentForSession(t, regular.ID, course.ID, yesterday.ID, model.EnrollmentStatusMissed, time.Now().AddDate(0, 0, -2))
+
+	today := seedSessionAt(t, course, time.Now().Add(10*time.Minute), 1)
+	seat := seedEnrollmentForSession(t, regular.ID, course.ID, today.ID, model.EnrollmentStatusEnrolled, time.Now())
+	queued := seedEnrollmentForSession(t, waiting.ID, course.ID, today.ID, model.EnrollmentStatusWaitlisted, time.Now())
+	position := 1
+	db.DB.Model(&model.Enrollment{}).Where("id = ?", queued.ID).Update("waitlist_position", position)
+	if _, err := dao.MarkEnrollmentCheckedIn(seat.ID, time.Now(), &teacher.ID); err != nil {
+		t.Fatalf("failed to check in: %v", err)
+	}
+
+	if _, err := GetInstructorSessionRoster(stranger.ID, today.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden for another instructor, got %v", err)
+	}
+
+	roster, err := GetInstructorSessionRoster(teacher.ID, today.ID)
+	if err != nil {
+		t.Fatalf("failed to load roster: %v", err)
+	}
+	if roster.Session.ID != today.ID || len(roster.Entries) != 2 {
+		t.Fatalf("expected two entries for session %d, got %+v", today.ID, roster)
+	}
+	first, second := roster.Entries[0], roster.Entries[1]
+	if first.UserID != regular.ID || !first.CheckedIn || first.Status != model.EnrollmentStatusAttended || first.Notes != notes {
+		t.Fatalf("expected the checked-in member with notes first, got %+v", first)
+	}
+	if first.History.Attended != 1 || first.History.Missed != 1 || first.History.LastAttendedAt == nil ||
+		!first.History.LastAttendedAt.Equal(lastWeek.StartAt) {
+		t.Fatalf("expected one attended and one missed past session, got %+v", first.History)
+	}
+	if second.UserID != waiting.ID || second.Status != model.EnrollmentStatusWaitlisted || second.CheckedIn {
+		t.Fatalf("expected the waitlisted member last, got %+v", second)
+	}
+
+	sessions, err := ListInstructorCourseSessions(teacher.ID, course.ID)
+	if err != nil {
+		t.Fatalf("failed to list sessions: %v", err)
+	}
+	// seedCourse adds an upcoming session after today's.
+	if len(sessions) != 4 || sessions[0].ID != lastWeek.ID || sessions[2].ID != today.ID {
+		t.Fatalf("expected four sessions in start order, got %+v", sessions)
+	}
+	if got := sessions[2].SessionHeadcount; got.Seated != 1 || got.Waitlisted != 1 || got.CheckedIn != 1 {
+		t.Fatalf("unexpected headcount %+v", got)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+const (
+	// DefaultSchedulerInterval is how often the scheduler runs when none is configured.
+	DefaultSchedulerInterval = 15 * time.Minute
+	// DefaultSessionHorizonWeeks is how many weeks of sessions are kept generated ahead.
+	DefaultSessionHorizonWeeks = 12
+)
+
+// scheduler keeps class sessions and their derived data up to date in the background.
+type scheduler struct {
+	mu           sync.Mutex
+	runMu        sync.Mutex
+	interval     time.Duration
+	horizonWeeks int
+	stop         chan struct{}
+	done         chan struct{}
+	runs         int
+	lastRunAt    *time.Time
+	lastDuration time.Duration
+	lastError    string
+	lastResult   *model.SchedulerRunResult
+	nextRunAt    *time.Time
+}
+
+var defaultScheduler = &scheduler{
+	interval:     DefaultSchedulerInterval,
+	horizonWeeks: DefaultSessionHorizonWeeks,
+}
+
+// StartScheduler starts the background scheduler. It runs one pass immediately
+// and then every interval until StopScheduler is called.
+func StartScheduler(interval time.Duration, horizonWeeks int) error {
+	s := defaultScheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return errors.New("scheduler already running")
+	}
+	if interval <= 0 {
+		interval = DefaultSchedulerInterval
+	}
+	if horizonWeeks < 1 {
+		horizonWeeks = DefaultSessionHorizonWeeks
+	}
+
+	s.interval = interval
+	s.horizonWeeks = horizonWeeks
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop(s.stop, s.done, interval)
+	return nil
+}
+
+// ResumeScheduler starts the scheduler again with its last configured interval and horizon.
+func ResumeScheduler() error {
+	s := defaultScheduler
+	s.mu.Lock()
+	interval, horizonWeeks := s.interval, s.horizonWeeks
+	s.mu.Unlock()
+	return StartScheduler(interval, horizonWeeks)
+}
+
+// StopScheduler stops the background scheduler and waits for an in-flight pass to finish.
+func StopScheduler() error {
+	s := defaultScheduler
+	s.mu.Lock()
+	if s.stop == nil {
+		s.mu.Unlock()
+		return errors.New("scheduler not running")
+	}
+	stop, done := s.stop, s.done
+	s.stop, s.done, s.nextRunAt = nil, nil, nil
+	s.mu.Unlock()
+
+	close(stop)
+	<-done
+	return nil
+}
+
+// RunSchedulerOnce runs a single scheduler pass synchronously and returns its result.
+func RunSchedulerOnce() (*model.SchedulerRunResult, error) {
+	return defaultScheduler.run(time.Now())
+}
+
+// GetSchedulerStatus returns the current state of the background scheduler.
+func GetSchedulerStatus() model.SchedulerStatus {
+	s := defaultScheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := model.SchedulerStatus{
+		Running:      s.stop != nil,
+		Interval:     s.interval.String(),
+		HorizonWeeks: s.horizonWeeks,
+		Runs:         s.runs,
+		LastRunAt:    s.lastRunAt,
+		LastError:    s.lastError,
+		LastResult:   s.lastResult,
+		NextRunAt:    s.nextRunAt,
+	}
+	if s.lastRunAt != nil {
+		status.LastDuration = s.lastDuration.String()
+	}
+	return status
+}
+
+func (s *scheduler) loop(stop <-chan struct{}, done chan<- struct{}, interval time.Duration) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.run(time.Now()); err != nil {
+			log.Printf("scheduler: %v", err)
+		}
+
+		s.mu.Lock()
+		if s.stop != nil {
+			next := time.Now().Add(interval)
+			s.nextRunAt = &next
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run performs one pass: extend the session horizon for every course, complete past
+// sessions, settle ended enrollments under the attendance policy, refund waitlists that never cleared,
+// backfill daily activity and delete expired refresh and account tokens.
+// A failing step is recorded and the remaining steps still run.
+func (s *scheduler) run(now time.Time) (*model.SchedulerRunResult, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.mu.Lock()
+	horizonWeeks := s.horizonWeeks
+	s.mu.Unlock()
+
+	result := &model.SchedulerRunResult{}
+	var firstErr error
+	record := func(err error) {
+		result.Errors++
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	courses, _, err := dao.ListClasses(model.ClassFilter{})
+	if err != nil {
+		record(fmt.Errorf("failed to list courses: %w", err))
+	}
+	for _, course := range courses {
+		// Courses without a weekly slot have no recurring schedule to extend.
+		if !hasCourseSchedule(&course) {
+			continue
+		}
+		if err := GenerateClassSessions(course.ID, horizonWeeks); err != nil {
+			record(fmt.Errorf("failed to generate sessions for course %d: %w", course.ID, err))
+			continue
+		}
+		result.CoursesProcessed++
+	}
+
+	completed, err := dao.CompletePastSessions(now)
+	if err != nil {
+		record(fmt.Errorf("failed to complete past sessions: %w", err))
+	}
+	result.SessionsCompleted = completed
+
+	if err := syncEndedEnrollments(); err != nil {
+		record(fmt.Errorf("failed to sync ended enrollments: %w", err))
+	}
+
+	if err := refundEndedWaitlists(now); err != nil {
+		record(fmt.Errorf("failed to refund ended waitlists: %w", err))
+	}
+
+	if err := dao.BackfillDailyActivityFromEnrollments(); err != nil {
+		record(fmt.Errorf("failed to backfill daily activity: %w", err))
+	}
+
+	if _, err := dao.DeleteExpiredRefreshTokens(now); err != nil {
+		record(fmt.Errorf("failed to delete expired refresh tokens: %w", err))
+	}
+
+	if _, err := dao.DeleteExpiredAccountTokens(now); err != nil {
+		record(fmt.Errorf("failed to delete expired account tokens: %w", err))
+	}
+
+	finished := time.Now()
+	s.mu.Lock()
+	s.runs++
+	s.lastRunAt = &now
+	s.lastDuration = finished.Sub(now)
+	s.lastResult = result
+	s.lastError = ""
+	if firstErr != nil {
+		s.lastError = firstErr.Error()
+	}
+	s.mu.Unlock()
+
+	return result, firstErr
+}
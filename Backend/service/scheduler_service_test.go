@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+
+	"gorm.io/gorm"
+)
+
+func TestRunSchedulerOnce_MaintainsSessionsAndAttendance(t *testing.T) {
+	setupClassServiceTestDB(t)
+	useCheckInSettings(t, CheckInSettings{Policy: model.AttendancePolicyAssumeAttended})
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Pilates", 5, "Wellness")
+	past := seedSessionAt(t, course, time.Now().AddDate(0, 0, -3), 5)
+	enrollment := seedEnrollmentForSession(t, user.ID, course.ID, past.ID, model.EnrollmentStatusEnrolled, time.Now().AddDate(0, 0, -4))
+
+	result, err := RunSchedulerOnce()
+	if err != nil {
+		t.Fatalf("expected scheduler pass success, got error: %v", err)
+	}
+	if result.CoursesProcessed != 1 {
+		t.Fatalf("expected 1 course processed, got %d", result.CoursesProcessed)
+	}
+	if result.SessionsCompleted != 1 {
+		t.Fatalf("expected 1 session completed, got %d", result.SessionsCompleted)
+	}
+
+	var upcoming int64
+	db.DB.Model(&model.ClassSession{}).Where("course_id = ? AND status = 'scheduled'", course.ID).Count(&upcoming)
+	if upcoming < DefaultSessionHorizonWeeks {
+		t.Fatalf("expected at least %d upcoming sessions, got %d", DefaultSessionHorizonWeeks, upcoming)
+	}
+
+	reloaded := model.ClassSession{}
+	db.DB.First(&reloaded, past.ID)
+	if reloaded.Status != "completed" {
+		t.Fatalf("expected past session completed, got %s", reloaded.Status)
+	}
+
+	updated := model.Enrollment{}
+	db.DB.First(&updated, enrollment.ID)
+	if updated.Status != model.EnrollmentStatusAttended {
+		t.Fatalf("expected enrollment attended, got %s", updated.Status)
+	}
+
+	var activity int64
+	db.DB.Model(&model.UserDailyActivity{}).Where("enrollment_id = ?", enrollment.ID).Count(&activity)
+	if activity != 1 {
+		t.Fatalf("expected daily activity to be backfilled, got %d rows", activity)
+	}
+
+	status := GetSchedulerStatus()
+	if status.LastRunAt == nil || status.LastResult == nil || status.LastError != "" {
+		t.Fatalf("expected status to record the last run, got %+v", status)
+	}
+}
+
+func TestManagerCreateCourse_GeneratesSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	course, err := ManagerCreateCourse(CourseUpsertInput{
+		CourseName: "HIIT",
+		CourseCode: "HIIT-101",
+		StartTime:  "08:00",
+		EndTime:    "09:00",
+		Capacity:   10,
+		Weekday:    "Wednesday",
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	var count int64
+	db.DB.Model(&model.ClassSession{}).Where("course_id = ?", course.ID).Count(&count)
+	if count != DefaultSessionHorizonWeeks {
+		t.Fatalf("expected %d generated sessions, got %d", DefaultSessionHorizonWeeks, count)
+	}
+}
+
+func TestManagerCreateCourse_LeavesNothingWhenSessionsFail(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	if err := db.DB.Callback().Create().Before("gorm:create").Register("test:fail_sessions", func(tx *gorm.DB) {
+		if tx.Statement.Table == "ClassSession" {
+			tx.AddError(errors.New("session insert failed"))
+		}
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	if _, err := ManagerCreateCourse(CourseUpsertInput{
+		CourseName: "HIIT",
+		CourseCode: "HIIT-101",
+		StartTime:  "08:00",
+		EndTime:    "09:00",
+		Capacity:   10,
+		Weekday:    "Wednesday",
+	}); err == nil {
+		t.Fatalf("expected the failed session insert to fail the create")
+	}
+
+	var courses, slots int64
+	db.DB.Model(&model.Course{}).Count(&courses)
+	db.DB.Model(&model.CourseSlot{}).Count(&slots)
+	if courses != 0 || slots != 0 {
+		t.Fatalf("expected no course or slots left behind, got %d courses and %d slots", courses, slots)
+	}
+}
+
+func TestManagerUpdateCourse_LeavesCourseUnchangedWhenSessionsFail(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Pilates", 1, "Core")
+	seedEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+	if _, err := JoinWaitlist(waiting.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+
+	if err := db.DB.Callback().Create().Before("gorm:create").Register("test:fail_sessions", func(tx *gorm.DB) {
+		if tx.Statement.Table == "ClassSession" {
+			tx.AddError(errors.New("session insert failed"))
+		}
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	if _, err := ManagerUpdateCourse(course.ID, CourseUpsertInput{
+		CourseName: course.CourseName,
+		CourseCode: course.CourseCode,
+		StartTime:  course.StartTime.Format("15:04"),
+		EndTime:    course.EndTime.Format("15:04"),
+		Capacity:   2,
+		Category:   course.Category,
+		Weekday:    course.Weekday,
+	}); err == nil {
+		t.Fatalf("expected the failed session insert to fail the update")
+	}
+
+	var stored model.Course
+	if err := db.DB.First(&stored, course.ID).Error; err != nil {
+		t.Fatalf("failed to load course: %v", err)
+	}
+	if stored.Capacity != 1 {
+		t.Fatalf("expected the course capacity to stay 1, got %d", stored.Capacity)
+	}
+	if entry := loadEnrollment(t, waiting.ID, course.ID); entry.Status != model.EnrollmentStatusWaitlisted {
+		t.Fatalf("expected the waitlisted member to stay queued, got %s", entry.Status)
+	}
+}
+
+func TestStartStopScheduler(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	if err := StartScheduler(time.Hour, DefaultSessionHorizonWeeks); err != nil {
+		t.Fatalf("expected scheduler start, got error: %v", err)
+	}
+	if err := StartScheduler(time.Hour, DefaultSessionHorizonWeeks); err == nil || err.Error() != "scheduler already running" {
+		t.Fatalf("expected scheduler already running, got: %v", err)
+	}
+	if !GetSchedulerStatus().Running {
+		t.Fatalf("expected scheduler to report running")
+	}
+
+	if err := StopScheduler(); err != nil {
+		t.Fatalf("expected scheduler stop, got error: %v", err)
+	}
+	status := GetSchedulerStatus()
+	if status.Running {
+		t.Fatalf("expected scheduler to report stopped")
+	}
+	if status.Runs < 1 {
+		t.Fatalf("expected the scheduler to run once on start, got %d runs", status.Runs)
+	}
+	if err := StopScheduler(); err == nil || err.Error() != "scheduler not running" {
+		t.Fatalf("expected scheduler not running, got: %v", err)
+	}
+}
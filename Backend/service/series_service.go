@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"my-course-backend/dao"
+	"my-course-backend/model"
+	"time"
+)
+
+// CreateBookingSeries books every upcoming session of a course in a date range for a user
+// and returns the per-session outcome. Sessions that cannot be booked yet are retried on later syncs.
+func CreateBookingSeries(userID uint, input model.BookingSeriesRequest) (*model.BookingSeries, []model.BookingSeriesResult, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if _, err := dao.GetCourseByID(input.CourseID); err != nil {
+		return nil, nil, errors.New("class not found")
+	}
+
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, nil, errors.New("invalid start_date, expected YYYY-MM-DD")
+	}
+	endDate, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil {
+		return nil, nil, errors.New("invalid end_date, expected YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, nil, errors.New("end_date must not be before start_date")
+	}
+
+	active, err := dao.CountActiveBookingSeries(userID, input.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if active > 0 {
+		return nil, nil, errors.New("booking series already exists")
+	}
+
+	series := &model.BookingSeries{
+		UserID:    userID,
+		CourseID:  input.CourseID,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Status:    model.BookingSeriesStatusActive,
+	}
+	if err := dao.CreateBookingSeries(series); err != nil {
+		return nil, nil, err
+	}
+
+	results, err := syncBookingSeries(series, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, results, nil
+}
+
+// syncBookingSeries tries to book each future session in the series range that has no final outcome yet.
+func syncBookingSeries(series *model.BookingSeries, now time.Time) ([]model.BookingSeriesResult, error) {
+	sessions, err := dao.ListScheduledSessionsInRange(series.CourseID, series.StartDate, series.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := dao.ListBookingSeriesItems(series.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.BookingSeriesResult, 0, len(sessions))
+	for i := range sessions {
+		session := &sessions[i]
+		if !session.StartAt.After(now) {
+			continue
+		}
+
+		status := ""
+		if item, ok := items[session.ID]; ok && !item.Retryable() {
+			status = item.Status
+		} else {
+			status, err = bookSeriesSession(series.UserID, session, now)
+			if err != nil {
+				return nil, err
+			}
+			if !ok || item.Status != status {
+				if err := dao.UpsertBookingSeriesItem(series.ID, session.ID, status); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		results = append(results, model.BookingSeriesResult{
+			SessionID:   session.ID,
+			SessionDate: session.SessionDate,
+			Status:      status,
+		})
+	}
+	return results, nil
+}
+
+// bookSeriesSession attempts one session of a series and maps expected failures to an outcome.
+func bookSeriesSession(userID uint, session *model.ClassSession, now time.Time) (string, error) {
+	exists, err := dao.CheckSessionEnrollmentExists(userID, session.ID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return model.SeriesItemAlreadyBooked, nil
+	}
+
+	policy := courseBookingPolicy(&session.Course)
+	if err := validateSessionWindow(session, policy, now); err != nil {
+		return model.SeriesItemWindowNotOpen, nil
+	}
+
+	if err := checkMemberStanding(userID, now); err != nil {
+		var policyErr *BookingPolicyError
+		if errors.As(err, &policyErr) {
+			return model.SeriesItemLimitReached, nil
+		}
+		return "", err
+	}
+
+	hasOverlap, err := hasSessionOverlap(userID, session)
+	if err != nil {
+		return "", err
+	}
+	if hasOverlap {
+		return model.SeriesItemOverlap, nil
+	}
+
+	if err := checkBookingLimits(userID, policy, session, now); err != nil {
+		var policyErr *BookingPolicyError
+		if errors.As(err, &policyErr) {
+			return model.SeriesItemLimitReached, nil
+		}
+		return "", err
+	}
+
+	if err := checkEntitlement(userID); err != nil {
+		var policyErr *BookingPolicyError
+		if errors.As(err, &policyErr) {
+			return model.SeriesItemLimitReached, nil
+		}
+		return "", err
+	}
+
+	enrollment, err := bookSession(userID, session)
+	if err != nil {
+		if err.Error() == "class is full" {
+			return model.SeriesItemFull, nil
+		}
+		return "", err
+	}
+	if err := chargeBooking(enrollment); err != nil {
+		var policyErr *BookingPolicyError
+		if errors.As(err, &policyErr) {
+			return model.SeriesItemLimitReached, nil
+		}
+		return "", err
+	}
+	return model.SeriesItemBooked, nil
+}
+
+// SyncCourseBookingSeries extends every active series of a course onto newly generated or newly opened sessions.
+func SyncCourseBookingSeries(courseID uint) error {
+	series, err := dao.ListActiveBookingSeriesByCourse(courseID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range series {
+		if _, err := syncBookingSeries(&series[i], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListUserBookingSeries syncs and returns the user's booking series with per-session outcomes.
+func ListUserBookingSeries(userID uint) ([]model.BookingSeries, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	active, err := dao.ListActiveBookingSeriesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range active {
+		if _, err := syncBookingSeries(&active[i], now); err != nil {
+			return nil, err
+		}
+	}
+
+	return dao.ListBookingSeriesByUser(userID)
+}
+
+// CancelBookingSeries drops every remaining booked session of a series and stops it from booking more.
+// Each session is dropped as DropSession would, so credits are refunded and late cancels recorded;
+// sessions past their cancellation cutoff stay booked. Returns the number of sessions that were dropped.
+func CancelBookingSeries(userID uint, seriesID uint) (int, error) {
+	series, err := dao.GetBookingSeriesByID(seriesID)
+	if err != nil {
+		return 0, errors.New("booking series not found")
+	}
+	if series.UserID != userID {
+		return 0, errors.New("forbidden")
+	}
+	if series.Status != model.BookingSeriesStatusActive {
+		return 0, errors.New("booking series already canceled")
+	}
+
+	sessions, err := dao.ListScheduledSessionsInRange(series.CourseID, series.StartDate, series.EndDate)
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := dao.ListBookingSeriesItems(series.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	dropped := 0
+	for i := range sessions {
+		session := &sessions[i]
+		item, ok := items[session.ID]
+		if !ok || !session.StartAt.After(now) {
+			continue
+		}
+
+		if item.Status == model.SeriesItemBooked {
+			removed, err := dropSeriesSession(userID, session.ID, now)
+			var policyErr *BookingPolicyError
+			if errors.As(err, &policyErr) {
+				continue
+			}
+			if err != nil {
+				return dropped, err
+			}
+			if removed {
+				dropped++
+			}
+		}
+
+		if item.Status != model.SeriesItemDropped {
+			if err := dao.UpsertBookingSeriesItem(series.ID, session.ID, model.SeriesItemCanceled); err != nil {
+				return dropped, err
+			}
+		}
+	}
+
+	if err := dao.UpdateBookingSeriesStatus(series.ID, model.BookingSeriesStatusCanceled); err != nil {
+		return dropped, err
+	}
+	return dropped, nil
+}
+
+// dropSeriesSession drops the user's enrollment in one session of a canceled series through the same
+// cutoff, late-cancel and refund checks as DropSession. Returns false when there was nothing to drop.
+func dropSeriesSession(userID uint, sessionID uint, now time.Time) (bool, error) {
+	enrollment, err := dao.GetActiveSessionEnrollment(userID, sessionID)
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := checkCancelCutoff(enrollment, now); err != nil {
+		return false, err
+	}
+
+	removed, err := dao.DeleteSessionEnrollment(userID, sessionID)
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := recordLateCancel(enrollment, now); err != nil {
+		return false, err
+	}
+	if err := refundDrop(enrollment, now); err != nil {
+		return false, err
+	}
+	return true, releaseSeat(removed)
+}
+
+// skipSeriesSession keeps the user's series from re-booking a session they dropped.
+func skipSeriesSession(removed *model.Enrollment) error {
+	if removed.SessionID == nil {
+		return nil
+	}
+	return dao.MarkSeriesItemsDropped(removed.UserID, *removed.SessionID)
+}
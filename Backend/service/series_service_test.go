@@ -0,0 +1,220 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func seriesResultsBySession(results []model.BookingSeriesResult) map[uint]string {
+	bySession := make(map[uint]string, len(results))
+	for _, r := range results {
+		bySession[r.SessionID] = r.Status
+	}
+	return bySession
+}
+
+func TestCreateBookingSeries_ReportsPerSessionResults(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	user := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Yoga", 5, "Wellness")
+	now := time.Now()
+	open := seedSessionAt(t, course, now.Add(2*time.Hour), 5)
+	full := seedSessionAt(t, course, now.Add(5*time.Hour), 1)
+	later := seedSessionAt(t, course, now.AddDate(0, 0, 7), 5)
+	seedEnrollmentForSession(t, seated.ID, course.ID, full.ID, model.EnrollmentStatusEnrolled, now)
+
+	series, results, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 30).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if series.Status != model.BookingSeriesStatusActive {
+		t.Fatalf("expected active series, got %s", series.Status)
+	}
+
+	statuses := seriesResultsBySession(results)
+	if statuses[open.ID] != model.SeriesItemBooked {
+		t.Fatalf("expected open session booked, got %q", statuses[open.ID])
+	}
+	if statuses[full.ID] != model.SeriesItemFull {
+		t.Fatalf("expected full session reported full, got %q", statuses[full.ID])
+	}
+	if statuses[later.ID] != model.SeriesItemWindowNotOpen {
+		t.Fatalf("expected later session window_not_open, got %q", statuses[later.ID])
+	}
+
+	if _, _, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 30).Format("2006-01-02"),
+	}); err == nil || err.Error() != "booking series already exists" {
+		t.Fatalf("expected booking series already exists, got: %v", err)
+	}
+
+	// Once the window for the later session opens, a sync books it.
+	results, err = syncBookingSeries(series, later.StartAt.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("expected sync success, got error: %v", err)
+	}
+	if status := seriesResultsBySession(results)[later.ID]; status != model.SeriesItemBooked {
+		t.Fatalf("expected later session booked after window opened, got %q", status)
+	}
+}
+
+func TestGenerateClassSessions_ExtendsActiveSeries(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Spin", 5, "Cardio")
+	now := time.Now()
+
+	series, _, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 60).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	var before int64
+	db.DB.Model(&model.BookingSeriesItem{}).Where("series_id = ?", series.ID).Count(&before)
+
+	if err := GenerateClassSessions(course.ID, 4); err != nil {
+		t.Fatalf("expected session generation success, got error: %v", err)
+	}
+
+	var after int64
+	db.DB.Model(&model.BookingSeriesItem{}).Where("series_id = ?", series.ID).Count(&after)
+	if after <= before {
+		t.Fatalf("expected series to cover newly generated sessions, items before=%d after=%d", before, after)
+	}
+}
+
+func TestCancelBookingSeries_DropsRemainingSessions(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	other := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Boxing", 5, "Combat")
+	now := time.Now()
+	open := seedSessionAt(t, course, now.Add(2*time.Hour), 5)
+
+	series, _, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 14).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	if _, err := CancelBookingSeries(other.ID, series.ID); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden, got: %v", err)
+	}
+
+	dropped, err := CancelBookingSeries(user.ID, series.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if dropped != 1 {
+		t.Fatalf("expected 1 dropped session, got %d", dropped)
+	}
+
+	exists, err := dao.CheckSessionEnrollmentExists(user.ID, open.ID)
+	if err != nil {
+		t.Fatalf("failed to check enrollment: %v", err)
+	}
+	if exists {
+		t.Fatalf("expected session %d enrollment to be removed", open.ID)
+	}
+
+	reloaded := model.BookingSeries{}
+	db.DB.First(&reloaded, series.ID)
+	if reloaded.Status != model.BookingSeriesStatusCanceled {
+		t.Fatalf("expected series canceled, got %s", reloaded.Status)
+	}
+}
+
+func TestDropSession_IsNotRebookedBySeries(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Rowing", 5, "Cardio")
+	now := time.Now()
+	open := seedSessionAt(t, course, now.Add(2*time.Hour), 5)
+
+	series, _, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 14).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	if err := DropSession(user.ID, open.ID); err != nil {
+		t.Fatalf("expected drop success, got error: %v", err)
+	}
+
+	results, err := syncBookingSeries(series, now)
+	if err != nil {
+		t.Fatalf("expected sync success, got error: %v", err)
+	}
+	if status := seriesResultsBySession(results)[open.ID]; status != model.SeriesItemDropped {
+		t.Fatalf("expected dropped session to stay dropped, got %q", status)
+	}
+}
+
+func TestCancelBookingSeries_RefundsCreditsAndKeepsSessionsPastCutoff(t *testing.T) {
+	setupClassServiceTestDB(t)
+	requireMembership(t)
+	useStrikeSettings(t, StrikeSettings{Limit: 3, Window: 30 * 24 * time.Hour, LateCancelWindow: time.Hour})
+
+	user := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	if _, err := ManagerCreateBookingPolicy(model.BookingPolicyInput{Name: "Late cancel", Category: "Strength", OpensHoursBefore: 25, CancelCutoffMinutes: 240}); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	pack := seedMembershipPlan(t, "Three pack", model.PlanKindClassPack, 3, 0)
+	if _, err := ManagerGrantSubscription(manager.ID, user.ID, model.SubscriptionGrantInput{PlanID: pack.ID}); err != nil {
+		t.Fatalf("failed to grant plan: %v", err)
+	}
+
+	course := seedCourse(t, "Lift", 5, "Strength")
+	now := time.Now()
+	soon := seedSessionAt(t, course, now.Add(2*time.Hour), 5)
+	later := seedSessionAt(t, course, now.Add(6*time.Hour), 5)
+
+	series, _, err := CreateBookingSeries(user.ID, model.BookingSeriesRequest{
+		CourseID:  course.ID,
+		StartDate: now.Format("2006-01-02"),
+		EndDate:   now.AddDate(0, 0, 14).Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	expectCredits(t, user.ID, 1)
+
+	dropped, err := CancelBookingSeries(user.ID, series.ID)
+	if err != nil || dropped != 1 {
+		t.Fatalf("expected 1 dropped session, got %d (err %v)", dropped, err)
+	}
+	expectCredits(t, user.ID, 2)
+
+	if exists, _ := dao.CheckSessionEnrollmentExists(user.ID, soon.ID); !exists {
+		t.Fatalf("expected the session past its cutoff to stay booked")
+	}
+	if exists, _ := dao.CheckSessionEnrollmentExists(user.ID, later.ID); exists {
+		t.Fatalf("expected session %d enrollment to be removed", later.ID)
+	}
+}
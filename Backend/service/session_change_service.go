@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"my-course-backend/dao"
+	"my-course-backend/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ManagerCancelSession cancels one session of any course.
+func ManagerCancelSession(managerID uint, sessionID uint, input model.SessionCancelInput) (*model.SessionChangeLog, error) {
+	session, err := loadChangeableSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return cancelSession(managerID, session, input.Reason)
+}
+
+// InstructorCancelSession cancels one session of a course taught by the instructor.
+func InstructorCancelSession(instructorID uint, sessionID uint, input model.SessionCancelInput) (*model.SessionChangeLog, error) {
+	session, err := loadInstructorChangeableSession(instructorID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return cancelSession(instructorID, session, input.Reason)
+}
+
+// ManagerRescheduleSession moves one session of any course to a new time.
+func ManagerRescheduleSession(managerID uint, sessionID uint, input model.SessionRescheduleInput) (*model.SessionChangeLog, error) {
+	session, err := loadChangeableSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return rescheduleSession(managerID, session, input)
+}
+
+// InstructorRescheduleSession moves one session of a course taught by the instructor to a new time.
+func InstructorRescheduleSession(instructorID uint, sessionID uint, input model.SessionRescheduleInput) (*model.SessionChangeLog, error) {
+	session, err := loadInstructorChangeableSession(instructorID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return rescheduleSession(instructorID, session, input)
+}
+
+// ListSessionChanges returns the audit log of a session.
+func ListSessionChanges(sessionID uint) ([]model.SessionChangeLog, error) {
+	if _, err := dao.GetSessionByID(sessionID); err != nil {
+		return nil, errors.New("session not found")
+	}
+	return dao.ListSessionChanges(sessionID)
+}
+
+// ListUserSessionNotifications returns the notifications a user received about session changes.
+func ListUserSessionNotifications(userID uint) ([]model.SessionNotification, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return dao.ListSessionNotificationsByUser(userID)
+}
+
+// loadChangeableSession returns a scheduled session that can still be canceled or moved.
+func loadChangeableSession(sessionID uint) (*model.ClassSession, error) {
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.Status != "scheduled" {
+		return nil, errors.New("session is not scheduled")
+	}
+	return session, nil
+}
+
+// loadInstructorChangeableSession is loadChangeableSession plus the course ownership check.
+func loadInstructorChangeableSession(instructorID uint, sessionID uint) (*model.ClassSession, error) {
+	profileID, err := resolveInstructorProfileID(instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	owns, err := courseBelongsToInstructor(&session.Course, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if !owns {
+		return nil, errors.New("forbidden")
+	}
+	if session.Status != "scheduled" {
+		return nil, errors.New("session is not scheduled")
+	}
+	return session, nil
+}
+
+func cancelSession(actorID uint, session *model.ClassSession, reason string) (*model.SessionChangeLog, error) {
+	var entry *model.SessionChangeLog
+	err := dao.WithTx(func(tx *gorm.DB) error {
+		var err error
+		entry, err = cancelSessionTx(tx, actorID, session, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// cancelSessionTx cancels a session and refunds its bookings within tx.
+func cancelSessionTx(tx *gorm.DB, actorID uint, session *model.ClassSession, reason string) (*model.SessionChangeLog, error) {
+	reason = strings.TrimSpace(reason)
+	entry := &model.SessionChangeLog{
+		SessionID:        session.ID,
+		CourseID:         session.CourseID,
+		Action:           model.SessionChangeCanceled,
+		ActorID:          actorID,
+		Reason:           reason,
+		PreviousStartAt:  session.StartAt,
+		PreviousEndAt:    session.EndAt,
+		PreviousCapacity: sessionCapacity(session),
+	}
+
+	message := fmt.Sprintf("%s on %s has been canceled.", session.Course.CourseName, session.StartAt.Format("2006-01-02 15:04"))
+	if reason != "" {
+		message += " Reason: " + reason
+	}
+
+	notifications, err := dao.CancelSessionTx(tx, session, entry, message)
+	if err != nil {
+		return nil, err
+	}
+	// Members get back the credits they spent on the canceled session.
+	for _, notification := range notifications {
+		refundActor := actorID
+		if refundActor == 0 {
+			refundActor = notification.UserID
+		}
+		enrollment := &model.Enrollment{ID: notification.EnrollmentID, UserID: notification.UserID, SessionID: &session.ID}
+		if err := refundBookingTx(tx, enrollment, refundActor, "session canceled"); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func rescheduleSession(actorID uint, session *model.ClassSession, input model.SessionRescheduleInput) (*model.SessionChangeLog, error) {
+	if !input.EndAt.After(input.StartAt) {
+		return nil, errors.New("end_at must be after start_at")
+	}
+	if !input.StartAt.After(time.Now()) {
+		return nil, errors.New("start_at must be in the future")
+	}
+
+	taken, err := dao.CheckSessionDateTaken(session.CourseID, model.FacilityDate(input.StartAt), session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("another session of this class is already on that date")
+	}
+
+	previousCapacity := sessionCapacity(session)
+	capacity := previousCapacity
+	if input.Capacity != nil {
+		capacity = *input.Capacity
+	}
+
+	room, err := sessionRoom(session)
+	if err != nil {
+		return nil, err
+	}
+	if room != nil {
+		if capacity > room.Capacity {
+			return nil, errors.New("capacity exceeds room capacity")
+		}
+		if err := checkSessionRoomConflict(room.ID, session.ID, input.StartAt, input.EndAt); err != nil {
+			return nil, err
+		}
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	entry := &model.SessionChangeLog{
+		SessionID:        session.ID,
+		CourseID:         session.CourseID,
+		Action:           model.SessionChangeRescheduled,
+		ActorID:          actorID,
+		Reason:           reason,
+		PreviousStartAt:  session.StartAt,
+		PreviousEndAt:    session.EndAt,
+		PreviousCapacity: previousCapacity,
+		NewStartAt:       &input.StartAt,
+		NewEndAt:         &input.EndAt,
+		NewCapacity:      &capacity,
+	}
+
+	message := fmt.Sprintf("%s on %s has been moved to %s.", session.Course.CourseName,
+		session.StartAt.Format("2006-01-02 15:04"), input.StartAt.Format("2006-01-02 15:04"))
+	if reason != "" {
+		message += " Reason: " + reason
+	}
+
+	if _, err := dao.RescheduleSession(session, input.StartAt, input.EndAt, capacity, entry, message); err != nil {
+		return nil, err
+	}
+
+	// A larger capacity opens seats for the session's waitlist.
+	if capacity > previousCapacity {
+		moved, err := dao.GetSessionByID(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := promoteSessionWaitlist(moved); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
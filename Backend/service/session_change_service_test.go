@@ -0,0 +1,163 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func TestManagerCancelSession_CancelsEnrollmentsAndNotifies(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	manager := seedRoleAndUser(t, 3)
+	seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	second := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Yoga", 1, "Wellness")
+	session := seedSessionAt(t, course, time.Now().Add(48*time.Hour), 1)
+	seated := seedEnrollmentForSession(t, first.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+	queued := seedEnrollmentForSession(t, second.ID, course.ID, session.ID, model.EnrollmentStatusWaitlisted, time.Now())
+
+	change, err := ManagerCancelSession(manager.ID, session.ID, model.SessionCancelInput{Reason: "Studio closed"})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if change.Action != model.SessionChangeCanceled || change.AffectedEnrollments != 2 || change.ActorID != manager.ID {
+		t.Fatalf("unexpected change log entry: %+v", change)
+	}
+
+	reloaded := model.ClassSession{}
+	db.DB.First(&reloaded, session.ID)
+	if reloaded.Status != "canceled" {
+		t.Fatalf("expected session canceled, got %s", reloaded.Status)
+	}
+
+	for _, id := range []uint{seated.ID, queued.ID} {
+		enrollment := model.Enrollment{}
+		db.DB.First(&enrollment, id)
+		if enrollment.Status != model.EnrollmentStatusCanceled || enrollment.WaitlistPosition != nil {
+			t.Fatalf("expected enrollment %d canceled, got %+v", id, enrollment)
+		}
+	}
+
+	notifications, err := ListUserSessionNotifications(first.ID)
+	if err != nil {
+		t.Fatalf("failed to list notifications: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].Outcome != model.SessionNotificationCanceled {
+		t.Fatalf("expected one canceled notification, got %+v", notifications)
+	}
+
+	if _, err := ManagerCancelSession(manager.ID, session.ID, model.SessionCancelInput{}); err == nil || err.Error() != "session is not scheduled" {
+		t.Fatalf("expected session is not scheduled, got: %v", err)
+	}
+}
+
+func TestManagerRescheduleSession_MovesEnrollmentsAndOverridesCapacity(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	manager := seedRoleAndUser(t, 3)
+	seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	second := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 1, "Cardio")
+	if err := GenerateClassSessions(course.ID, 2); err != nil {
+		t.Fatalf("failed to generate sessions: %v", err)
+	}
+	var sessions []model.ClassSession
+	db.DB.Where("course_id = ?", course.ID).Order("session_date ASC").Find(&sessions)
+	if len(sessions) < 2 {
+		t.Fatalf("expected generated sessions, got %d", len(sessions))
+	}
+	session := sessions[len(sessions)-1]
+	originalDate := session.SessionDate
+	seated := seedEnrollmentForSession(t, first.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+	queued := seedEnrollmentForSession(t, second.ID, course.ID, session.ID, model.EnrollmentStatusWaitlisted, time.Now())
+
+	newStart := session.StartAt.AddDate(0, 0, 1)
+	capacity := 2
+	change, err := ManagerRescheduleSession(manager.ID, session.ID, model.SessionRescheduleInput{
+		StartAt:  newStart,
+		EndAt:    newStart.Add(time.Hour),
+		Capacity: &capacity,
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if change.Action != model.SessionChangeRescheduled || change.AffectedEnrollments != 2 {
+		t.Fatalf("unexpected change log entry: %+v", change)
+	}
+
+	reloaded := model.ClassSession{}
+	db.DB.First(&reloaded, session.ID)
+	if reloaded.SessionDate != newStart.Format("2006-01-02") || reloaded.Capacity != 2 {
+		t.Fatalf("expected session moved with capacity 2, got %+v", reloaded)
+	}
+	if reloaded.RescheduledFrom == nil || *reloaded.RescheduledFrom != originalDate {
+		t.Fatalf("expected rescheduled_from %s, got %v", originalDate, reloaded.RescheduledFrom)
+	}
+
+	for _, id := range []uint{seated.ID, queued.ID} {
+		enrollment := model.Enrollment{}
+		db.DB.First(&enrollment, id)
+		if enrollment.Status != model.EnrollmentStatusEnrolled || *enrollment.SessionID != session.ID {
+			t.Fatalf("expected enrollment %d to move along seated, got %+v", id, enrollment)
+		}
+	}
+
+	// The generator must not recreate a session on the original date.
+	if err := GenerateClassSessions(course.ID, 2); err != nil {
+		t.Fatalf("failed to regenerate sessions: %v", err)
+	}
+	var count int64
+	db.DB.Model(&model.ClassSession{}).Where("course_id = ? AND session_date = ?", course.ID, originalDate).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected original date to stay free, found %d sessions", count)
+	}
+}
+
+func TestRescheduleSession_RejectsTakenDate(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	manager := seedRoleAndUser(t, 3)
+	course := seedCourse(t, "Boxing", 5, "Combat")
+	session := seedSessionAt(t, course, time.Now().Add(72*time.Hour), 5)
+	other := seedSessionAt(t, course, time.Now().Add(120*time.Hour), 5)
+
+	_, err := ManagerRescheduleSession(manager.ID, session.ID, model.SessionRescheduleInput{
+		StartAt: other.StartAt.Add(time.Hour),
+		EndAt:   other.StartAt.Add(2 * time.Hour),
+	})
+	if err == nil || err.Error() != "another session of this class is already on that date" {
+		t.Fatalf("expected taken date error, got: %v", err)
+	}
+
+	_, err = ManagerRescheduleSession(manager.ID, session.ID, model.SessionRescheduleInput{
+		StartAt: time.Now().Add(-time.Hour),
+		EndAt:   time.Now(),
+	})
+	if err == nil || err.Error() != "start_at must be in the future" {
+		t.Fatalf("expected start_at must be in the future, got: %v", err)
+	}
+}
+
+func TestInstructorCancelSession_RequiresOwnership(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	instructor := seedRoleAndUser(t, 4)
+	course := seedCourse(t, "Pilates", 5, "Wellness")
+	session := seedSessionAt(t, course, time.Now().Add(48*time.Hour), 5)
+
+	if _, err := InstructorCancelSession(instructor.ID, session.ID, model.SessionCancelInput{}); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("expected forbidden, got: %v", err)
+	}
+
+	profile := seedInstructorProfile(t, instructor)
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Update("instructor_id", profile.ID)
+
+	if _, err := InstructorCancelSession(instructor.ID, session.ID, model.SessionCancelInput{}); err != nil {
+		t.Fatalf("expected success for own course, got error: %v", err)
+	}
+}
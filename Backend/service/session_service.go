@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"my-course-backend/dao"
 	"my-course-backend/db"
@@ -11,53 +12,108 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-// GenerateClassSessions creates ClassSession rows for the given course for the next N weeks.
+// GenerateClassSessions creates ClassSession rows for every weekly slot of the given course for the next N weeks.
 // This should be called when a course is created or updated.
 // numWeeks: how many weeks ahead to generate sessions (typically 8-12)
 func GenerateClassSessions(courseID uint, numWeeks int) error {
-	if numWeeks < 1 {
-		numWeeks = 12 // default to 12 weeks
-	}
-
 	// Fetch the course
 	course, err := dao.GetCourseByID(courseID)
 	if err != nil {
 		return fmt.Errorf("course not found: %w", err)
 	}
 
-	// Parse course weekday (e.g., "Monday", "Mon")
-	targetWeekday := normalizeWeekdayForGeneration(course.Weekday)
-	if targetWeekday == "" {
-		return fmt.Errorf("invalid weekday: %s", course.Weekday)
+	sessions, err := planClassSessions(course, numWeeks)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		// Insert or update (upsert)
+		if err := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&sessions[i]).Error; err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
 	}
 
-	// Generate sessions starting from today
-	today := time.Now().UTC()
-	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	// Extend active series bookings onto the newly generated weeks.
+	if err := SyncCourseBookingSeries(courseID); err != nil {
+		return fmt.Errorf("failed to sync booking series: %w", err)
+	}
 
-	// Find the first occurrence of the target weekday from today
-	firstSessionDate := getNextOccurrenceOfWeekday(today, targetWeekday)
+	return nil
+}
 
-	// Generate session rows for numWeeks
-	for i := 0; i < numWeeks; i++ {
-		sessionDate := firstSessionDate.AddDate(0, 0, i*7) // add i weeks
+// planClassSessions returns the sessions every weekly slot of the course should have over the next N weeks,
+// skipping blackout dates and dates whose session was moved. Existing sessions are not checked.
+func planClassSessions(course *model.Course, numWeeks int) ([]model.ClassSession, error) {
+	if numWeeks < 1 {
+		numWeeks = 12 // default to 12 weeks
+	}
 
-		session := &model.ClassSession{
-			CourseID:    courseID,
-			SessionDate: sessionDate.Format("2006-01-02"),
-			StartAt:     combineDateTime(sessionDate, course.StartTime),
-			EndAt:       combineDateTime(sessionDate, course.EndTime),
-			Status:      "scheduled",
-			Capacity:    course.Capacity,
-		}
+	slots := courseSlots(course)
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("invalid weekday: %s", course.Weekday)
+	}
 
-		// Insert or update (upsert)
-		if err := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(session).Error; err != nil {
-			return fmt.Errorf("failed to create session: %w", err)
+	// Generate sessions starting from today in the facility time zone, so each session keeps
+	// its wall-clock time across DST transitions.
+	loc := model.FacilityLocation()
+	today := model.FacilityNow()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+
+	// Sessions start no earlier than the course term and stay within the horizon.
+	from := today
+	horizonEnd := today.AddDate(0, 0, numWeeks*7)
+	startsOn, endsOn := courseTermBounds(course)
+	if startsOn != "" {
+		if termStart, err := time.ParseInLocation("2006-01-02", startsOn, loc); err == nil && termStart.After(from) {
+			from = termStart
 		}
 	}
 
-	return nil
+	// Dates whose session was moved elsewhere must not be generated again.
+	movedDates, err := dao.ListRescheduledSessionDates(course.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rescheduled sessions: %w", err)
+	}
+
+	// No sessions run on blackout dates.
+	blackouts, err := dao.ListBlackoutDateSet(today.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blackout dates: %w", err)
+	}
+
+	var sessions []model.ClassSession
+	for _, slot := range slots {
+		// Parse slot weekday (e.g., "Monday", "Mon")
+		targetWeekday := normalizeWeekdayForGeneration(slot.Weekday)
+		if _, ok := validWeekdays[targetWeekday]; !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", slot.Weekday)
+		}
+
+		// Find the first occurrence of the target weekday from the first day sessions may run
+		firstSessionDate := getNextOccurrenceOfWeekday(from, targetWeekday)
+
+		// Generate session rows for numWeeks
+		for i := 0; i < numWeeks; i++ {
+			sessionDate := firstSessionDate.AddDate(0, 0, i*7) // add i weeks
+			day := sessionDate.Format("2006-01-02")
+			if !sessionDate.Before(horizonEnd) || (endsOn != "" && day > endsOn) {
+				break
+			}
+			if movedDates[day] || blackouts[day] {
+				continue
+			}
+
+			sessions = append(sessions, model.ClassSession{
+				CourseID:    course.ID,
+				SessionDate: day,
+				StartAt:     combineDateTime(sessionDate, slot.StartTime),
+				EndAt:       combineDateTime(sessionDate, slot.EndTime),
+				Status:      "scheduled",
+				Capacity:    course.Capacity,
+			})
+		}
+	}
+	return sessions, nil
 }
 
 // getNextOccurrenceOfWeekday returns the next date that is the target weekday, starting from fromDate.
@@ -108,3 +164,195 @@ func combineDateTime(date time.Time, timeOnly model.TimeOnly) time.Time {
 	hour, minute, second := timeOnly.Time.Clock()
 	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, date.Location())
 }
+
+// sessionCapacity returns the session's capacity override, falling back to the course capacity.
+func sessionCapacity(session *model.ClassSession) int {
+	if session.Capacity > 0 {
+		return session.Capacity
+	}
+	return session.Course.Capacity
+}
+
+// fillSessionSpots populates Spot for each session with a single aggregated count query.
+func fillSessionSpots(sessions []model.ClassSession) error {
+	ids := make([]uint, 0, len(sessions))
+	for i := range sessions {
+		ids = append(ids, sessions[i].ID)
+	}
+
+	counts, err := dao.CountSeatedEnrollmentsBySessions(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		spot := sessionCapacity(&sessions[i]) - int(counts[sessions[i].ID])
+		if spot < 0 {
+			spot = 0
+		}
+		sessions[i].Spot = spot
+	}
+	return nil
+}
+
+// ListClassSessions returns the upcoming sessions of a course with per-session spot counts.
+func ListClassSessions(courseID uint) ([]model.ClassSession, error) {
+	if _, err := dao.GetCourseByID(courseID); err != nil {
+		return nil, errors.New("class not found")
+	}
+
+	sessions, err := dao.ListUpcomingSessionsByCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillSessionSpots(sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RegisterSession enrolls a user in a specific class session.
+// The enrollment window, duplicate, overlap and capacity checks all run against that session.
+func RegisterSession(userID uint, sessionID uint) error {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	session, err := loadBookableSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := checkMemberStanding(userID, now); err != nil {
+		return err
+	}
+
+	policy := courseBookingPolicy(&session.Course)
+	if err := validateSessionWindow(session, policy, now); err != nil {
+		return err
+	}
+
+	hasOverlap, err := hasSessionOverlap(userID, session)
+	if err != nil {
+		return err
+	}
+	if hasOverlap {
+		return errors.New("class schedule overlaps with an existing enrolled class")
+	}
+
+	if err := checkBookingLimits(userID, policy, session, now); err != nil {
+		return err
+	}
+	if err := checkEntitlement(userID); err != nil {
+		return err
+	}
+
+	enrollment, err := bookSession(userID, session)
+	if err != nil {
+		return err
+	}
+	if err := chargeBooking(enrollment); err != nil {
+		return err
+	}
+
+	return dao.BackfillUserDailyActivityFromEnrollments(userID)
+}
+
+// DropSession removes a user's enrollment (or waitlist entry) for a specific session.
+// Seated enrollments cannot be dropped inside the booking policy's late-cancel cutoff.
+// The booking's credit is refunded unless the drop is a late cancel.
+func DropSession(userID uint, sessionID uint) error {
+	enrollment, err := dao.GetActiveSessionEnrollment(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := checkCancelCutoff(enrollment, now); err != nil {
+		return err
+	}
+
+	removed, err := dao.DeleteSessionEnrollment(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := recordLateCancel(enrollment, now); err != nil {
+		return err
+	}
+	if err := refundDrop(enrollment, now); err != nil {
+		return err
+	}
+	if err := skipSeriesSession(removed); err != nil {
+		return err
+	}
+	return releaseSeat(removed)
+}
+
+// loadBookableSession returns a session that is still open for booking.
+func loadBookableSession(sessionID uint) (*model.ClassSession, error) {
+	session, err := dao.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.Status != "scheduled" {
+		return nil, errors.New("session is not open for registration")
+	}
+	return session, nil
+}
+
+// bookSession checks duplicates and capacity for one session and creates the enrollment.
+func bookSession(userID uint, session *model.ClassSession) (*model.Enrollment, error) {
+	exists, err := dao.CheckSessionEnrollmentExists(userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("enrollment already exists")
+	}
+
+	count, err := dao.CountEnrollmentsBySession(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if int(count) >= sessionCapacity(session) {
+		return nil, errors.New("class is full")
+	}
+
+	enrollment := &model.Enrollment{
+		UserID:    userID,
+		CourseID:  session.CourseID,
+		SessionID: &session.ID,
+		Status:    model.EnrollmentStatusEnrolled,
+	}
+	if err := dao.CreateEnrollment(enrollment); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// hasSessionOverlap reports whether the session's time range overlaps another session the user is seated in.
+func hasSessionOverlap(userID uint, target *model.ClassSession) (bool, error) {
+	sessions, err := dao.ListEnrolledSessionsByUser(userID)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range sessions {
+		existing := &sessions[i]
+		if existing.ID == target.ID {
+			continue
+		}
+		if existing.StartAt.Before(target.EndAt) && target.StartAt.Before(existing.EndAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateSessionWindow enforces the booking policy's window against the session start.
+func validateSessionWindow(session *model.ClassSession, policy *model.BookingPolicy, now time.Time) error {
+	if session.StartAt.IsZero() {
+		return errors.New("invalid class schedule")
+	}
+	return checkBookingWindow(policy, session.StartAt, now)
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// seedSessionAt creates a scheduled session for a course starting at the given time.
+func seedSessionAt(t *testing.T, course model.Course, startAt time.Time, capacity int) model.ClassSession {
+	t.Helper()
+	session := model.ClassSession{
+		CourseID:    course.ID,
+		SessionDate: startAt.Format("2006-01-02"),
+		StartAt:     startAt,
+		EndAt:       startAt.Add(time.Hour),
+		Status:      "scheduled",
+		Capacity:    capacity,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		t.Fatalf("failed to seed session: %v", err)
+	}
+	return session
+}
+
+func TestRegisterSession_BooksChosenSession(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Yoga", 3, "Wellness")
+	target := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 3)
+
+	if err := RegisterSession(user.ID, target.ID); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	enrollment := loadEnrollment(t, user.ID, course.ID)
+	if enrollment.SessionID == nil || *enrollment.SessionID != target.ID {
+		t.Fatalf("expected enrollment for session %d, got %v", target.ID, enrollment.SessionID)
+	}
+
+	if err := RegisterSession(user.ID, target.ID); err == nil || err.Error() != "enrollment already exists" {
+		t.Fatalf("expected enrollment already exists, got: %v", err)
+	}
+}
+
+func TestRegisterSession_UsesSessionCapacity(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	user := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 5, "Cardio")
+	target := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 1)
+	seedEnrollmentForSession(t, seated.ID, course.ID, target.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	err := RegisterSession(user.ID, target.ID)
+	if err == nil || err.Error() != "class is full" {
+		t.Fatalf("expected class is full, got: %v", err)
+	}
+}
+
+func TestRegisterSession_ScheduleOverlap(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	existingCourse := seedCourse(t, "Morning Yoga", 5, "Wellness")
+	targetCourse := seedCourse(t, "Strength Flow", 5, "Strength")
+
+	start := time.Now().Add(3 * time.Hour)
+	existing := seedSessionAt(t, existingCourse, start, 5)
+	target := seedSessionAt(t, targetCourse, start.Add(30*time.Minute), 5)
+	seedEnrollmentForSession(t, user.ID, existingCourse.ID, existing.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	err := RegisterSession(user.ID, target.ID)
+	if err == nil || err.Error() != "class schedule overlaps with an existing enrolled class" {
+		t.Fatalf("expected schedule overlap error, got: %v", err)
+	}
+}
+
+func TestRegisterSession_OutsideEnrollmentWindow(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Pilates", 5, "Core")
+	later := seedSessionAt(t, course, time.Now().AddDate(0, 0, 14), 5)
+
+	err := RegisterSession(user.ID, later.ID)
+	if err == nil || err.Error() != "enrollment opens 25 hours before class start." {
+		t.Fatalf("expected enrollment window error, got: %v", err)
+	}
+
+	// Managers bypass the window and can book weeks ahead.
+	if err := ManagerAddUserSessionEnrollment(user.ID, later.ID); err != nil {
+		t.Fatalf("expected manager booking to succeed, got: %v", err)
+	}
+}
+
+func TestListClassSessions_ReturnsSpotPerSession(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "HIIT", 3, "Cardio")
+	later := seedSessionAt(t, course, time.Now().AddDate(0, 0, 8), 2)
+	seedEnrollmentForSession(t, user.ID, course.ID, later.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	sessions, err := ListClassSessions(course.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	spots := map[uint]int{}
+	for _, s := range sessions {
+		spots[s.ID] = s.Spot
+	}
+	if spots[later.ID] != 1 {
+		t.Fatalf("expected later session spot 1, got %d", spots[later.ID])
+	}
+	if spots[sessions[0].ID] != 3 {
+		t.Fatalf("expected next session spot 3, got %d", spots[sessions[0].ID])
+	}
+}
+
+func TestDropSession_RemovesOnlyThatSession(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Boxing", 3, "Combat")
+	first := seedSessionAt(t, course, time.Now().AddDate(0, 0, 7), 3)
+	second := seedSessionAt(t, course, time.Now().AddDate(0, 0, 14), 3)
+	seedEnrollmentForSession(t, user.ID, course.ID, first.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, user.ID, course.ID, second.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if err := DropSession(user.ID, second.ID); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	var remaining []model.Enrollment
+	if err := db.DB.Where("user_id = ?", user.ID).Find(&remaining).Error; err != nil {
+		t.Fatalf("failed to load enrollments: %v", err)
+	}
+	if len(remaining) != 1 || *remaining[0].SessionID != first.ID {
+		t.Fatalf("expected only session %d to remain, got %+v", first.ID, remaining)
+	}
+
+	if err := DropSession(user.ID, second.ID); err == nil || err.Error() != "enrollment not found" {
+		t.Fatalf("expected enrollment not found, got: %v", err)
+	}
+}
+
+// useFacilityZone switches the facility time zone for one test.
+func useFacilityZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	if err := model.LoadFacilityLocation(name); err != nil {
+		t.Fatalf("failed to load time zone %s: %v", name, err)
+	}
+	t.Cleanup(func() { model.SetFacilityLocation(nil) })
+	return model.FacilityLocation()
+}
+
+func TestCombineDateTime_KeepsWallClockAcrossDST(t *testing.T) {
+	loc := useFacilityZone(t, "America/New_York")
+	start, err := model.ParseTimeOnly("07:00")
+	if err != nil {
+		t.Fatalf("failed to parse time: %v", err)
+	}
+
+	// US daylight saving time starts on 2026-03-08.
+	before := combineDateTime(time.Date(2026, 3, 7, 0, 0, 0, 0, loc), start)
+	after := combineDateTime(time.Date(2026, 3, 9, 0, 0, 0, 0, loc), start)
+	if got := before.UTC().Format("15:04"); got != "12:00" {
+		t.Fatalf("expected 07:00 EST to be 12:00 UTC, got %s", got)
+	}
+	if got := after.UTC().Format("15:04"); got != "11:00" {
+		t.Fatalf("expected 07:00 EDT to be 11:00 UTC, got %s", got)
+	}
+}
+
+func TestValidateEnrollmentWindow_AcrossDSTFallBack(t *testing.T) {
+	loc := useFacilityZone(t, "America/New_York")
+	start, _ := model.ParseTimeOnly("07:00")
+	end, _ := model.ParseTimeOnly("08:00")
+	class := &model.Course{Slots: []model.CourseSlot{{Weekday: "Sunday", StartTime: start, EndTime: end}}}
+
+	// Clocks fall back on 2026-11-01, so Saturday 07:00 EDT is 25 real hours before Sunday 07:00 EST.
+	justOpen := time.Date(2026, 10, 31, 7, 0, 0, 0, loc)
+	if err := validateEnrollmentWindow(class, &DefaultBookingPolicy, justOpen.UTC()); err != nil {
+		t.Fatalf("expected enrollment to be open, got: %v", err)
+	}
+
+	tooEarly := time.Date(2026, 10, 31, 6, 30, 0, 0, loc)
+	if err := validateEnrollmentWindow(class, &DefaultBookingPolicy, tooEarly.UTC()); err == nil || err.Error() != "enrollment opens 25 hours before class start." {
+		t.Fatalf("expected enrollment window error, got: %v", err)
+	}
+}
+
+func TestClassSession_StoredInUTCAndReadInFacilityZone(t *testing.T) {
+	setupClassServiceTestDB(t)
+	loc := useFacilityZone(t, "Europe/Berlin")
+
+	course := seedCourse(t, "Pilates", 5, "Wellness")
+	// Central European Summer Time ends on 2026-10-25.
+	summer := seedSessionAt(t, course, time.Date(2026, 10, 24, 18, 0, 0, 0, loc), 5)
+	winter := seedSessionAt(t, course, time.Date(2026, 10, 26, 18, 0, 0, 0, loc), 5)
+
+	var raw []string
+	db.DB.Raw("SELECT start_at FROM ClassSession WHERE id IN (?, ?) ORDER BY id", summer.ID, winter.ID).Scan(&raw)
+	if len(raw) != 2 || raw[0] != "2026-10-24T16:00:00Z" || raw[1] != "2026-10-26T17:00:00Z" {
+		t.Fatalf("expected UTC start times in the database, got %v", raw)
+	}
+
+	var sessions []model.ClassSession
+	db.DB.Where("id IN ?", []uint{summer.ID, winter.ID}).Order("id").Find(&sessions)
+	for _, s := range sessions {
+		if s.StartAt.Location() != loc || s.StartAt.Format("15:04") != "18:00" {
+			t.Fatalf("expected 18:00 in the facility zone, got %s", s.StartAt)
+		}
+	}
+	if _, offset := sessions[0].StartAt.Zone(); offset != 2*3600 {
+		t.Fatalf("expected summer session at +02:00, got offset %d", offset)
+	}
+	if _, offset := sessions[1].StartAt.Zone(); offset != 3600 {
+		t.Fatalf("expected winter session at +01:00, got offset %d", offset)
+	}
+}
+
+func TestGenerateClassSessions_UsesFacilityWallClock(t *testing.T) {
+	setupClassServiceTestDB(t)
+	loc := useFacilityZone(t, "Pacific/Auckland")
+
+	course, err := ManagerCreateCourse(CourseUpsertInput{
+		CourseName: "Sunrise Yoga",
+		CourseCode: "SUN-YOGA",
+		Capacity:   8,
+		Slots:      []model.CourseSlotInput{{Weekday: "Sunday", StartTime: "06:30", EndTime: "07:30"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create course: %v", err)
+	}
+
+	var sessions []model.ClassSession
+	db.DB.Where("course_id = ?", course.ID).Find(&sessions)
+	if len(sessions) == 0 {
+		t.Fatal("expected generated sessions")
+	}
+	for _, s := range sessions {
+		local := s.StartAt.In(loc)
+		if local.Weekday() != time.Sunday || local.Format("15:04") != "06:30" || local.Format("2006-01-02") != s.SessionDate {
+			t.Fatalf("expected Sunday 06:30 on %s in the facility zone, got %s", s.SessionDate, local)
+		}
+	}
+}
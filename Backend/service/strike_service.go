@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/model"
+)
+
+// StrikeSettings configures the no-show and late-cancel penalty.
+type StrikeSettings struct {
+	// Limit is how many strikes within Window suspend new bookings; 0 never suspends.
+	Limit int
+	// Window is the rolling period strikes count for.
+	Window time.Duration
+	// LateCancelWindow is how close to a session's start dropping a seat counts as a late cancel; 0 records none.
+	LateCancelWindow time.Duration
+}
+
+// DefaultStrikeSettings suspends booking after 3 strikes in 30 days; drops within 12 hours of the start are late cancels.
+var DefaultStrikeSettings = StrikeSettings{Limit: 3, Window: 30 * 24 * time.Hour, LateCancelWindow: 12 * time.Hour}
+
+var strikeSettings = DefaultStrikeSettings
+
+// SetStrikeSettings replaces the strike settings.
+func SetStrikeSettings(settings StrikeSettings) {
+	strikeSettings = settings
+}
+
+// GetMemberStanding returns the member's strikes within the rolling window and whether booking is suspended.
+func GetMemberStanding(userID uint) (*model.MemberStanding, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return memberStanding(userID, time.Now())
+}
+
+// ManagerListMemberStrikes returns the member's standing and every strike on record, cleared or not.
+func ManagerListMemberStrikes(userID uint) (*model.MemberStanding, []model.MemberStrike, error) {
+	standing, err := GetMemberStanding(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := dao.ListStrikesByUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return standing, history, nil
+}
+
+// ManagerClearMemberStrikes forgives the member's uncleared strikes, or only strikeID when it is non-zero.
+// Returns the updated standing.
+func ManagerClearMemberStrikes(managerID uint, userID uint, strikeID uint) (*model.MemberStanding, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	if strikeID != 0 {
+		strike, err := dao.GetMemberStrikeByID(strikeID)
+		if err != nil || strike.UserID != userID {
+			return nil, errors.New("strike not found")
+		}
+		if strike.ClearedAt != nil {
+			return nil, errors.New("strike already cleared")
+		}
+	}
+
+	now := time.Now()
+	if _, err := dao.ClearMemberStrikes(userID, strikeID, managerID, now); err != nil {
+		return nil, err
+	}
+	return memberStanding(userID, now)
+}
+
+func memberStanding(userID uint, now time.Time) (*model.MemberStanding, error) {
+	settings := strikeSettings
+	strikes, err := dao.ListActiveStrikesByUser(userID, now.Add(-settings.Window))
+	if err != nil {
+		return nil, err
+	}
+	if strikes == nil {
+		strikes = []model.MemberStrike{}
+	}
+
+	standing := &model.MemberStanding{
+		Strikes:       strikes,
+		ActiveStrikes: len(strikes),
+		StrikeLimit:   settings.Limit,
+		WindowDays:    int(settings.Window / (24 * time.Hour)),
+	}
+	// Booking reopens once enough strikes age out of the window to fall below the limit.
+	if settings.Limit > 0 && len(strikes) >= settings.Limit {
+		until := strikes[len(strikes)-settings.Limit].OccurredAt.Add(settings.Window).In(model.FacilityLocation())
+		standing.Blocked = true
+		standing.BlockedUntil = &until
+	}
+	return standing, nil
+}
+
+// checkMemberStanding refuses new bookings while the member's strikes are at the limit.
+func checkMemberStanding(userID uint, now time.Time) error {
+	if strikeSettings.Limit <= 0 {
+		return nil
+	}
+	standing, err := memberStanding(userID, now)
+	if err != nil {
+		return err
+	}
+	if !standing.Blocked {
+		return nil
+	}
+	return &BookingPolicyError{
+		Code: BookingCodeSuspended,
+		Message: fmt.Sprintf("bookings are suspended until %s after repeated no-shows or late cancellations",
+			standing.BlockedUntil.Format(time.RFC3339)),
+	}
+}
+
+// isLateCancel reports whether dropping a seated enrollment now falls within the late-cancel window.
+func isLateCancel(enrollment *model.Enrollment, now time.Time) bool {
+	if strikeSettings.LateCancelWindow <= 0 || enrollment.Status != model.EnrollmentStatusEnrolled || enrollment.Session == nil {
+		return false
+	}
+	return !now.Before(enrollment.Session.StartAt.Add(-strikeSettings.LateCancelWindow))
+}
+
+// recordLateCancel adds a strike when a seated enrollment is dropped within the late-cancel window.
+func recordLateCancel(enrollment *model.Enrollment, now time.Time) error {
+	if !isLateCancel(enrollment, now) {
+		return nil
+	}
+
+	return dao.CreateMemberStrike(&model.MemberStrike{
+		UserID:     enrollment.UserID,
+		CourseID:   enrollment.CourseID,
+		SessionID:  enrollment.SessionID,
+		Reason:     model.StrikeReasonLateCancel,
+		OccurredAt: now,
+	})
+}
+
+// syncNoShowStrikes gives each missed enrollment one no-show strike and withdraws uncleared strikes
+// of enrollments whose status was corrected. sessionIDs nil means the whole course.
+func syncNoShowStrikes(userID uint, courseID uint, sessionIDs []uint) error {
+	enrollments, err := dao.ListEnrollmentsForStatusUpdate(userID, courseID, sessionIDs)
+	if err != nil {
+		return err
+	}
+
+	var corrected []uint
+	for i := range enrollments {
+		enrollment := &enrollments[i]
+		if enrollment.Status != model.EnrollmentStatusMissed {
+			corrected = append(corrected, enrollment.ID)
+			continue
+		}
+
+		if err := addNoShowStrike(enrollment); err != nil {
+			return err
+		}
+	}
+	return dao.DeleteNoShowStrikes(corrected)
+}
+
+// addNoShowStrike gives a missed enrollment its no-show strike unless it already has one.
+func addNoShowStrike(enrollment *model.Enrollment) error {
+	exists, err := dao.CheckNoShowStrikeExists(enrollment.ID)
+	if err != nil || exists {
+		return err
+	}
+
+	occurredAt := time.Now()
+	if enrollment.Session != nil {
+		occurredAt = enrollment.Session.StartAt
+	}
+	return dao.CreateMemberStrike(&model.MemberStrike{
+		UserID:       enrollment.UserID,
+		CourseID:     enrollment.CourseID,
+		SessionID:    enrollment.SessionID,
+		EnrollmentID: &enrollment.ID,
+		Reason:       model.StrikeReasonNoShow,
+		OccurredAt:   occurredAt,
+	})
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+func useStrikeSettings(t *testing.T, settings StrikeSettings) {
+	t.Helper()
+	SetStrikeSettings(settings)
+	t.Cleanup(func() { SetStrikeSettings(DefaultStrikeSettings) })
+}
+
+func TestNoShowStrikes_SuspendBookingUntilCleared(t *testing.T) {
+	setupClassServiceTestDB(t)
+	useStrikeSettings(t, StrikeSettings{Limit: 2, Window: 30 * 24 * time.Hour, LateCancelWindow: 12 * time.Hour})
+
+	student := seedRoleAndUser(t, 1)
+	manager := seedRoleAndUser(t, 3)
+	teacher := seedRoleAndUser(t, 4)
+	profile := seedInstructorProfile(t, teacher)
+
+	course := seedCourse(t, "Boxing", 5, "Combat")
+	db.DB.Model(&model.Course{}).Where("id = ?", course.ID).Update("instructor_id", profile.ID)
+	first := seedPastSession(t, course, 2)
+	second := seedPastSession(t, course, 5)
+	seedEnrollmentForSession(t, student.ID, course.ID, first.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, student.ID, course.ID, second.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	markMissed := func(sessionID uint, status string) {
+		t.Helper()
+		if err := UpdateEnrollmentStatusByInstructor(teacher.ID, course.ID, student.ID, status, &sessionID); err != nil {
+			t.Fatalf("failed to mark %s: %v", status, err)
+		}
+	}
+	markMissed(first.ID, model.EnrollmentStatusMissed)
+	markMissed(first.ID, model.EnrollmentStatusMissed)
+
+	standing, err := GetMemberStanding(student.ID)
+	if err != nil || standing.ActiveStrikes != 1 || standing.Blocked {
+		t.Fatalf("expected one strike and no suspension, got %+v (err %v)", standing, err)
+	}
+
+	markMissed(second.ID, model.EnrollmentStatusMissed)
+	upcoming := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 5)
+	expectPolicyCode(t, RegisterSession(student.ID, upcoming.ID), BookingCodeSuspended)
+
+	// Correcting the attendance withdraws the strike.
+	markMissed(second.ID, model.EnrollmentStatusAttended)
+	if standing, _ := GetMemberStanding(student.ID); standing.ActiveStrikes != 1 || standing.Blocked {
+		t.Fatalf("expected the corrected strike to be withdrawn, got %+v", standing)
+	}
+
+	markMissed(second.ID, model.EnrollmentStatusMissed)
+	standing, err = ManagerClearMemberStrikes(manager.ID, student.ID, 0)
+	if err != nil || standing.ActiveStrikes != 0 || standing.Blocked {
+		t.Fatalf("expected strikes cleared, got %+v (err %v)", standing, err)
+	}
+	if err := RegisterSession(student.ID, upcoming.ID); err != nil {
+		t.Fatalf("expected booking after clearing strikes, got: %v", err)
+	}
+
+	_, history, err := ManagerListMemberStrikes(student.ID)
+	if err != nil || len(history) != 2 || history[0].ClearedBy == nil || *history[0].ClearedBy != manager.ID {
+		t.Fatalf("expected two cleared strikes in the history, got %+v (err %v)", history, err)
+	}
+}
+
+func TestDropSession_RecordsLateCancelStrike(t *testing.T) {
+	setupClassServiceTestDB(t)
+	useStrikeSettings(t, StrikeSettings{Limit: 3, Window: 30 * 24 * time.Hour, LateCancelWindow: 12 * time.Hour})
+
+	student := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 1, "Cardio")
+	soon := seedSessionAt(t, course, time.Now().Add(3*time.Hour), 1)
+	later := seedSessionAt(t, course, time.Now().Add(30*time.Hour), 1)
+	seedEnrollmentForSession(t, student.ID, course.ID, soon.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, student.ID, course.ID, later.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, waiting.ID, course.ID, later.ID, model.EnrollmentStatusWaitlisted, time.Now())
+
+	for _, sessionID := range []uint{soon.ID, later.ID} {
+		if err := DropSession(student.ID, sessionID); err != nil {
+			t.Fatalf("failed to drop session %d: %v", sessionID, err)
+		}
+	}
+	if err := DropSession(waiting.ID, later.ID); err != nil {
+		t.Fatalf("failed to leave the waitlist: %v", err)
+	}
+
+	standing, err := GetMemberStanding(student.ID)
+	if err != nil || standing.ActiveStrikes != 1 || standing.Strikes[0].Reason != model.StrikeReasonLateCancel {
+		t.Fatalf("expected one late-cancel strike, got %+v (err %v)", standing, err)
+	}
+	if standing, _ := GetMemberStanding(waiting.ID); standing.ActiveStrikes != 0 {
+		t.Fatalf("expected no strike for leaving a waitlist, got %+v", standing)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"my-course-backend/model"
+	"my-course-backend/tokens"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DevJWTKeyID is the kid of the dev signing key.
+const DevJWTKeyID = "dev"
+
+// TokenSettings configures the access tokens FitFlow issues and accepts.
+type TokenSettings struct {
+	// Issuer and Audience are set as iss and aud on new tokens and required on incoming ones.
+	Issuer   string
+	Audience string
+	// TTL is how long a new access token is valid. Access tokens cannot be revoked, so a role change
+	// reaches a signed-in user within one TTL.
+	TTL time.Duration
+	// RefreshTTL is how long a session can go without refreshing before its refresh token expires.
+	RefreshTTL time.Duration
+}
+
+// DefaultTokenSettings issues tokens for FitFlow itself: access tokens for 48 hours, refresh tokens for 30 days.
+// The web client does not refresh access tokens yet, so they keep the lifetime sessions had before refresh
+// tokens; deployments whose clients refresh should set FITFLOW_JWT_TTL to a few minutes.
+var DefaultTokenSettings = TokenSettings{
+	Issuer:     "fitflow",
+	Audience:   "fitflow",
+	TTL:        48 * time.Hour,
+	RefreshTTL: 30 * 24 * time.Hour,
+}
+
+var (
+	tokenSettings = DefaultTokenSettings
+	tokenKeys     = NewDevKeyring()
+)
+
+// SetTokenSettings replaces the access token settings.
+func SetTokenSettings(settings TokenSettings) {
+	tokenSettings = settings
+}
+
+// SetTokenKeys replaces the keys access tokens are signed and verified with.
+func SetTokenKeys(keys *tokens.Keyring) {
+	tokenKeys = keys
+}
+
+// NewDevKeyring returns a keyring with a random HS256 key for development. Tokens signed with it
+// stop verifying when the process restarts.
+func NewDevKeyring() *tokens.Keyring {
+	secret := make([]byte, tokens.MinHMACSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	key, err := tokens.NewHMACKey(DevJWTKeyID, secret)
+	if err != nil {
+		panic(err)
+	}
+	keys, err := tokens.NewKeyring(DevJWTKeyID, key)
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// accessClaims are the claims of a FitFlow access token. id, email and role_id predate the
+// registered claims and stay for clients that read them.
+type accessClaims struct {
+	UserID uint   `json:"id"`
+	Email  string `json:"email"`
+	RoleID uint   `json:"role_id"`
+	jwt.RegisteredClaims
+}
+
+// IssueAccessToken signs a new access token for a user with the current signing key.
+func IssueAccessToken(userID uint, email string, roleID uint) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	return tokenKeys.Sign(accessClaims{
+		UserID: userID,
+		Email:  email,
+		RoleID: roleID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenSettings.Issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			Audience:  jwt.ClaimStrings{tokenSettings.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(tokenSettings.TTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        tokenID,
+		},
+	})
+}
+
+// ParseAccessToken verifies an access token and returns the caller it identifies. The token must be
+// signed by a key in the keyring and carry the configured issuer and audience, iat, exp and jti.
+func ParseAccessToken(tokenString string) (*model.Principal, error) {
+	claims := &accessClaims{}
+	parsed, err := jwt.ParseWithClaims(tokenString, claims, tokenKeys.Keyfunc,
+		jwt.WithValidMethods(tokenKeys.Algorithms()),
+		jwt.WithIssuer(tokenSettings.Issuer),
+		jwt.WithAudience(tokenSettings.Audience),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !parsed.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.ID == "" || claims.IssuedAt == nil || claims.UserID == 0 {
+		return nil, errors.New("invalid token")
+	}
+	return &model.Principal{UserID: claims.UserID, RoleID: claims.RoleID, TokenID: claims.ID}, nil
+}
+
+// TokenJWKS returns the public keys other services can verify FitFlow tokens with.
+func TokenJWKS() tokens.JWKS {
+	return tokenKeys.JWKS()
+}
+
+// newTokenID returns a random jti for a new token.
+func newTokenID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
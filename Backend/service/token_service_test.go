@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"my-course-backend/tokens"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func useTokenSettings(t *testing.T, settings TokenSettings) {
+	t.Helper()
+	SetTokenSettings(settings)
+	t.Cleanup(func() { SetTokenSettings(DefaultTokenSettings) })
+}
+
+// useDevSecret signs and verifies tokens with a known dev key for the rest of the test.
+func useDevSecret(t *testing.T, secret []byte) {
+	t.Helper()
+	key, err := tokens.NewHMACKey(DevJWTKeyID, secret)
+	if err != nil {
+		t.Fatalf("failed to build key: %v", err)
+	}
+	keys, _ := tokens.NewKeyring(DevJWTKeyID, key)
+	SetTokenKeys(keys)
+	t.Cleanup(func() { SetTokenKeys(NewDevKeyring()) })
+}
+
+func TestAccessToken_ValidatesRegisteredClaims(t *testing.T) {
+	issued, err := IssueAccessToken(7, "member@example.com", 1)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	principal, err := ParseAccessToken(issued)
+	if err != nil {
+		t.Fatalf("expected the token to parse, got %v", err)
+	}
+	if principal.UserID != 7 || principal.RoleID != 1 || principal.TokenID == "" {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+
+	useTokenSettings(t, TokenSettings{Issuer: "fitflow", Audience: "billing", TTL: time.Hour})
+	if _, err := ParseAccessToken(issued); err == nil {
+		t.Fatalf("expected a token for another audience to be rejected")
+	}
+	useTokenSettings(t, TokenSettings{Issuer: "other", Audience: "fitflow", TTL: time.Hour})
+	if _, err := ParseAccessToken(issued); err == nil {
+		t.Fatalf("expected a token from another issuer to be rejected")
+	}
+
+	useTokenSettings(t, TokenSettings{Issuer: "fitflow", Audience: "fitflow", TTL: -time.Minute})
+	expired, _ := IssueAccessToken(7, "member@example.com", 1)
+	if _, err := ParseAccessToken(expired); err == nil {
+		t.Fatalf("expected an expired token to be rejected")
+	}
+
+	// Tokens without a jti, like the ones issued before keys were configurable, are rejected.
+	devSecret := []byte(strings.Repeat("d", tokens.MinHMACSecretLength))
+	useDevSecret(t, devSecret)
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id": 7, "role_id": 1, "iss": "fitflow", "aud": "fitflow",
+		"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	legacy.Header["kid"] = DevJWTKeyID
+	signed, _ := legacy.SignedString(devSecret)
+	useTokenSettings(t, DefaultTokenSettings)
+	if _, err := ParseAccessToken(signed); err == nil {
+		t.Fatalf("expected a token without a jti to be rejected")
+	}
+}
+
+func TestAccessToken_RotatedKeyStillVerifies(t *testing.T) {
+	devSecret := []byte(strings.Repeat("d", tokens.MinHMACSecretLength))
+	useDevSecret(t, devSecret)
+
+	issued, _ := IssueAccessToken(3, "staff@example.com", 3)
+
+	next, err := tokens.NewHMACKey("next", []byte(strings.Repeat("n", tokens.MinHMACSecretLength)))
+	if err != nil {
+		t.Fatalf("failed to build key: %v", err)
+	}
+	dev, _ := tokens.NewHMACKey(DevJWTKeyID, devSecret)
+	rotated, _ := tokens.NewKeyring("next", dev, next)
+	SetTokenKeys(rotated)
+
+	if _, err := ParseAccessToken(issued); err != nil {
+		t.Fatalf("expected a token of the previous key to verify, got %v", err)
+	}
+
+	onlyNext, _ := tokens.NewKeyring("next", next)
+	SetTokenKeys(onlyNext)
+	if _, err := ParseAccessToken(issued); err == nil {
+		t.Fatalf("expected a token of a retired key to be rejected")
+	}
+}
+
+func TestNewDevKeyring_IsRandomPerProcess(t *testing.T) {
+	SetTokenKeys(NewDevKeyring())
+	t.Cleanup(func() { SetTokenKeys(NewDevKeyring()) })
+	issued, _ := IssueAccessToken(3, "staff@example.com", 3)
+
+	SetTokenKeys(NewDevKeyring())
+	if _, err := ParseAccessToken(issued); err == nil {
+		t.Fatalf("expected a token of another dev keyring to be rejected")
+	}
+}
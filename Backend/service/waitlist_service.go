@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"my-course-backend/dao"
+	"my-course-backend/model"
+	"time"
+)
+
+// JoinWaitlist queues a user for the next session of a full course.
+// The same window, duplicate and overlap rules as RegisterClass apply. The credit is held
+// while waiting and refunded if the member leaves the queue or no seat opens.
+func JoinWaitlist(userID uint, courseID uint) (*model.Enrollment, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	class, err := dao.GetCourseByID(courseID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+
+	now := time.Now()
+	if err := checkMemberStanding(userID, now); err != nil {
+		return nil, err
+	}
+
+	policy := courseBookingPolicy(class)
+	if err := validateEnrollmentWindow(class, policy, now); err != nil {
+		return nil, err
+	}
+
+	exists, err := dao.CheckEnrollmentExists(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("enrollment already exists")
+	}
+
+	hasOverlap, err := hasScheduleOverlap(userID, class)
+	if err != nil {
+		return nil, err
+	}
+	if hasOverlap {
+		return nil, errors.New("class schedule overlaps with an existing enrolled class")
+	}
+
+	count, err := dao.CountEnrollmentsByClass(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if int(count) < class.Capacity {
+		return nil, errors.New("class is not full")
+	}
+
+	session, err := dao.GetNextScheduledSession(courseID)
+	if err != nil {
+		return nil, errors.New("no upcoming session found for this class")
+	}
+
+	return queueForSession(userID, session)
+}
+
+// JoinSessionWaitlist queues a user for a specific full session.
+// The same window, duplicate and overlap rules as RegisterSession apply; the credit is held as for JoinWaitlist.
+func JoinSessionWaitlist(userID uint, sessionID uint) (*model.Enrollment, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	session, err := loadBookableSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkMemberStanding(userID, now); err != nil {
+		return nil, err
+	}
+
+	policy := courseBookingPolicy(&session.Course)
+	if err := validateSessionWindow(session, policy, now); err != nil {
+		return nil, err
+	}
+
+	exists, err := dao.CheckSessionEnrollmentExists(userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("enrollment already exists")
+	}
+
+	hasOverlap, err := hasSessionOverlap(userID, session)
+	if err != nil {
+		return nil, err
+	}
+	if hasOverlap {
+		return nil, errors.New("class schedule overlaps with an existing enrolled class")
+	}
+
+	count, err := dao.CountEnrollmentsBySession(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if int(count) < sessionCapacity(session) {
+		return nil, errors.New("class is not full")
+	}
+
+	return queueForSession(userID, session)
+}
+
+// queueForSession adds the user to the end of a session's waitlist and holds a credit for the entry.
+func queueForSession(userID uint, session *model.ClassSession) (*model.Enrollment, error) {
+	if err := checkEntitlement(userID); err != nil {
+		return nil, err
+	}
+
+	enrollment := model.Enrollment{
+		UserID:    userID,
+		CourseID:  session.CourseID,
+		SessionID: &session.ID,
+	}
+	if err := dao.CreateWaitlistEnrollment(&enrollment); err != nil {
+		return nil, err
+	}
+	if err := chargeBooking(&enrollment); err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// releaseSeat promotes the waitlist of the session a removed enrollment was seated in.
+func releaseSeat(removed *model.Enrollment) error {
+	if removed.Status != model.EnrollmentStatusEnrolled || removed.SessionID == nil {
+		return nil
+	}
+
+	session, err := dao.GetSessionByID(*removed.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return promoteSessionWaitlist(session)
+}
+
+// waitlistPromotion is a session whose waitlist moves up into newly opened seats.
+type waitlistPromotion struct {
+	session    *model.ClassSession
+	passedOver []uint
+}
+
+// planCourseWaitlistPromotions prepares filling the seats a course capacity increase opens in its upcoming
+// sessions. Sessions that followed the previous course capacity are given the new one, as
+// UpdateScheduledSessionCapacityTx does when the promotions are applied.
+func planCourseWaitlistPromotions(course *model.Course, previousCapacity int) ([]waitlistPromotion, error) {
+	sessionIDs, err := dao.ListWaitlistedSessionIDsByCourse(course.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := make([]waitlistPromotion, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := dao.GetSessionByID(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.Capacity == previousCapacity || session.Capacity == 0 {
+			session.Capacity = course.Capacity
+		}
+		session.Course.Capacity = course.Capacity
+
+		queue, err := dao.ListSessionWaitlist(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		passedOver, err := passedOverWaitlist(session, queue)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, waitlistPromotion{session: session, passedOver: passedOver})
+	}
+	return promotions, nil
+}
+
+// promoteSessionWaitlist fills open seats of a session from its waitlist in queue order.
+func promoteSessionWaitlist(session *model.ClassSession) error {
+	queue, err := dao.ListSessionWaitlist(session.ID)
+	if err != nil || len(queue) == 0 {
+		return err
+	}
+
+	passedOver, err := passedOverWaitlist(session, queue)
+	if err != nil {
+		return err
+	}
+	_, err = dao.PromoteWaitlistedEnrollments(session.ID, sessionCapacity(session), passedOver)
+	return err
+}
+
+// passedOverWaitlist returns the queue entries that cannot take a seat in the session. Members who are
+// suspended, or now seated in an overlapping session, are passed over and keep their place in the queue.
+func passedOverWaitlist(session *model.ClassSession, queue []model.Enrollment) ([]uint, error) {
+	now := time.Now()
+	var passedOver []uint
+	for i := range queue {
+		if err := checkMemberStanding(queue[i].UserID, now); err != nil {
+			var policyErr *BookingPolicyError
+			if !errors.As(err, &policyErr) {
+				return nil, err
+			}
+			passedOver = append(passedOver, queue[i].ID)
+			continue
+		}
+
+		hasOverlap, err := hasSessionOverlap(queue[i].UserID, session)
+		if err != nil {
+			return nil, err
+		}
+		if hasOverlap {
+			passedOver = append(passedOver, queue[i].ID)
+		}
+	}
+	return passedOver, nil
+}
+
+// ListUserWaitlist returns the user's waitlisted enrollments with their queue positions.
+func ListUserWaitlist(userID uint) ([]model.Enrollment, error) {
+	if _, err := dao.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return dao.ListWaitlistByUser(userID)
+}
+
+// ListClassWaitlist returns the waitlist of a course's upcoming sessions.
+func ListClassWaitlist(courseID uint) ([]model.Enrollment, error) {
+	if _, err := dao.GetCourseByID(courseID); err != nil {
+		return nil, errors.New("class not found")
+	}
+	return dao.ListWaitlistByCourse(courseID)
+}
+
+// ListInstructorCourseWaitlist returns the waitlist of a course taught by the instructor.
+// Substitutes only see the waitlists of the sessions they cover.
+func ListInstructorCourseWaitlist(instructorID uint, courseID uint) ([]model.Enrollment, error) {
+	_, access, err := resolveCourseAccess(instructorID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	waitlist, err := dao.ListWaitlistByCourse(courseID)
+	if err != nil || access.full {
+		return waitlist, err
+	}
+
+	covered := make([]model.Enrollment, 0, len(waitlist))
+	for _, e := range waitlist {
+		if e.SessionID != nil && access.covers(*e.SessionID) {
+			covered = append(covered, e)
+		}
+	}
+	return covered, nil
+}
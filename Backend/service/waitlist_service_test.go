@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"my-course-backend/dao"
+	"my-course-backend/db"
+	"my-course-backend/model"
+)
+
+// seedUserWithRole creates another user for an already-seeded role.
+func seedUserWithRole(t *testing.T, roleID uint) model.User {
+	t.Helper()
+
+	user := model.User{
+		Name:     "Waitlist User",
+		Email:    fmt.Sprintf("waitlist-%d-%d@example.com", time.Now().UnixNano(), atomic.AddUint64(&testSeq, 1)),
+		Password: "secret",
+		RoleID:   roleID,
+	}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	return user
+}
+
+func loadEnrollment(t *testing.T, userID uint, courseID uint) model.Enrollment {
+	t.Helper()
+
+	var enrollment model.Enrollment
+	if err := db.DB.Where("user_id = ? AND course_id = ?", userID, courseID).First(&enrollment).Error; err != nil {
+		t.Fatalf("failed to load enrollment: %v", err)
+	}
+	return enrollment
+}
+
+func TestJoinWaitlist_AssignsQueuePositions(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	second := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Boxing", 1, "Combat")
+	seedEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	entry, err := JoinWaitlist(first.ID, course.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if entry.Status != model.EnrollmentStatusWaitlisted || entry.WaitlistPosition == nil || *entry.WaitlistPosition != 1 {
+		t.Fatalf("expected waitlist position 1, got status=%s position=%v", entry.Status, entry.WaitlistPosition)
+	}
+
+	entry, err = JoinWaitlist(second.ID, course.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if entry.WaitlistPosition == nil || *entry.WaitlistPosition != 2 {
+		t.Fatalf("expected waitlist position 2, got %v", entry.WaitlistPosition)
+	}
+
+	if _, err := JoinWaitlist(second.ID, course.ID); err == nil || err.Error() != "enrollment already exists" {
+		t.Fatalf("expected enrollment already exists, got: %v", err)
+	}
+
+	// Waitlisted entries must not consume seats.
+	class, err := GetClass(course.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if class.Spot != 0 {
+		t.Fatalf("expected spot 0, got %d", class.Spot)
+	}
+}
+
+func TestJoinWaitlist_ClassNotFull(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	user := seedRoleAndUser(t, 1)
+	course := seedCourse(t, "Yoga", 2, "Wellness")
+
+	_, err := JoinWaitlist(user.ID, course.ID)
+	if err == nil || err.Error() != "class is not full" {
+		t.Fatalf("expected class is not full, got: %v", err)
+	}
+}
+
+func TestDropClass_PromotesFirstWaitlisted(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	second := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 1, "Cardio")
+	seedEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if _, err := JoinWaitlist(first.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+	if _, err := JoinWaitlist(second.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+
+	if err := DropClass(seated.ID, course.ID); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	promoted := loadEnrollment(t, first.ID, course.ID)
+	if promoted.Status != model.EnrollmentStatusEnrolled || promoted.WaitlistPosition != nil {
+		t.Fatalf("expected first waitlisted user promoted, got status=%s position=%v", promoted.Status, promoted.WaitlistPosition)
+	}
+
+	waiting := loadEnrollment(t, second.ID, course.ID)
+	if waiting.Status != model.EnrollmentStatusWaitlisted || waiting.WaitlistPosition == nil || *waiting.WaitlistPosition != 1 {
+		t.Fatalf("expected second user at position 1, got status=%s position=%v", waiting.Status, waiting.WaitlistPosition)
+	}
+}
+
+func TestDropClass_LeavesWaitlistAndCompactsQueue(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	second := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Rowing", 1, "Cardio")
+	seedEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if _, err := JoinWaitlist(first.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+	if _, err := JoinWaitlist(second.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+
+	if err := DropClass(first.ID, course.ID); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	if still := loadEnrollment(t, seated.ID, course.ID); still.Status != model.EnrollmentStatusEnrolled {
+		t.Fatalf("expected seated user to stay enrolled, got %s", still.Status)
+	}
+
+	waiting := loadEnrollment(t, second.ID, course.ID)
+	if waiting.WaitlistPosition == nil || *waiting.WaitlistPosition != 1 {
+		t.Fatalf("expected second user moved to position 1, got %v", waiting.WaitlistPosition)
+	}
+}
+
+func TestManagerUpdateCourse_CapacityIncreasePromotesWaitlist(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	first := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Pilates", 1, "Core")
+	seedEnrollmentAt(t, seated.ID, course.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if _, err := JoinWaitlist(first.ID, course.ID); err != nil {
+		t.Fatalf("failed to join waitlist: %v", err)
+	}
+
+	_, err := ManagerUpdateCourse(course.ID, CourseUpsertInput{
+		CourseName: course.CourseName,
+		CourseCode: course.CourseCode,
+		StartTime:  course.StartTime.Format("15:04"),
+		EndTime:    course.EndTime.Format("15:04"),
+		Capacity:   2,
+		Category:   course.Category,
+		Weekday:    course.Weekday,
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	promoted := loadEnrollment(t, first.ID, course.ID)
+	if promoted.Status != model.EnrollmentStatusEnrolled {
+		t.Fatalf("expected waitlisted user promoted after capacity increase, got %s", promoted.Status)
+	}
+}
+
+func TestPromoteWaitlistedEnrollments_IgnoresCanceledEnrollments(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	canceled := seedRoleAndUser(t, 1)
+	waiting := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 1, "Cardio")
+	session := seedSessionAt(t, course, time.Now().Add(20*time.Hour), 1)
+	seedEnrollmentForSession(t, canceled.ID, course.ID, session.ID, model.EnrollmentStatusCanceled, time.Now())
+	seedEnrollmentForSession(t, waiting.ID, course.ID, session.ID, model.EnrollmentStatusWaitlisted, time.Now())
+
+	promoted, err := dao.PromoteWaitlistedEnrollments(session.ID, sessionCapacity(&session), nil)
+	if err != nil || len(promoted) != 1 || promoted[0].UserID != waiting.ID {
+		t.Fatalf("expected the waitlisted member promoted into the free seat, got %+v (err %v)", promoted, err)
+	}
+}
+
+func TestJoinSessionWaitlist_QueuesForThatSession(t *testing.T) {
+	setupClassServiceTestDB(t)
+
+	seated := seedRoleAndUser(t, 1)
+	user := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Spin", 1, "Cardio")
+	next := seedSessionAt(t, course, time.Now().Add(20*time.Hour), 1)
+	later := seedSessionAt(t, course, time.Now().Add(22*time.Hour), 1)
+	seedEnrollmentForSession(t, seated.ID, course.ID, later.ID, model.EnrollmentStatusEnrolled, time.Now())
+
+	if _, err := JoinSessionWaitlist(user.ID, next.ID); err == nil || err.Error() != "class is not full" {
+		t.Fatalf("expected class is not full for the open session, got: %v", err)
+	}
+
+	entry, err := JoinSessionWaitlist(user.ID, later.ID)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if entry.SessionID == nil || *entry.SessionID != later.ID || entry.WaitlistPosition == nil || *entry.WaitlistPosition != 1 {
+		t.Fatalf("expected position 1 for session %d, got %+v", later.ID, entry)
+	}
+
+	if _, err := JoinSessionWaitlist(user.ID, later.ID); err == nil || err.Error() != "enrollment already exists" {
+		t.Fatalf("expected enrollment already exists, got: %v", err)
+	}
+}
+
+func TestPromoteSessionWaitlist_PassesOverIneligibleMembers(t *testing.T) {
+	setupClassServiceTestDB(t)
+	useStrikeSettings(t, StrikeSettings{Limit: 1, Window: 30 * 24 * time.Hour})
+
+	seated := seedRoleAndUser(t, 1)
+	overlapping := seedUserWithRole(t, 1)
+	suspended := seedUserWithRole(t, 1)
+	eligible := seedUserWithRole(t, 1)
+	course := seedCourse(t, "Pilates", 1, "Core")
+	other := seedCourse(t, "Barre", 5, "Core")
+	startAt := time.Now().Add(20 * time.Hour)
+	session := seedSessionAt(t, course, startAt, 1)
+	clash := seedSessionAt(t, other, startAt, 5)
+
+	seedEnrollmentForSession(t, seated.ID, course.ID, session.ID, model.EnrollmentStatusEnrolled, time.Now())
+	seedEnrollmentForSession(t, overlapping.ID, other.ID, clash.ID, model.EnrollmentStatusEnrolled, time.Now())
+	for _, user := range []model.User{overlapping, suspended, eligible} {
+		seedEnrollmentForSession(t, user.ID, course.ID, session.ID, model.EnrollmentStatusWaitlisted, time.Now())
+	}
+	if err := db.DB.Create(&model.MemberStrike{UserID: suspended.ID, CourseID: course.ID, Reason: model.StrikeReasonNoShow, OccurredAt: time.Now()}).Error; err != nil {
+		t.Fatalf("failed to seed strike: %v", err)
+	}
+
+	session.Capacity = 2
+	if err := promoteSessionWaitlist(&session); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	if promoted := loadEnrollment(t, eligible.ID, course.ID); promoted.Status != model.EnrollmentStatusEnrolled {
+		t.Fatalf("expected the eligible member promoted, got %s", promoted.Status)
+	}
+	for position, user := range []model.User{overlapping, suspended} {
+		entry := loadEnrollment(t, user.ID, course.ID)
+		if entry.Status != model.EnrollmentStatusWaitlisted || entry.WaitlistPosition == nil || *entry.WaitlistPosition != position+1 {
+			t.Fatalf("expected user %d to keep place %d, got status=%s position=%v", user.ID, position+1, entry.Status, entry.WaitlistPosition)
+		}
+	}
+}
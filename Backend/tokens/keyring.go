@@ -0,0 +1,159 @@
+// Package tokens holds the keys FitFlow signs and verifies its access tokens with. A Keyring signs
+// new tokens with one key and verifies tokens signed by any of its keys, picked by the token's kid
+// header, so keys can be rotated without logging everyone out. Asymmetric keys are published as a
+// JWKS so other services can verify FitFlow tokens without sharing a secret.
+package tokens
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// MinHMACSecretLength is the shortest accepted HS256 secret, in bytes.
+const MinHMACSecretLength = 32
+
+// Key is one signing key identified by its kid.
+type Key struct {
+	ID     string
+	method jwt.SigningMethod
+	// signKey signs new tokens; verifyKey checks them. For HS256 both are the secret.
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// Algorithm returns the key's JWT algorithm name.
+func (k *Key) Algorithm() string { return k.method.Alg() }
+
+// NewHMACKey returns an HS256 key. HS256 keys can only be verified by holders of the secret
+// and are never published in the JWKS.
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if err := checkKeyID(id); err != nil {
+		return nil, err
+	}
+	if len(secret) < MinHMACSecretLength {
+		return nil, fmt.Errorf("key %s: HS256 secret must be at least %d bytes", id, MinHMACSecretLength)
+	}
+	return &Key{ID: id, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// ParseKey builds a key of the given algorithm: the raw secret for HS256, a PEM-encoded
+// private key for RS256 (PKCS#1 or PKCS#8) and EdDSA (PKCS#8 Ed25519).
+func ParseKey(id string, alg string, material []byte) (*Key, error) {
+	if err := checkKeyID(id); err != nil {
+		return nil, err
+	}
+	switch alg {
+	case AlgHS256:
+		return NewHMACKey(id, []byte(strings.TrimSpace(string(material))))
+	case AlgRS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		if private.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("key %s: RSA keys must be at least 2048 bits", id)
+		}
+		return &Key{ID: id, method: jwt.SigningMethodRS256, signKey: private, verifyKey: &private.PublicKey}, nil
+	case AlgEdDSA:
+		private, err := jwt.ParseEdPrivateKeyFromPEM(material)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		signer, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key %s: not an Ed25519 key", id)
+		}
+		return &Key{ID: id, method: jwt.SigningMethodEdDSA, signKey: signer, verifyKey: signer.Public()}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported algorithm %q", id, alg)
+	}
+}
+
+func checkKeyID(id string) error {
+	if strings.TrimSpace(id) == "" || strings.ContainsAny(id, " ,:") {
+		return fmt.Errorf("invalid key id %q", id)
+	}
+	return nil
+}
+
+// Keyring signs with one key and verifies with all of them.
+type Keyring struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeyring returns a keyring signing with the key whose ID is signingID.
+func NewKeyring(signingID string, keys ...*Key) (*Keyring, error) {
+	ring := &Keyring{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, taken := ring.keys[key.ID]; taken {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ring.keys[key.ID] = key
+	}
+	ring.signing = ring.keys[signingID]
+	if ring.signing == nil {
+		return nil, fmt.Errorf("signing key %q is not in the keyring", signingID)
+	}
+	return ring, nil
+}
+
+// SigningKeyID returns the kid new tokens are signed with.
+func (k *Keyring) SigningKeyID() string { return k.signing.ID }
+
+// Sign signs claims with the signing key and sets the kid header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.ID
+	return token.SignedString(k.signing.signKey)
+}
+
+// Keyfunc resolves the verification key of a token from its kid header, for jwt.Parse.
+// The token's algorithm must match the key's, so a public key can never be used as an HMAC secret.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected signing algorithm")
+	}
+	return key.verifyKey, nil
+}
+
+// Algorithms returns the algorithms of the keyring's keys, for jwt.WithValidMethods.
+func (k *Keyring) Algorithms() []string {
+	seen := map[string]bool{}
+	var algs []string
+	for _, key := range k.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// sortedKeys returns the keys ordered by ID so the JWKS is stable.
+func (k *Keyring) sortedKeys() []*Key {
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
@@ -0,0 +1,129 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func pemKey(t *testing.T, private interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func verify(ring *Keyring, token string) error {
+	_, err := jwt.Parse(token, ring.Keyfunc, jwt.WithValidMethods(ring.Algorithms()))
+	return err
+}
+
+func TestKeyring_RotationKeepsOldTokensValid(t *testing.T) {
+	old, err := NewHMACKey("2026-01", []byte(strings.Repeat("a", MinHMACSecretLength)))
+	if err != nil {
+		t.Fatalf("failed to build key: %v", err)
+	}
+	oldRing, _ := NewKeyring("2026-01", old)
+	issued, err := oldRing.Sign(jwt.MapClaims{"sub": "1"})
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	next, err := ParseKey("2026-02", AlgEdDSA, pemKey(t, private))
+	if err != nil {
+		t.Fatalf("failed to parse EdDSA key: %v", err)
+	}
+	rotated, err := NewKeyring("2026-02", old, next)
+	if err != nil {
+		t.Fatalf("failed to build keyring: %v", err)
+	}
+	if err := verify(rotated, issued); err != nil {
+		t.Fatalf("expected a token of the retired key to verify, got %v", err)
+	}
+
+	fresh, _ := rotated.Sign(jwt.MapClaims{"sub": "1"})
+	parsed, _, _ := jwt.NewParser().ParseUnverified(fresh, jwt.MapClaims{})
+	if parsed.Header["kid"] != "2026-02" || parsed.Method.Alg() != AlgEdDSA {
+		t.Fatalf("expected new tokens signed by 2026-02 with EdDSA, got %v", parsed.Header)
+	}
+	if err := verify(rotated, fresh); err != nil {
+		t.Fatalf("expected the new token to verify, got %v", err)
+	}
+	if err := verify(oldRing, fresh); err == nil {
+		t.Fatalf("expected a keyring without 2026-02 to reject the token")
+	}
+}
+
+func TestKeyring_RejectsAlgorithmConfusion(t *testing.T) {
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaKey, err := ParseKey("rsa", AlgRS256, pemKey(t, private))
+	if err != nil {
+		t.Fatalf("failed to parse RSA key: %v", err)
+	}
+	ring, _ := NewKeyring("rsa", rsaKey)
+
+	// An attacker signs an HS256 token with the published public key as the secret.
+	public, _ := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1"})
+	forged.Header["kid"] = "rsa"
+	signed, _ := forged.SignedString(public)
+	if err := verify(ring, signed); err == nil {
+		t.Fatalf("expected an HS256 token to be rejected by an RS256 key")
+	}
+
+	missing := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "1"})
+	signed, _ = missing.SignedString(private)
+	if err := verify(ring, signed); err == nil {
+		t.Fatalf("expected a token without a kid to be rejected")
+	}
+}
+
+func TestParseKey_ValidatesMaterial(t *testing.T) {
+	if _, err := ParseKey("short", AlgHS256, []byte("too-short")); err == nil {
+		t.Fatalf("expected a short HS256 secret to be rejected")
+	}
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	if _, err := ParseKey("weak", AlgRS256, pemKey(t, weak)); err == nil {
+		t.Fatalf("expected a 1024-bit RSA key to be rejected")
+	}
+	if _, err := ParseKey("none", "none", nil); err == nil {
+		t.Fatalf("expected an unsupported algorithm to be rejected")
+	}
+	if _, err := ParseKey("a:b", AlgHS256, []byte(strings.Repeat("a", MinHMACSecretLength))); err == nil {
+		t.Fatalf("expected a kid containing ':' to be rejected")
+	}
+}
+
+func TestKeyring_JWKSPublishesOnlyPublicKeys(t *testing.T) {
+	secret, _ := NewHMACKey("hmac", []byte(strings.Repeat("s", MinHMACSecretLength)))
+	rsaPrivate, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaKey, _ := ParseKey("rsa", AlgRS256, pemKey(t, rsaPrivate))
+	_, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	edKey, _ := ParseKey("ed", AlgEdDSA, pemKey(t, edPrivate))
+
+	ring, err := NewKeyring("rsa", secret, rsaKey, edKey)
+	if err != nil {
+		t.Fatalf("failed to build keyring: %v", err)
+	}
+
+	set := ring.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected the RSA and Ed25519 keys only, got %+v", set.Keys)
+	}
+	ed, rsaJWK := set.Keys[0], set.Keys[1]
+	if ed.Kid != "ed" || ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != AlgEdDSA || ed.X == "" {
+		t.Fatalf("unexpected Ed25519 JWK %+v", ed)
+	}
+	if rsaJWK.Kid != "rsa" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != AlgRS256 || rsaJWK.E != "AQAB" || rsaJWK.N == "" {
+		t.Fatalf("unexpected RSA JWK %+v", rsaJWK)
+	}
+}